@@ -0,0 +1,386 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	cmd2 "github.com/axellelanca/urlshortener/cmd"
+	"github.com/axellelanca/urlshortener/internal/models"
+	"github.com/axellelanca/urlshortener/internal/repository"
+	"github.com/axellelanca/urlshortener/internal/services"
+	"github.com/spf13/cobra"
+
+	// "github.com/glebarez/sqlite" WINDOWS
+	"gorm.io/driver/sqlite" // MAC
+	"gorm.io/gorm"
+)
+
+// Flags des sous-commandes de campaign
+var (
+	campaignIDFlag          uint
+	campaignNameFlag        string
+	campaignDescriptionFlag string
+	campaignStartsAtFlag    string
+	campaignEndsAtFlag      string
+	campaignCodesFlag       []string
+	campaignFromFlag        string
+	campaignToFlag          string
+)
+
+// CampaignCmd représente le groupe de commandes 'campaign'
+var CampaignCmd = &cobra.Command{
+	Use:   "campaign",
+	Short: "Gère les campagnes (groupes de liens aux statistiques agrégées).",
+	Long: `Une campagne regroupe des liens courts, avec une période optionnelle,
+pour en consulter les statistiques agrégées.
+
+Exemple:
+  url-shortener campaign create --name="Rentrée 2025" --starts-at=2025-09-01 --ends-at=2025-10-01
+  url-shortener campaign add --id=1 --code=aB3Xy9 --code=xyz123
+  url-shortener campaign stats --id=1`,
+}
+
+// campaignCreateCmd représente la commande 'campaign create'
+var campaignCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Crée une campagne.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if campaignNameFlag == "" {
+			log.Fatal("FATAL: Le flag --name est requis")
+		}
+		input := services.CampaignInput{
+			Name:        campaignNameFlag,
+			Description: campaignDescriptionFlag,
+			StartsAt:    parseCampaignDate("starts-at", campaignStartsAtFlag),
+			EndsAt:      parseCampaignDate("ends-at", campaignEndsAtFlag),
+		}
+
+		campaignService, closeDB := openCampaignService()
+		defer closeDB()
+
+		campaign, err := campaignService.CreateCampaign(input)
+		if err != nil {
+			exitOnCampaignError(err)
+		}
+
+		fmt.Printf("Campagne créée avec succès:\n")
+		printCampaign(campaign)
+		fmt.Println()
+	},
+}
+
+// campaignListCmd représente la commande 'campaign list'
+var campaignListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Liste les campagnes.",
+	Run: func(cmd *cobra.Command, args []string) {
+		campaignService, closeDB := openCampaignService()
+		defer closeDB()
+
+		campaigns, err := campaignService.ListCampaigns()
+		if err != nil {
+			log.Fatalf("FATAL: Erreur lors du listage des campagnes: %v", err)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tNOM\tLIENS\tDÉBUT\tFIN")
+		for _, campaign := range campaigns {
+			fmt.Fprintf(w, "%d\t%s\t%d\t%s\t%s\n",
+				campaign.ID, campaign.Name, campaign.LinkCount, formatCampaignDate(campaign.StartsAt), formatCampaignDate(campaign.EndsAt))
+		}
+		w.Flush()
+		fmt.Println()
+	},
+}
+
+// campaignShowCmd représente la commande 'campaign show'
+var campaignShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Affiche une campagne et ses liens.",
+	Run: func(cmd *cobra.Command, args []string) {
+		requireCampaignID()
+
+		campaignService, closeDB := openCampaignService()
+		defer closeDB()
+
+		campaign, err := campaignService.GetCampaign(campaignIDFlag)
+		if err != nil {
+			exitOnCampaignError(err)
+		}
+
+		printCampaign(campaign)
+		printCampaignLinks(campaign.Links)
+	},
+}
+
+// campaignUpdateCmd représente la commande 'campaign update'
+var campaignUpdateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Modifie le nom, la description ou la période d'une campagne.",
+	Long: `Seuls les flags fournis sont modifiés. Une date vide retire la borne correspondante.
+
+Exemple:
+  url-shortener campaign update --id=1 --ends-at=2025-10-15
+  url-shortener campaign update --id=1 --ends-at=""`,
+	Run: func(cmd *cobra.Command, args []string) {
+		requireCampaignID()
+
+		campaignService, closeDB := openCampaignService()
+		defer closeDB()
+
+		campaign, err := campaignService.GetCampaign(campaignIDFlag)
+		if err != nil {
+			exitOnCampaignError(err)
+		}
+		input := services.CampaignInput{
+			Name:        campaign.Name,
+			Description: campaign.Description,
+			StartsAt:    campaign.StartsAt,
+			EndsAt:      campaign.EndsAt,
+		}
+		if cmd.Flags().Changed("name") {
+			input.Name = campaignNameFlag
+		}
+		if cmd.Flags().Changed("description") {
+			input.Description = campaignDescriptionFlag
+		}
+		if cmd.Flags().Changed("starts-at") {
+			input.StartsAt = parseCampaignDate("starts-at", campaignStartsAtFlag)
+		}
+		if cmd.Flags().Changed("ends-at") {
+			input.EndsAt = parseCampaignDate("ends-at", campaignEndsAtFlag)
+		}
+
+		campaign, err = campaignService.UpdateCampaign(campaignIDFlag, input)
+		if err != nil {
+			exitOnCampaignError(err)
+		}
+
+		fmt.Printf("Campagne modifiée avec succès:\n")
+		printCampaign(campaign)
+		fmt.Println()
+	},
+}
+
+// campaignDeleteCmd représente la commande 'campaign delete'
+var campaignDeleteCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "Supprime une campagne (ses liens sont conservés).",
+	Run: func(cmd *cobra.Command, args []string) {
+		requireCampaignID()
+
+		campaignService, closeDB := openCampaignService()
+		defer closeDB()
+
+		campaign, err := campaignService.DeleteCampaign(campaignIDFlag)
+		if err != nil {
+			exitOnCampaignError(err)
+		}
+
+		fmt.Printf("Campagne '%s' supprimée. Ses liens sont conservés.\n\n", campaign.Name)
+	},
+}
+
+// campaignAddCmd représente la commande 'campaign add'
+var campaignAddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Rattache des liens à une campagne.",
+	Long: `Un lien appartient au plus à une campagne : un lien d'une autre campagne y est déplacé.
+
+Exemple:
+  url-shortener campaign add --id=1 --code=aB3Xy9,xyz123`,
+	Run: func(cmd *cobra.Command, args []string) {
+		requireCampaignID()
+		if len(campaignCodesFlag) == 0 {
+			log.Fatal("FATAL: Le flag --code est requis")
+		}
+
+		campaignService, closeDB := openCampaignService()
+		defer closeDB()
+
+		campaign, err := campaignService.AddLinks(campaignIDFlag, campaignCodesFlag)
+		if err != nil {
+			exitOnCampaignError(err)
+		}
+
+		fmt.Printf("Liens ajoutés à la campagne '%s'.\n", campaign.Name)
+		printCampaignLinks(campaign.Links)
+	},
+}
+
+// campaignRemoveCmd représente la commande 'campaign remove'
+var campaignRemoveCmd = &cobra.Command{
+	Use:   "remove",
+	Short: "Retire des liens d'une campagne.",
+	Run: func(cmd *cobra.Command, args []string) {
+		requireCampaignID()
+		if len(campaignCodesFlag) == 0 {
+			log.Fatal("FATAL: Le flag --code est requis")
+		}
+
+		campaignService, closeDB := openCampaignService()
+		defer closeDB()
+
+		campaign, err := campaignService.RemoveLinks(campaignIDFlag, campaignCodesFlag)
+		if err != nil {
+			exitOnCampaignError(err)
+		}
+
+		fmt.Printf("Liens retirés de la campagne '%s'.\n", campaign.Name)
+		printCampaignLinks(campaign.Links)
+	},
+}
+
+// campaignStatsCmd représente la commande 'campaign stats'
+var campaignStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Affiche les statistiques agrégées d'une campagne.",
+	Long: `Les clics sont comptés sur la période de la campagne, sauf si --from ou --to sont fournis.
+
+Exemple:
+  url-shortener campaign stats --id=1
+  url-shortener campaign stats --id=1 --from=2025-09-01 --to=2025-09-08`,
+	Run: func(cmd *cobra.Command, args []string) {
+		requireCampaignID()
+		since := parseCampaignDate("from", campaignFromFlag)
+		until := parseCampaignDate("to", campaignToFlag)
+
+		campaignService, closeDB := openCampaignService()
+		defer closeDB()
+
+		stats, err := campaignService.GetCampaignStats(campaignIDFlag, since, until)
+		if err != nil {
+			exitOnCampaignError(err)
+		}
+
+		fmt.Printf("Statistiques de la campagne: %s\n", stats.Campaign.Name)
+		fmt.Printf("Période: %s → %s\n", formatCampaignDate(stats.Since), formatCampaignDate(stats.Until))
+		fmt.Printf("Nombre de liens: %d\n", len(stats.Links))
+		fmt.Printf("Total de clics: %d\n", stats.TotalClicks)
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "\nCODE\tCLICS")
+		for _, item := range stats.Links {
+			fmt.Fprintf(w, "%s\t%d\n", item.Link.Shortcode, item.Clicks)
+		}
+		if len(stats.Daily) > 0 {
+			fmt.Fprintln(w, "\nJOUR\tCLICS")
+			for _, day := range stats.Daily {
+				fmt.Fprintf(w, "%s\t%d\n", day.Day, day.Clicks)
+			}
+		}
+		w.Flush()
+		fmt.Println()
+	},
+}
+
+// openCampaignService ouvre la base de données et retourne le CampaignService,
+// ainsi que la fonction fermant la connexion.
+func openCampaignService() (*services.CampaignService, func()) {
+	cfg := cmd2.Cfg
+	if cfg == nil {
+		log.Fatal("FATAL: Configuration non chargée")
+	}
+
+	db, err := gorm.Open(sqlite.Open(cfg.Database.Name), &gorm.Config{})
+	if err != nil {
+		log.Fatalf("FATAL: Échec de la connexion à la base de données: %v", err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		log.Fatalf("FATAL: Échec de l'obtention de la base de données SQL sous-jacente: %v", err)
+	}
+
+	campaignRepo := repository.NewCampaignRepository(db)
+	clickRepo := repository.NewClickRepository(db)
+	return services.NewCampaignService(campaignRepo, clickRepo), func() { sqlDB.Close() }
+}
+
+// requireCampaignID vérifie que le flag --id a été fourni.
+func requireCampaignID() {
+	if campaignIDFlag == 0 {
+		log.Fatal("FATAL: Le flag --id est requis")
+	}
+}
+
+// parseCampaignDate interprète la valeur d'un flag de date (RFC3339 ou YYYY-MM-DD). Une valeur vide donne nil.
+func parseCampaignDate(flag, value string) *time.Time {
+	if value == "" {
+		return nil
+	}
+	date, err := services.ParseDateFilter(value)
+	if err != nil {
+		log.Fatalf("FATAL: --%s: %v", flag, err)
+	}
+	return date
+}
+
+// formatCampaignDate affiche une borne de période, "-" si elle n'est pas définie.
+func formatCampaignDate(date *time.Time) string {
+	if date == nil {
+		return "-"
+	}
+	return date.Format("2006-01-02 15:04")
+}
+
+// exitOnCampaignError affiche l'erreur d'une opération sur une campagne et termine la commande.
+func exitOnCampaignError(err error) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Fatalf("ERREUR: Aucune campagne trouvée avec l'ID %d", campaignIDFlag)
+	}
+	if errors.Is(err, services.ErrCampaignAlreadyExists) {
+		log.Fatalf("ERREUR: Une campagne nommée '%s' existe déjà", campaignNameFlag)
+	}
+	if errors.Is(err, services.ErrInvalidCampaign) || errors.Is(err, services.ErrUnknownShortCodes) {
+		log.Fatalf("ERREUR: %v", err)
+	}
+	log.Fatalf("FATAL: Erreur lors de l'opération sur la campagne: %v", err)
+}
+
+// printCampaign affiche les informations d'une campagne.
+func printCampaign(campaign *models.Campaign) {
+	fmt.Printf("ID: %d\n", campaign.ID)
+	fmt.Printf("Nom: %s\n", campaign.Name)
+	if campaign.Description != "" {
+		fmt.Printf("Description: %s\n", campaign.Description)
+	}
+	fmt.Printf("Période: %s → %s\n", formatCampaignDate(campaign.StartsAt), formatCampaignDate(campaign.EndsAt))
+}
+
+// printCampaignLinks affiche les liens d'une campagne.
+func printCampaignLinks(links []models.Link) {
+	fmt.Printf("Liens (%d):\n", len(links))
+	now := time.Now()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, link := range links {
+		fmt.Fprintf(w, "  %s\t%s\t%s\n", link.Shortcode, link.Status(now), link.LongURL)
+	}
+	w.Flush()
+	fmt.Println()
+}
+
+func init() {
+	for _, c := range []*cobra.Command{campaignShowCmd, campaignUpdateCmd, campaignDeleteCmd, campaignAddCmd, campaignRemoveCmd, campaignStatsCmd} {
+		c.Flags().UintVar(&campaignIDFlag, "id", 0, "ID de la campagne")
+	}
+	for _, c := range []*cobra.Command{campaignCreateCmd, campaignUpdateCmd} {
+		c.Flags().StringVar(&campaignNameFlag, "name", "", "Nom de la campagne")
+		c.Flags().StringVar(&campaignDescriptionFlag, "description", "", "Description de la campagne")
+		c.Flags().StringVar(&campaignStartsAtFlag, "starts-at", "", "Début de la campagne (RFC3339 ou YYYY-MM-DD)")
+		c.Flags().StringVar(&campaignEndsAtFlag, "ends-at", "", "Fin de la campagne, exclue (RFC3339 ou YYYY-MM-DD)")
+	}
+	for _, c := range []*cobra.Command{campaignAddCmd, campaignRemoveCmd} {
+		c.Flags().StringSliceVarP(&campaignCodesFlag, "code", "c", nil, "Code court du lien (répétable ou séparé par des virgules)")
+	}
+	campaignStatsCmd.Flags().StringVar(&campaignFromFlag, "from", "", "Début de la période (par défaut: début de la campagne)")
+	campaignStatsCmd.Flags().StringVar(&campaignToFlag, "to", "", "Fin de la période, exclue (par défaut: fin de la campagne)")
+
+	CampaignCmd.AddCommand(campaignCreateCmd, campaignListCmd, campaignShowCmd, campaignUpdateCmd,
+		campaignDeleteCmd, campaignAddCmd, campaignRemoveCmd, campaignStatsCmd)
+	cmd2.RootCmd.AddCommand(CampaignCmd)
+}
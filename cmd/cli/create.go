@@ -1,9 +1,13 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	cmd2 "github.com/axellelanca/urlshortener/cmd"
 	"github.com/axellelanca/urlshortener/internal/repository"
@@ -19,6 +23,78 @@ import (
 // DONE : Faire une variable longURLFlag qui stockera la valeur du flag --url
 var longURLFlag string
 
+// aliasFlag stocke la valeur du flag --alias (code court personnalisé, optionnel)
+var aliasFlag string
+
+// Flags d'expiration (optionnels) : date absolue RFC3339 ou durée relative, et URL de repli
+var (
+	expiresAtFlag  string
+	ttlFlag        time.Duration
+	expiredURLFlag string
+)
+
+// Flags de programmation (optionnels) : date d'activation RFC3339, URL d'attente et changements de destination
+// programmés au format "date=URL" (répétable)
+var (
+	activatesAtFlag string
+	pendingURLFlag  string
+	scheduleFlags   []string
+)
+
+// passwordFlag stocke le mot de passe de protection du lien (optionnel)
+var passwordFlag string
+
+// Flags de limite de redirections (optionnels)
+var (
+	maxClicksFlag int
+	oneTimeFlag   bool
+)
+
+// Flags de métadonnées (optionnels) : titre, description, notes internes et tags
+var (
+	titleFlag       string
+	descriptionFlag string
+	notesFlag       string
+	tagsFlag        []string
+)
+
+// Flags UTM (optionnels) : preset de la configuration et valeurs explicites, prioritaires sur le preset
+var (
+	utmPresetFlag string
+	utmFlags      services.UTMParams
+)
+
+// Flags de transmission (optionnels) : query string et chemin demandé après le code court, ajoutés à la destination
+var (
+	forwardQueryFlag bool
+	forwardPathFlag  bool
+)
+
+// deviceFlags stocke les destinations par type d'appareil du flag --device (format "ios=URL", répétable)
+var deviceFlags []string
+
+// countryFlags stocke les destinations par pays du visiteur du flag --country (format "FR=URL", répétable)
+var countryFlags []string
+
+// languageFlags stocke les destinations par langue du visiteur du flag --language (format "fr=URL", répétable)
+var languageFlags []string
+
+// Flags de test A/B (optionnels) : variantes au format "id:poids=URL" (répétable) et conservation de la variante tirée
+var (
+	variantFlags      []string
+	stickyVariantFlag bool
+)
+
+// previewFlag active la page d'aperçu systématique avant la redirection (optionnel)
+var previewFlag bool
+
+// redirectStatusFlag stocke le code HTTP de redirection du lien (optionnel, 0 = code de la configuration)
+var redirectStatusFlag int
+
+// fetchDestinationFlag récupère les métadonnées de la destination après la création (optionnel,
+// destination.fetch_on_create par défaut)
+var fetchDestinationFlag bool
+
 // CreateCmd représente la commande 'create'
 var CreateCmd = &cobra.Command{
 	Use:   "create",
@@ -26,7 +102,19 @@ var CreateCmd = &cobra.Command{
 	Long: `Cette commande raccourcit une URL longue fournie et affiche le code court généré.
 
 Exemple:
-  url-shortener create --url="https://www.google.com/search?q=go+lang"`,
+  url-shortener create --url="https://www.google.com/search?q=go+lang"
+  url-shortener create --url="https://promo.site.com/black-friday" --alias="bf2025"
+  url-shortener create --url="https://event.site.com/live" --ttl=72h --expired-url="https://event.site.com"
+  url-shortener create --url="https://intranet.site.com/onboarding" --one-time
+  url-shortener create --url="https://shop.site.com/rentree" --tag campaign-q3 --tag email --title="Newsletter rentrée"
+  url-shortener create --url="https://shop.site.com/rentree?ref=home" --utm-preset=newsletter --utm-campaign=rentree
+  url-shortener create --url="https://app.site.com" --device ios=https://apps.apple.com/app/id123 --device android=https://play.google.com/store/apps/details?id=com.site
+  url-shortener create --url="https://shop.site.com" --country FR=https://shop.site.fr --country DE=https://shop.site.de
+  url-shortener create --url="https://docs.site.com" --language fr=https://docs.site.com/fr --language en=https://docs.site.com/en
+  url-shortener create --url="https://www.site.com/landing" --variant a:70=https://www.site.com/landing-a --variant b:30=https://www.site.com/landing-b --sticky-variant
+  url-shortener create --url="https://shop.site.com/soldes" --activates-at=2025-06-25T08:00:00+02:00 --pending-url="https://shop.site.com/bientot"
+  url-shortener create --url="https://event.site.com/inscription" --schedule 2025-06-01T09:00:00Z=https://event.site.com/live --schedule 2025-06-02T18:00:00Z=https://event.site.com/replay
+  url-shortener create --url="https://blog.site.com/article" --fetch-destination`,
 	Run: func(cmd *cobra.Command, args []string) {
 		// DONE: Valider que le flag --url a été fourni.
 		if longURLFlag == "" {
@@ -38,6 +126,12 @@ Exemple:
 			log.Fatalf("FATAL: URL invalide: %v", err)
 		}
 
+		if expiredURLFlag != "" {
+			if _, err := url.ParseRequestURI(expiredURLFlag); err != nil {
+				log.Fatalf("FATAL: URL de repli invalide: %v", err)
+			}
+		}
+
 		// DONE : Charger la configuration chargée globalement via cmd.Cfg
 		cfg := cmd2.Cfg
 		if cfg == nil {
@@ -60,26 +154,214 @@ Exemple:
 
 		// DONE : Initialiser les repositories et services nécessaires NewLinkRepository & NewLinkService
 		linkRepo := repository.NewLinkRepository(db)
-		linkService := services.NewLinkService(linkRepo)
+		linkService, err := services.NewLinkServiceFromConfig(linkRepo, cfg)
+		if err != nil {
+			log.Fatalf("FATAL: %v", err)
+		}
 
 		// DONE : Appeler le LinkService et la fonction CreateLink pour créer le lien court.
-		link, err := linkService.CreateLink(longURLFlag)
+		opts := services.CreateLinkOptions{
+			Alias:      aliasFlag,
+			TTL:        ttlFlag,
+			ExpiredURL: expiredURLFlag,
+			MaxClicks:  maxClicksFlag,
+			OneTime:    oneTimeFlag,
+			Password:   passwordFlag,
+
+			Title:       titleFlag,
+			Description: descriptionFlag,
+			Notes:       notesFlag,
+			Tags:        tagsFlag,
+
+			UTMPreset: utmPresetFlag,
+			UTM:       utmFlags,
+
+			ForwardQuery: forwardQueryFlag,
+			ForwardPath:  forwardPathFlag,
+
+			DeviceURLs:     parseTargetFlags("device", deviceFlags),
+			CountryURLs:    parseTargetFlags("country", countryFlags),
+			LanguageURLs:   parseTargetFlags("language", languageFlags),
+			Variants:       parseVariantFlags("variant", variantFlags),
+			StickyVariant:  stickyVariantFlag,
+			Preview:        previewFlag,
+			RedirectStatus: redirectStatusFlag,
+
+			PendingURL: pendingURLFlag,
+			Schedule:   parseScheduleFlags("schedule", scheduleFlags),
+		}
+		if expiresAtFlag != "" {
+			expiresAt, err := time.Parse(time.RFC3339, expiresAtFlag)
+			if err != nil {
+				log.Fatalf("FATAL: --expires-at doit être au format RFC3339 (ex: 2025-12-31T23:59:59Z): %v", err)
+			}
+			opts.ExpiresAt = &expiresAt
+		}
+		if activatesAtFlag != "" {
+			activatesAt, err := time.Parse(time.RFC3339, activatesAtFlag)
+			if err != nil {
+				log.Fatalf("FATAL: --activates-at doit être au format RFC3339 (ex: 2025-06-25T08:00:00+02:00): %v", err)
+			}
+			opts.ActivatesAt = &activatesAt
+		}
+		if cmd.Flags().Changed("fetch-destination") {
+			opts.FetchDestination = &fetchDestinationFlag
+		}
+
+		link, created, err := linkService.CreateLink(longURLFlag, opts)
 		if err != nil {
+			if errors.Is(err, services.ErrAliasAlreadyExists) {
+				log.Fatalf("ERREUR: L'alias '%s' est déjà utilisé", aliasFlag)
+			}
+			if errors.Is(err, services.ErrURLAlreadyExists) {
+				log.Fatalf("ERREUR: Un lien court existe déjà pour cette URL (voir 'list' ou GET /api/v1/lookup)")
+			}
+			if errors.Is(err, services.ErrInvalidMetadata) || errors.Is(err, services.ErrInvalidUTM) ||
+				errors.Is(err, services.ErrInvalidRedirectStatus) || errors.Is(err, services.ErrInvalidTarget) ||
+				errors.Is(err, services.ErrInvalidSchedule) || errors.Is(err, services.ErrInvalidExpiration) ||
+				errors.Is(err, services.ErrInvalidLongURL) {
+				log.Fatalf("ERREUR: %v", err)
+			}
 			log.Fatalf("FATAL: Erreur lors de la création du lien: %v", err)
 		}
 
 		fullShortURL := fmt.Sprintf("%s/%s", cfg.Server.BaseURL, link.Shortcode)
-		fmt.Printf("URL courte créée avec succès:\n")
+		if created {
+			fmt.Printf("URL courte créée avec succès:\n")
+		} else {
+			fmt.Printf("Un lien court existe déjà pour cette URL:\n")
+		}
 		fmt.Printf("Code: %s\n", link.Shortcode)
-		fmt.Printf("URL complète: %s\n\n", fullShortURL)
+		fmt.Printf("URL complète: %s\n", fullShortURL)
+		if link.LongURL != longURLFlag {
+			fmt.Printf("URL longue: %s\n", link.LongURL)
+		}
+		if link.ExpiresAt != nil {
+			fmt.Printf("Expire le: %s\n", link.ExpiresAt.Format(time.RFC3339))
+		}
+		if link.HasClickLimit() {
+			fmt.Printf("Clics maximum: %d\n", link.MaxClicks)
+		}
+		if link.IsProtected() {
+			fmt.Println("Protégé par mot de passe: oui")
+		}
+		if link.Title != "" {
+			fmt.Printf("Titre: %s\n", link.Title)
+		}
+		if len(link.Tags) > 0 {
+			fmt.Printf("Tags: %s\n", strings.Join(services.TagNames(link.Tags), ", "))
+		}
+		printRedirectOptions(link)
+
+		// La récupération des métadonnées de la destination se fait en arrière-plan : l'attendre avant de quitter.
+		linkService.WaitDestinationFetches()
+		if created {
+			if fetched, err := linkService.GetLinkByShortCode(link.Shortcode); err == nil {
+				printDestination(fetched.Destination)
+			}
+		}
+		fmt.Println()
 	},
 }
 
+// parseTargetFlags convertit les valeurs "clé=URL" d'un flag de destinations alternatives en map indexée par clé.
+// Les valeurs vides sont ignorées (--device="" retire toutes les destinations avec la commande update).
+func parseTargetFlags(flagName string, values []string) map[string]string {
+	urls := make(map[string]string, len(values))
+	for _, value := range values {
+		if strings.TrimSpace(value) == "" {
+			continue
+		}
+		key, targetURL, ok := strings.Cut(value, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			log.Fatalf("FATAL: --%s doit être au format clé=URL (reçu '%s')", flagName, value)
+		}
+		urls[strings.TrimSpace(key)] = targetURL
+	}
+	return urls
+}
+
+// parseVariantFlags convertit les valeurs "id:poids=URL" d'un flag de variantes A/B (poids 1 si omis).
+// Les valeurs vides sont ignorées (--variant="" met fin au test A/B avec la commande update).
+func parseVariantFlags(flagName string, values []string) []services.Variant {
+	variants := make([]services.Variant, 0, len(values))
+	for _, value := range values {
+		if strings.TrimSpace(value) == "" {
+			continue
+		}
+		key, targetURL, ok := strings.Cut(value, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			log.Fatalf("FATAL: --%s doit être au format id:poids=URL (reçu '%s')", flagName, value)
+		}
+		variant := services.Variant{ID: strings.TrimSpace(key), URL: targetURL, Weight: 1}
+		if id, weight, ok := strings.Cut(variant.ID, ":"); ok {
+			parsed, err := strconv.Atoi(strings.TrimSpace(weight))
+			if err != nil {
+				log.Fatalf("FATAL: --%s: poids invalide '%s' (entier attendu)", flagName, weight)
+			}
+			variant.ID, variant.Weight = strings.TrimSpace(id), parsed
+		}
+		variants = append(variants, variant)
+	}
+	return variants
+}
+
+// parseScheduleFlags convertit les valeurs "date=URL" d'un flag de changements de destination programmés (date RFC3339).
+// Les valeurs vides sont ignorées (--schedule="" retire tous les changements programmés avec la commande update).
+func parseScheduleFlags(flagName string, values []string) []services.ScheduledURL {
+	entries := make([]services.ScheduledURL, 0, len(values))
+	for _, value := range values {
+		if strings.TrimSpace(value) == "" {
+			continue
+		}
+		at, targetURL, ok := strings.Cut(value, "=")
+		if !ok {
+			log.Fatalf("FATAL: --%s doit être au format date=URL (reçu '%s')", flagName, value)
+		}
+		switchAt, err := time.Parse(time.RFC3339, strings.TrimSpace(at))
+		if err != nil {
+			log.Fatalf("FATAL: --%s: la date '%s' doit être au format RFC3339 (ex: 2025-06-01T09:00:00Z)", flagName, at)
+		}
+		entries = append(entries, services.ScheduledURL{At: switchAt, URL: targetURL})
+	}
+	return entries
+}
+
 // init() s'exécute automatiquement lors de l'importation du package.
 // Il est utilisé pour définir les flags que cette commande accepte.
 func init() {
 	// DONE : Définir le flag --url pour la commande create.
 	CreateCmd.Flags().StringVarP(&longURLFlag, "url", "u", "", "URL longue à raccourcir")
+	CreateCmd.Flags().StringVarP(&aliasFlag, "alias", "a", "", "Code court personnalisé (3 à 10 caractères: lettres, chiffres, '-' et '_')")
+	CreateCmd.Flags().StringVar(&expiresAtFlag, "expires-at", "", "Date d'expiration absolue au format RFC3339")
+	CreateCmd.Flags().DurationVar(&ttlFlag, "ttl", 0, "Durée de vie du lien (ex: 30m, 72h)")
+	CreateCmd.Flags().StringVar(&expiredURLFlag, "expired-url", "", "URL de repli une fois le lien expiré")
+	CreateCmd.Flags().StringVar(&activatesAtFlag, "activates-at", "", "Date d'activation du lien au format RFC3339 (avant: page d'attente)")
+	CreateCmd.Flags().StringVar(&pendingURLFlag, "pending-url", "", "URL d'attente avant l'activation (par défaut: schedule.placeholder_url)")
+	CreateCmd.Flags().StringArrayVar(&scheduleFlags, "schedule", nil, "Changement de destination programmé, ex: 2025-06-01T09:00:00Z=https://site.com/live (répétable)")
+	CreateCmd.Flags().IntVar(&maxClicksFlag, "max-clicks", 0, "Nombre maximum de redirections (0 = illimité)")
+	CreateCmd.Flags().BoolVar(&oneTimeFlag, "one-time", false, "Lien à usage unique (désactivé après le premier clic)")
+	CreateCmd.Flags().StringVar(&passwordFlag, "password", "", "Mot de passe protégeant l'accès au lien")
+	CreateCmd.Flags().StringVar(&titleFlag, "title", "", "Titre du lien")
+	CreateCmd.Flags().StringVar(&descriptionFlag, "description", "", "Description du lien")
+	CreateCmd.Flags().StringVar(&notesFlag, "notes", "", "Notes internes (usage du lien, contact...)")
+	CreateCmd.Flags().StringSliceVarP(&tagsFlag, "tag", "t", nil, "Tag du lien (répétable ou séparé par des virgules)")
+	CreateCmd.Flags().StringVar(&utmPresetFlag, "utm-preset", "", "Preset UTM défini dans la section utm.presets de la configuration")
+	CreateCmd.Flags().StringVar(&utmFlags.Source, "utm-source", "", "Paramètre utm_source ajouté à l'URL longue")
+	CreateCmd.Flags().StringVar(&utmFlags.Medium, "utm-medium", "", "Paramètre utm_medium ajouté à l'URL longue")
+	CreateCmd.Flags().StringVar(&utmFlags.Campaign, "utm-campaign", "", "Paramètre utm_campaign ajouté à l'URL longue")
+	CreateCmd.Flags().StringVar(&utmFlags.Term, "utm-term", "", "Paramètre utm_term ajouté à l'URL longue")
+	CreateCmd.Flags().StringVar(&utmFlags.Content, "utm-content", "", "Paramètre utm_content ajouté à l'URL longue")
+	CreateCmd.Flags().StringArrayVar(&deviceFlags, "device", nil, "Destination par type d'appareil, ex: ios=https://apps.apple.com/... (ios, android, desktop ; répétable)")
+	CreateCmd.Flags().StringArrayVar(&variantFlags, "variant", nil, "Variante de test A/B, ex: a:70=https://site.com/a (poids 1 si omis ; répétable)")
+	CreateCmd.Flags().BoolVar(&stickyVariantFlag, "sticky-variant", false, "Conserver la variante tirée pour chaque visiteur (cookie)")
+	CreateCmd.Flags().StringArrayVar(&countryFlags, "country", nil, "Destination par pays du visiteur (GeoIP), ex: FR=https://site.fr (code ISO à deux lettres ; répétable)")
+	CreateCmd.Flags().StringArrayVar(&languageFlags, "language", nil, "Destination par langue du visiteur (Accept-Language), ex: fr=https://site.com/fr (répétable)")
+	CreateCmd.Flags().BoolVar(&previewFlag, "preview", false, "Afficher une page d'aperçu (destination, clics) avant chaque redirection")
+	CreateCmd.Flags().IntVar(&redirectStatusFlag, "redirect-status", 0, "Code HTTP de redirection: 301, 302, 307 ou 308 (par défaut: redirect.status)")
+	CreateCmd.Flags().BoolVar(&forwardQueryFlag, "forward-query", false, "Transmettre la query string des visiteurs (?ref=...) à la destination")
+	CreateCmd.Flags().BoolVar(&forwardPathFlag, "forward-path", false, "Transmettre le chemin demandé après le code court (/code/suite) à la destination")
+	CreateCmd.Flags().BoolVar(&fetchDestinationFlag, "fetch-destination", false, "Récupérer l'URL finale, le code HTTP, le titre et les balises Open Graph de la destination (par défaut: destination.fetch_on_create)")
 
 	// DONE :  Marquer le flag comme requis
 	CreateCmd.MarkFlagRequired("url")
@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	cmd2 "github.com/axellelanca/urlshortener/cmd"
+	"github.com/axellelanca/urlshortener/internal/repository"
+	"github.com/axellelanca/urlshortener/internal/services"
+	"github.com/spf13/cobra"
+
+	// "github.com/glebarez/sqlite" WINDOWS
+	"gorm.io/driver/sqlite" // MAC
+	"gorm.io/gorm"
+)
+
+// Flags de la commande delete
+var (
+	deleteCodeFlag         string
+	deletePurgeFlag        bool
+	deletePurgeExpiredFlag bool
+)
+
+// DeleteCmd représente la commande 'delete'
+var DeleteCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "Supprime un lien court (corbeille ou suppression définitive).",
+	Long: `Cette commande met un lien court à la corbeille. Il reste restaurable avec 'restore'
+pendant la durée de rétention configurée (trash.retention_days).
+
+Avec --purge, le lien est supprimé définitivement avec ses clics et son historique.
+Avec --purge-expired, tous les liens restés à la corbeille au-delà de la rétention sont purgés.
+
+Exemple:
+  url-shortener delete --code="xyz123"
+  url-shortener delete --code="xyz123" --purge
+  url-shortener delete --purge-expired`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if deleteCodeFlag == "" && !deletePurgeExpiredFlag {
+			log.Fatal("FATAL: Le flag --code (ou --purge-expired) est requis")
+		}
+
+		cfg := cmd2.Cfg
+		if cfg == nil {
+			log.Fatal("FATAL: Configuration non chargée")
+		}
+
+		db, err := gorm.Open(sqlite.Open(cfg.Database.Name), &gorm.Config{})
+		if err != nil {
+			log.Fatalf("FATAL: Échec de la connexion à la base de données: %v", err)
+		}
+
+		sqlDB, err := db.DB()
+		if err != nil {
+			log.Fatalf("FATAL: Échec de l'obtention de la base de données SQL sous-jacente: %v", err)
+		}
+		defer sqlDB.Close()
+
+		linkRepo := repository.NewLinkRepository(db)
+		linkService, err := services.NewLinkServiceFromConfig(linkRepo, cfg)
+		if err != nil {
+			log.Fatalf("FATAL: %v", err)
+		}
+
+		if deletePurgeExpiredFlag {
+			retention := time.Duration(cfg.Trash.RetentionDays) * 24 * time.Hour
+			count, err := linkService.PurgeExpiredTrash(retention)
+			if err != nil {
+				log.Fatalf("FATAL: Erreur lors de la purge de la corbeille: %v", err)
+			}
+			fmt.Printf("%d lien(s) purgé(s) de la corbeille.\n\n", count)
+			return
+		}
+
+		if deletePurgeFlag {
+			_, err = linkService.PurgeLink(deleteCodeFlag)
+		} else {
+			_, err = linkService.DeleteLink(deleteCodeFlag)
+		}
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				log.Fatalf("ERREUR: Aucun lien trouvé avec le code '%s'", deleteCodeFlag)
+			}
+			log.Fatalf("FATAL: Erreur lors de la suppression du lien: %v", err)
+		}
+
+		if deletePurgeFlag {
+			fmt.Printf("Lien '%s' supprimé définitivement (clics et historique inclus).\n\n", deleteCodeFlag)
+		} else {
+			fmt.Printf("Lien '%s' mis à la corbeille (restaurable pendant %d jours).\n\n", deleteCodeFlag, cfg.Trash.RetentionDays)
+		}
+	},
+}
+
+func init() {
+	DeleteCmd.Flags().StringVarP(&deleteCodeFlag, "code", "c", "", "Code court du lien à supprimer")
+	DeleteCmd.Flags().BoolVar(&deletePurgeFlag, "purge", false, "Supprimer définitivement le lien, ses clics et son historique")
+	DeleteCmd.Flags().BoolVar(&deletePurgeExpiredFlag, "purge-expired", false, "Purger les liens restés à la corbeille au-delà de la rétention")
+
+	cmd2.RootCmd.AddCommand(DeleteCmd)
+}
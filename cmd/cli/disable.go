@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	cmd2 "github.com/axellelanca/urlshortener/cmd"
+	"github.com/axellelanca/urlshortener/internal/repository"
+	"github.com/axellelanca/urlshortener/internal/services"
+	"github.com/spf13/cobra"
+
+	// "github.com/glebarez/sqlite" WINDOWS
+	"gorm.io/driver/sqlite" // MAC
+	"gorm.io/gorm"
+)
+
+// toggleCodeFlag stocke la valeur du flag --code des commandes disable et enable
+var toggleCodeFlag string
+
+// DisableCmd représente la commande 'disable'
+var DisableCmd = &cobra.Command{
+	Use:   "disable",
+	Short: "Désactive un lien court (la redirection renvoie 410, les statistiques sont conservées).",
+	Long: `Cette commande désactive un lien court sans le supprimer.
+
+Exemple:
+  url-shortener disable --code="xyz123"`,
+	Run: func(cmd *cobra.Command, args []string) {
+		setLinkDisabled(true)
+	},
+}
+
+// EnableCmd représente la commande 'enable'
+var EnableCmd = &cobra.Command{
+	Use:   "enable",
+	Short: "Réactive un lien court précédemment désactivé.",
+	Long: `Cette commande réactive un lien court désactivé avec 'disable'.
+
+Exemple:
+  url-shortener enable --code="xyz123"`,
+	Run: func(cmd *cobra.Command, args []string) {
+		setLinkDisabled(false)
+	},
+}
+
+// setLinkDisabled contient la logique commune aux commandes disable et enable.
+func setLinkDisabled(disabled bool) {
+	if toggleCodeFlag == "" {
+		log.Fatal("FATAL: Le flag --code est requis")
+	}
+
+	cfg := cmd2.Cfg
+	if cfg == nil {
+		log.Fatal("FATAL: Configuration non chargée")
+	}
+
+	db, err := gorm.Open(sqlite.Open(cfg.Database.Name), &gorm.Config{})
+	if err != nil {
+		log.Fatalf("FATAL: Échec de la connexion à la base de données: %v", err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		log.Fatalf("FATAL: Échec de l'obtention de la base de données SQL sous-jacente: %v", err)
+	}
+	defer sqlDB.Close()
+
+	linkRepo := repository.NewLinkRepository(db)
+	linkService, err := services.NewLinkServiceFromConfig(linkRepo, cfg)
+	if err != nil {
+		log.Fatalf("FATAL: %v", err)
+	}
+
+	if _, err := linkService.SetLinkDisabled(toggleCodeFlag, disabled); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Fatalf("ERREUR: Aucun lien trouvé avec le code '%s'", toggleCodeFlag)
+		}
+		log.Fatalf("FATAL: Erreur lors de la modification du statut du lien: %v", err)
+	}
+
+	if disabled {
+		fmt.Printf("Lien '%s' désactivé.\n\n", toggleCodeFlag)
+	} else {
+		fmt.Printf("Lien '%s' réactivé.\n\n", toggleCodeFlag)
+	}
+}
+
+func init() {
+	DisableCmd.Flags().StringVarP(&toggleCodeFlag, "code", "c", "", "Code court du lien à désactiver")
+	DisableCmd.MarkFlagRequired("code")
+	EnableCmd.Flags().StringVarP(&toggleCodeFlag, "code", "c", "", "Code court du lien à réactiver")
+	EnableCmd.MarkFlagRequired("code")
+
+	cmd2.RootCmd.AddCommand(DisableCmd)
+	cmd2.RootCmd.AddCommand(EnableCmd)
+}
@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"io"
+	"log"
+	"os"
+	"time"
+
+	cmd2 "github.com/axellelanca/urlshortener/cmd"
+	"github.com/axellelanca/urlshortener/internal/models"
+	"github.com/axellelanca/urlshortener/internal/repository"
+	"github.com/axellelanca/urlshortener/internal/services"
+	"github.com/spf13/cobra"
+
+	// "github.com/glebarez/sqlite" WINDOWS
+	"gorm.io/driver/sqlite" // MAC
+	"gorm.io/gorm"
+)
+
+// Flags de la commande export
+var (
+	exportFormatFlag string
+	exportOutFlag    string
+)
+
+// ExportCmd représente la commande 'export'
+var ExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Exporte toutes les données (liens, clics, historique...) pour sauvegarde ou migration.",
+	Long: `Cette commande exporte l'intégralité de la base (toutes les tables des modèles, corbeille comprise)
+dans un fichier versionné au format JSON Lines ou CSV. Le fichier peut être restauré avec 'import'
+dans une base migrée et vide, en conservant les IDs et les codes courts.
+
+Exemple:
+  url-shortener export --out backup.jsonl
+  url-shortener export --format=csv > backup.csv`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if exportFormatFlag != services.DatasetFormatJSONL && exportFormatFlag != services.DatasetFormatCSV {
+			log.Fatalf("FATAL: --format doit valoir '%s' ou '%s'", services.DatasetFormatJSONL, services.DatasetFormatCSV)
+		}
+
+		cfg := cmd2.Cfg
+		if cfg == nil {
+			log.Fatal("FATAL: Configuration non chargée")
+		}
+
+		db, err := gorm.Open(sqlite.Open(cfg.Database.Name), &gorm.Config{})
+		if err != nil {
+			log.Fatalf("FATAL: Échec de la connexion à la base de données: %v", err)
+		}
+
+		sqlDB, err := db.DB()
+		if err != nil {
+			log.Fatalf("FATAL: Échec de l'obtention de la base de données SQL sous-jacente: %v", err)
+		}
+		defer sqlDB.Close()
+
+		var output io.Writer = os.Stdout
+		if exportOutFlag != "" && exportOutFlag != "-" {
+			file, err := os.Create(exportOutFlag)
+			if err != nil {
+				log.Fatalf("FATAL: Impossible de créer le fichier '%s': %v", exportOutFlag, err)
+			}
+			defer file.Close()
+			output = file
+		}
+
+		datasetService := services.NewDatasetService(repository.NewDatasetRepository(db, models.AllModels()...))
+		summary, err := datasetService.Export(output, exportFormatFlag, time.Now())
+		if err != nil {
+			log.Fatalf("FATAL: %v", err)
+		}
+
+		// Le résumé est écrit sur la sortie d'erreur pour ne pas se mêler à un export vers stdout.
+		for _, table := range summary.Tables {
+			log.Printf("Table %s: %d ligne(s) exportée(s)", table.Table, table.Rows)
+		}
+		log.Printf("Export terminé (format %s, version %d).", exportFormatFlag, summary.Version)
+	},
+}
+
+func init() {
+	ExportCmd.Flags().StringVarP(&exportFormatFlag, "format", "f", services.DatasetFormatJSONL, "Format de l'export: jsonl ou csv")
+	ExportCmd.Flags().StringVarP(&exportOutFlag, "out", "o", "", "Fichier de sortie (vide ou '-' = sortie standard)")
+
+	cmd2.RootCmd.AddCommand(ExportCmd)
+}
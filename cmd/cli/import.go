@@ -0,0 +1,199 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	cmd2 "github.com/axellelanca/urlshortener/cmd"
+	"github.com/axellelanca/urlshortener/internal/models"
+	"github.com/axellelanca/urlshortener/internal/repository"
+	"github.com/axellelanca/urlshortener/internal/services"
+	"github.com/spf13/cobra"
+
+	// "github.com/glebarez/sqlite" WINDOWS
+	"gorm.io/driver/sqlite" // MAC
+	"gorm.io/gorm"
+)
+
+// importFileFlag stocke le chemin du fichier à importer ("-" ou vide = entrée standard)
+var importFileFlag string
+
+// ImportCmd représente la commande 'import'
+var ImportCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Crée des URLs courtes en lot (CSV) ou restaure un export complet.",
+	Long: `Cette commande lit un fichier ou l'entrée standard, dans l'un des deux formats suivants.
+
+Liste de liens CSV : chaque ligne contient une URL longue et, optionnellement, un alias
+("long_url[,alias]"). Une ligne d'en-tête "long_url,alias" est ignorée si elle est présente.
+Tous les liens sont créés avec une seule connexion, par transactions ; une ligne en erreur
+(URL déjà existante, alias invalide...) est signalée sans interrompre l'import.
+
+Export complet produit par 'export' (reconnu à son en-tête) : toutes les tables sont restaurées
+en une transaction, IDs et codes courts compris. La base doit être migrée et vide ; l'import est
+annulé si un clic ou une révision référence un lien absent.
+
+Exemple:
+  url-shortener import --file links.csv
+  cat links.csv | url-shortener import
+  url-shortener import --file backup.jsonl`,
+	Run: func(cmd *cobra.Command, args []string) {
+		var input io.Reader = os.Stdin
+		if importFileFlag != "" && importFileFlag != "-" {
+			file, err := os.Open(importFileFlag)
+			if err != nil {
+				log.Fatalf("FATAL: Impossible d'ouvrir le fichier '%s': %v", importFileFlag, err)
+			}
+			defer file.Close()
+			input = file
+		}
+		reader := bufio.NewReader(input)
+
+		cfg := cmd2.Cfg
+		if cfg == nil {
+			log.Fatal("FATAL: Configuration non chargée")
+		}
+
+		db, err := gorm.Open(sqlite.Open(cfg.Database.Name), &gorm.Config{})
+		if err != nil {
+			log.Fatalf("FATAL: Échec de la connexion à la base de données: %v", err)
+		}
+
+		sqlDB, err := db.DB()
+		if err != nil {
+			log.Fatalf("FATAL: Échec de l'obtention de la base de données SQL sous-jacente: %v", err)
+		}
+		defer sqlDB.Close()
+
+		if services.IsDatasetDump(reader) {
+			importDataset(db, reader)
+			return
+		}
+
+		requests, lines, err := readImportCSV(reader)
+		if err != nil {
+			log.Fatalf("FATAL: Lecture du CSV impossible: %v", err)
+		}
+		if len(requests) == 0 {
+			log.Fatal("FATAL: Aucun lien à importer")
+		}
+
+		linkService, err := services.NewLinkServiceFromConfig(repository.NewLinkRepository(db), cfg)
+		if err != nil {
+			log.Fatalf("FATAL: %v", err)
+		}
+
+		results, err := linkService.CreateLinksBatch(requests)
+		if err != nil {
+			log.Printf("ERREUR: %v", err)
+		}
+		// Métadonnées des destinations récupérées en arrière-plan (destination.fetch_on_create) : les attendre avant de quitter.
+		linkService.WaitDestinationFetches()
+
+		created, existing := 0, 0
+		for _, result := range results {
+			line := lines[result.Index]
+			if result.Err != nil {
+				fmt.Printf("Ligne %d: ERREUR %s: %v\n", line, result.LongURL, importErrorMessage(result.Err))
+				continue
+			}
+			note := ""
+			if result.Existing {
+				existing++
+				note = " (lien existant)"
+			} else {
+				created++
+			}
+			fmt.Printf("Ligne %d: %s/%s -> %s%s\n", line, cfg.Server.BaseURL, result.Link.Shortcode, result.LongURL, note)
+		}
+
+		failed := len(requests) - created - existing
+		fmt.Printf("\nImport terminé: %d lien(s) créé(s), %d existant(s), %d en erreur sur %d.\n", created, existing, failed, len(requests))
+		if err != nil || failed > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+// importDataset restaure un export complet produit par la commande 'export'.
+func importDataset(db *gorm.DB, input io.Reader) {
+	datasetService := services.NewDatasetService(repository.NewDatasetRepository(db, models.AllModels()...))
+	summary, err := datasetService.Import(input)
+	if err != nil {
+		if errors.Is(err, services.ErrDatasetTargetNotEmpty) {
+			log.Fatalf("ERREUR: %v (importez dans une base fraîchement migrée)", err)
+		}
+		log.Fatalf("FATAL: %v", err)
+	}
+
+	for _, table := range summary.Tables {
+		fmt.Printf("Table %s: %d ligne(s) importée(s)\n", table.Table, table.Rows)
+	}
+	fmt.Printf("\nRestauration terminée (export version %d).\n", summary.Version)
+}
+
+// readImportCSV lit les lignes "long_url[,alias]" du CSV et retourne les requêtes de création
+// ainsi que, pour chacune, son numéro de ligne dans le fichier (pour les messages d'erreur).
+func readImportCSV(input io.Reader) ([]services.BatchLinkRequest, []int, error) {
+	reader := csv.NewReader(input)
+	reader.FieldsPerRecord = -1 // L'alias est optionnel
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+
+	var requests []services.BatchLinkRequest
+	var lines []int
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		line, _ := reader.FieldPos(0)
+
+		longURL := strings.TrimSpace(record[0])
+		if longURL == "" {
+			continue
+		}
+		// Ligne d'en-tête optionnelle
+		if len(requests) == 0 && strings.EqualFold(longURL, "long_url") {
+			continue
+		}
+		if len(record) > 2 {
+			return nil, nil, fmt.Errorf("ligne %d: 2 colonnes maximum attendues (long_url,alias), %d trouvées", line, len(record))
+		}
+
+		req := services.BatchLinkRequest{LongURL: longURL}
+		if len(record) == 2 {
+			req.Options.Alias = strings.TrimSpace(record[1])
+		}
+		requests = append(requests, req)
+		lines = append(lines, line)
+	}
+	return requests, lines, nil
+}
+
+// importErrorMessage rend une erreur de création lisible pour l'utilisateur.
+func importErrorMessage(err error) string {
+	switch {
+	case errors.Is(err, services.ErrURLAlreadyExists):
+		return "l'URL existe déjà"
+	case errors.Is(err, services.ErrAliasAlreadyExists):
+		return "l'alias est déjà utilisé"
+	default:
+		return err.Error()
+	}
+}
+
+func init() {
+	ImportCmd.Flags().StringVarP(&importFileFlag, "file", "f", "", "Fichier CSV à importer (vide ou '-' = entrée standard)")
+
+	cmd2.RootCmd.AddCommand(ImportCmd)
+}
@@ -0,0 +1,180 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	cmd2 "github.com/axellelanca/urlshortener/cmd"
+	"github.com/axellelanca/urlshortener/internal/repository"
+	"github.com/axellelanca/urlshortener/internal/services"
+	"github.com/spf13/cobra"
+
+	// "github.com/glebarez/sqlite" WINDOWS
+	"gorm.io/driver/sqlite" // MAC
+	"gorm.io/gorm"
+)
+
+// Flags de la commande list
+var (
+	listLimitFlag         int
+	listCursorFlag        string
+	listSortFlag          string
+	listOrderFlag         string
+	listDomainFlag        string
+	listCreatedAfterFlag  string
+	listCreatedBeforeFlag string
+	listStatusFlag        string
+	listFormatFlag        string
+	listTagsFlag          []string
+)
+
+// listedLink est la représentation JSON d'un lien dans la sortie de la commande list.
+type listedLink struct {
+	ShortCode   string    `json:"short_code"`
+	LongURL     string    `json:"long_url"`
+	Domain      string    `json:"domain"`
+	CreatedAt   time.Time `json:"created_at"`
+	Status      string    `json:"status"`
+	TotalClicks int       `json:"total_clicks"`
+	Title       string    `json:"title"`
+	Tags        []string  `json:"tags"`
+	PageTitle   string    `json:"page_title"`
+}
+
+// ListCmd représente la commande 'list'
+var ListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Liste les liens courts existants (paginé, filtrable).",
+	Long: `Cette commande liste les liens courts, avec pagination par curseur, tri et filtres.
+Le curseur de la page suivante est affiché en fin de sortie.
+
+Exemple:
+  url-shortener list
+  url-shortener list --sort=clicks --domain=example.com --status=active
+  url-shortener list --created-after=2025-01-01 --format=json
+  url-shortener list --tag=campaign-q3`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if listFormatFlag != "table" && listFormatFlag != "json" {
+			log.Fatal("FATAL: --format doit valoir 'table' ou 'json'")
+		}
+
+		params := services.ListLinksParams{
+			Domain: listDomainFlag,
+			Status: listStatusFlag,
+			Sort:   listSortFlag,
+			Order:  listOrderFlag,
+			Limit:  listLimitFlag,
+			Cursor: listCursorFlag,
+			Tags:   listTagsFlag,
+		}
+		var err error
+		if listCreatedAfterFlag != "" {
+			if params.CreatedAfter, err = services.ParseDateFilter(listCreatedAfterFlag); err != nil {
+				log.Fatalf("FATAL: --created-after: %v", err)
+			}
+		}
+		if listCreatedBeforeFlag != "" {
+			if params.CreatedBefore, err = services.ParseDateFilter(listCreatedBeforeFlag); err != nil {
+				log.Fatalf("FATAL: --created-before: %v", err)
+			}
+		}
+
+		cfg := cmd2.Cfg
+		if cfg == nil {
+			log.Fatal("FATAL: Configuration non chargée")
+		}
+
+		db, err := gorm.Open(sqlite.Open(cfg.Database.Name), &gorm.Config{})
+		if err != nil {
+			log.Fatalf("FATAL: Échec de la connexion à la base de données: %v", err)
+		}
+
+		sqlDB, err := db.DB()
+		if err != nil {
+			log.Fatalf("FATAL: Échec de l'obtention de la base de données SQL sous-jacente: %v", err)
+		}
+		defer sqlDB.Close()
+
+		linkRepo := repository.NewLinkRepository(db)
+		linkService, err := services.NewLinkServiceFromConfig(linkRepo, cfg)
+		if err != nil {
+			log.Fatalf("FATAL: %v", err)
+		}
+
+		page, err := linkService.ListLinks(params)
+		if err != nil {
+			if errors.Is(err, services.ErrInvalidListParams) {
+				log.Fatalf("ERREUR: %v", err)
+			}
+			log.Fatalf("FATAL: Erreur lors du listage des liens: %v", err)
+		}
+
+		now := time.Now()
+		links := make([]listedLink, 0, len(page.Links))
+		for _, link := range page.Links {
+			links = append(links, listedLink{
+				ShortCode:   link.Shortcode,
+				LongURL:     link.LongURL,
+				Domain:      link.Domain,
+				CreatedAt:   link.CreatedAt,
+				Status:      link.Status(now),
+				TotalClicks: link.TotalClicks,
+				Title:       link.Title,
+				Tags:        services.TagNames(link.Tags),
+				PageTitle:   link.Destination.PageTitle(),
+			})
+		}
+
+		if listFormatFlag == "json" {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(map[string]interface{}{
+				"links":       links,
+				"next_cursor": page.NextCursor,
+			}); err != nil {
+				log.Fatalf("FATAL: Erreur d'encodage JSON: %v", err)
+			}
+			return
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "CODE\tSTATUT\tCLICS\tCRÉÉ LE\tTAGS\tTITRE\tURL LONGUE")
+		for _, link := range links {
+			// À défaut de titre saisi, le titre de la page de destination (s'il a été récupéré) nomme le lien.
+			title := link.Title
+			if title == "" {
+				title = link.PageTitle
+			}
+			fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\t%s\t%s\n",
+				link.ShortCode, link.Status, link.TotalClicks, link.CreatedAt.Format("2006-01-02 15:04"),
+				strings.Join(link.Tags, ","), title, link.LongURL)
+		}
+		w.Flush()
+
+		if page.NextCursor != "" {
+			fmt.Printf("\nPage suivante: --cursor=%s\n", page.NextCursor)
+		}
+		fmt.Println()
+	},
+}
+
+func init() {
+	ListCmd.Flags().IntVarP(&listLimitFlag, "limit", "n", 20, "Nombre de liens par page (max 100)")
+	ListCmd.Flags().StringVar(&listCursorFlag, "cursor", "", "Curseur de la page suivante")
+	ListCmd.Flags().StringVar(&listSortFlag, "sort", "created_at", "Tri: created_at ou clicks")
+	ListCmd.Flags().StringVar(&listOrderFlag, "order", "desc", "Ordre: asc ou desc")
+	ListCmd.Flags().StringVar(&listDomainFlag, "domain", "", "Filtre sur une partie du domaine de l'URL longue")
+	ListCmd.Flags().StringVar(&listCreatedAfterFlag, "created-after", "", "Liens créés à partir de cette date (RFC3339 ou YYYY-MM-DD)")
+	ListCmd.Flags().StringVar(&listCreatedBeforeFlag, "created-before", "", "Liens créés avant cette date (RFC3339 ou YYYY-MM-DD)")
+	ListCmd.Flags().StringVar(&listStatusFlag, "status", "", "Filtre sur le statut: active, disabled, expired, exhausted ou deleted")
+	ListCmd.Flags().StringSliceVarP(&listTagsFlag, "tag", "t", nil, "Filtre sur un tag (répétable : le lien doit porter tous les tags)")
+	ListCmd.Flags().StringVarP(&listFormatFlag, "format", "f", "table", "Format de sortie: table ou json")
+
+	cmd2.RootCmd.AddCommand(ListCmd)
+}
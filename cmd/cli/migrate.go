@@ -6,6 +6,8 @@ import (
 
 	cmd2 "github.com/axellelanca/urlshortener/cmd"
 	"github.com/axellelanca/urlshortener/internal/models"
+	"github.com/axellelanca/urlshortener/internal/repository"
+	"github.com/axellelanca/urlshortener/internal/services"
 	"github.com/spf13/cobra"
 
 	// "github.com/glebarez/sqlite" WINDOWS
@@ -18,8 +20,8 @@ var MigrateCmd = &cobra.Command{
 	Use:   "migrate",
 	Short: "Exécute les migrations de la base de données pour créer ou mettre à jour les tables.",
 	Long: `Cette commande se connecte à la base de données configurée (SQLite)
-et exécute les migrations automatiques de GORM pour créer les tables 'links' et 'clicks'
-basées sur les modèles Go.`,
+et exécute les migrations automatiques de GORM pour créer les tables 'links', 'clicks',
+'link_revisions', 'tags', 'link_tags', 'link_targets', 'link_schedules' et 'campaigns' basées sur les modèles Go.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		// DONE : Charger la configuration chargée globalement via cmd.Cfg
 		cfg := cmd2.Cfg
@@ -43,12 +45,34 @@ basées sur les modèles Go.`,
 		// DONE : Exécuter les migrations automatiques de GORM.
 		// Utilisez db.AutoMigrate() et passez-lui les pointeurs vers tous vos modèles.
 		log.Println("Exécution des migrations de la base de données...")
-		if err := db.AutoMigrate(&models.Link{}, &models.Click{}); err != nil {
+		if err := db.AutoMigrate(models.AllModels()...); err != nil {
 			log.Fatalf("FATAL: Échec des migrations: %v", err)
 		}
 
+		// Renseigner le domaine des liens créés avant l'ajout de la colonne 'domain'.
+		linkService, err := services.NewLinkServiceFromConfig(repository.NewLinkRepository(db), cfg)
+		if err != nil {
+			log.Fatalf("FATAL: %v", err)
+		}
+		count, err := linkService.BackfillDomains()
+		if err != nil {
+			log.Fatalf("FATAL: Échec du remplissage des domaines: %v", err)
+		}
+		if count > 0 {
+			log.Printf("Domaine renseigné pour %d lien(s) existant(s).", count)
+		}
+
+		// (Re)calculer l'URL canonique utilisée pour la détection des doublons.
+		count, err = linkService.BackfillCanonicalURLs()
+		if err != nil {
+			log.Fatalf("FATAL: Échec du calcul des URLs canoniques: %v", err)
+		}
+		if count > 0 {
+			log.Printf("URL canonique renseignée pour %d lien(s) existant(s).", count)
+		}
+
 		// Pas touche au log
-		fmt.Println("Migrations de la base de données exécutées avec succès.\n")
+		fmt.Print("Migrations de la base de données exécutées avec succès.\n\n")
 	},
 }
 
@@ -0,0 +1,127 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	cmd2 "github.com/axellelanca/urlshortener/cmd"
+	"github.com/axellelanca/urlshortener/internal/repository"
+	"github.com/axellelanca/urlshortener/internal/services"
+	"github.com/spf13/cobra"
+
+	// "github.com/glebarez/sqlite" WINDOWS
+	"gorm.io/driver/sqlite" // MAC
+	"gorm.io/gorm"
+)
+
+// Flags de la commande qr : code du lien, fichier de sortie et options de l'image
+var (
+	qrCodeFlag   string
+	qrOutFlag    string
+	qrFormatFlag string
+	qrSizeFlag   int
+	qrMarginFlag int
+	qrLevelFlag  string
+	qrFgFlag     string
+	qrBgFlag     string
+)
+
+// QRCmd représente la commande 'qr'
+var QRCmd = &cobra.Command{
+	Use:   "qr",
+	Short: "Génère le QR code d'un lien court (PNG ou SVG).",
+	Long: `Cette commande enregistre dans un fichier le QR code de l'URL courte d'un lien,
+construite à partir de server.base_url et marquée src=qr : les scans sont comptés
+séparément des clics dans les statistiques. Le format est déduit de l'extension du fichier
+(.svg, sinon PNG), sauf si --format est fourni.
+
+Exemple:
+  url-shortener qr --code="xyz123" --out=affiche.png
+  url-shortener qr --code="xyz123" --out=flyer.svg --level=H --margin=2
+  url-shortener qr --code="xyz123" --out=carte.png --size=1024 --fg="#1a237e" --bg="#ffffff"`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if qrCodeFlag == "" || qrOutFlag == "" {
+			log.Fatal("FATAL: Les flags --code et --out sont requis")
+		}
+
+		opts := services.QROptions{
+			Format:     qrFormatFlag,
+			Size:       qrSizeFlag,
+			Margin:     &qrMarginFlag,
+			Level:      qrLevelFlag,
+			Foreground: qrFgFlag,
+			Background: qrBgFlag,
+		}
+		if opts.Format == "" {
+			opts.Format = services.QRFormatPNG
+			if strings.EqualFold(filepath.Ext(qrOutFlag), "."+services.QRFormatSVG) {
+				opts.Format = services.QRFormatSVG
+			}
+		}
+
+		cfg := cmd2.Cfg
+		if cfg == nil {
+			log.Fatal("FATAL: Configuration non chargée")
+		}
+
+		db, err := gorm.Open(sqlite.Open(cfg.Database.Name), &gorm.Config{})
+		if err != nil {
+			log.Fatalf("FATAL: Échec de la connexion à la base de données: %v", err)
+		}
+
+		sqlDB, err := db.DB()
+		if err != nil {
+			log.Fatalf("FATAL: Échec de l'obtention de la base de données SQL sous-jacente: %v", err)
+		}
+		defer sqlDB.Close()
+
+		linkRepo := repository.NewLinkRepository(db)
+		linkService, err := services.NewLinkServiceFromConfig(linkRepo, cfg)
+		if err != nil {
+			log.Fatalf("FATAL: %v", err)
+		}
+
+		link, err := linkService.GetLinkByShortCode(qrCodeFlag)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				log.Fatalf("ERREUR: Aucun lien trouvé avec le code '%s'", qrCodeFlag)
+			}
+			log.Fatalf("FATAL: Erreur lors de la récupération du lien: %v", err)
+		}
+
+		content := services.QRCodeURL(cfg.Server.BaseURL, link.Shortcode)
+		image, err := services.GenerateQRCode(content, opts)
+		if err != nil {
+			if errors.Is(err, services.ErrInvalidQROptions) {
+				log.Fatalf("ERREUR: %v", err)
+			}
+			log.Fatalf("FATAL: Erreur lors de la génération du QR code: %v", err)
+		}
+		if err := os.WriteFile(qrOutFlag, image, 0o644); err != nil {
+			log.Fatalf("FATAL: Impossible d'écrire le fichier '%s': %v", qrOutFlag, err)
+		}
+
+		fmt.Printf("QR code enregistré dans %s\n", qrOutFlag)
+		fmt.Printf("URL encodée: %s\n\n", content)
+	},
+}
+
+func init() {
+	QRCmd.Flags().StringVarP(&qrCodeFlag, "code", "c", "", "Code court du lien")
+	QRCmd.Flags().StringVarP(&qrOutFlag, "out", "o", "", "Fichier de sortie (.png ou .svg)")
+	QRCmd.Flags().StringVar(&qrFormatFlag, "format", "", "Format de l'image: png ou svg (par défaut: extension du fichier)")
+	QRCmd.Flags().IntVar(&qrSizeFlag, "size", 256, "Côté de l'image en pixels (64 à 2048)")
+	QRCmd.Flags().IntVar(&qrMarginFlag, "margin", 4, "Marge autour du code, en modules (0 à 16)")
+	QRCmd.Flags().StringVar(&qrLevelFlag, "level", "M", "Niveau de correction d'erreur: L (7 %), M (15 %), Q (25 %) ou H (30 %)")
+	QRCmd.Flags().StringVar(&qrFgFlag, "fg", "#000000", "Couleur des modules (#rrggbb)")
+	QRCmd.Flags().StringVar(&qrBgFlag, "bg", "#ffffff", "Couleur du fond (#rrggbb)")
+
+	QRCmd.MarkFlagRequired("code")
+	QRCmd.MarkFlagRequired("out")
+
+	cmd2.RootCmd.AddCommand(QRCmd)
+}
@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	cmd2 "github.com/axellelanca/urlshortener/cmd"
+	"github.com/axellelanca/urlshortener/internal/models"
+	"github.com/axellelanca/urlshortener/internal/repository"
+	"github.com/axellelanca/urlshortener/internal/services"
+	"github.com/spf13/cobra"
+
+	// "github.com/glebarez/sqlite" WINDOWS
+	"gorm.io/driver/sqlite" // MAC
+	"gorm.io/gorm"
+)
+
+// refreshCodeFlag stocke la valeur du flag --code
+var refreshCodeFlag string
+
+// RefreshCmd représente la commande 'refresh'
+var RefreshCmd = &cobra.Command{
+	Use:   "refresh",
+	Short: "Récupère à nouveau les métadonnées de la destination d'un lien.",
+	Long: `Cette commande interroge immédiatement l'URL longue d'un lien et enregistre
+l'URL finale après redirections, le code HTTP, le type de contenu, le titre de la page
+et ses balises Open Graph (og:title, og:description, og:image).
+
+Exemple:
+  url-shortener refresh --code="xyz123"`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if refreshCodeFlag == "" {
+			log.Fatal("FATAL: Le flag --code est requis")
+		}
+
+		cfg := cmd2.Cfg
+		if cfg == nil {
+			log.Fatal("FATAL: Configuration non chargée")
+		}
+
+		db, err := gorm.Open(sqlite.Open(cfg.Database.Name), &gorm.Config{})
+		if err != nil {
+			log.Fatalf("FATAL: Échec de la connexion à la base de données: %v", err)
+		}
+
+		sqlDB, err := db.DB()
+		if err != nil {
+			log.Fatalf("FATAL: Échec de l'obtention de la base de données SQL sous-jacente: %v", err)
+		}
+		defer sqlDB.Close()
+
+		linkRepo := repository.NewLinkRepository(db)
+		linkService, err := services.NewLinkServiceFromConfig(linkRepo, cfg)
+		if err != nil {
+			log.Fatalf("FATAL: %v", err)
+		}
+
+		link, err := linkService.RefreshDestination(context.Background(), refreshCodeFlag)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				log.Fatalf("ERREUR: Aucun lien trouvé avec le code '%s'", refreshCodeFlag)
+			}
+			log.Fatalf("FATAL: Erreur lors de la récupération de la destination: %v", err)
+		}
+
+		fmt.Printf("Code: %s\n", link.Shortcode)
+		fmt.Printf("URL longue: %s\n", link.LongURL)
+		printDestination(link.Destination)
+		fmt.Println()
+	},
+}
+
+// printDestination affiche les métadonnées de la destination d'un lien, si elles ont été récupérées.
+func printDestination(destination models.DestinationMetadata) {
+	if destination.FetchedAt == nil {
+		return
+	}
+	if destination.Error != "" {
+		fmt.Printf("Destination injoignable: %s\n", destination.Error)
+	} else {
+		fmt.Printf("Destination: %d %s\n", destination.StatusCode, destination.FinalURL)
+	}
+	if destination.ContentType != "" {
+		fmt.Printf("Type de contenu: %s\n", destination.ContentType)
+	}
+	if destination.Title != "" {
+		fmt.Printf("Titre de la page: %s\n", destination.Title)
+	}
+	if destination.OGTitle != "" {
+		fmt.Printf("Titre Open Graph: %s\n", destination.OGTitle)
+	}
+	if destination.OGDescription != "" {
+		fmt.Printf("Description Open Graph: %s\n", destination.OGDescription)
+	}
+	if destination.OGImage != "" {
+		fmt.Printf("Image Open Graph: %s\n", destination.OGImage)
+	}
+	fmt.Printf("Récupérée le: %s\n", destination.FetchedAt.Format(time.RFC3339))
+}
+
+func init() {
+	RefreshCmd.Flags().StringVarP(&refreshCodeFlag, "code", "c", "", "Code court du lien dont la destination est à récupérer")
+	RefreshCmd.MarkFlagRequired("code")
+
+	cmd2.RootCmd.AddCommand(RefreshCmd)
+}
@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	cmd2 "github.com/axellelanca/urlshortener/cmd"
+	"github.com/axellelanca/urlshortener/internal/repository"
+	"github.com/axellelanca/urlshortener/internal/services"
+	"github.com/spf13/cobra"
+
+	// "github.com/glebarez/sqlite" WINDOWS
+	"gorm.io/driver/sqlite" // MAC
+	"gorm.io/gorm"
+)
+
+// restoreCodeFlag stocke la valeur du flag --code
+var restoreCodeFlag string
+
+// RestoreCmd représente la commande 'restore'
+var RestoreCmd = &cobra.Command{
+	Use:   "restore",
+	Short: "Restaure un lien court depuis la corbeille.",
+	Long: `Cette commande sort un lien de la corbeille, à condition qu'il y soit depuis
+moins de la durée de rétention configurée (trash.retention_days).
+
+Exemple:
+  url-shortener restore --code="xyz123"`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if restoreCodeFlag == "" {
+			log.Fatal("FATAL: Le flag --code est requis")
+		}
+
+		cfg := cmd2.Cfg
+		if cfg == nil {
+			log.Fatal("FATAL: Configuration non chargée")
+		}
+
+		db, err := gorm.Open(sqlite.Open(cfg.Database.Name), &gorm.Config{})
+		if err != nil {
+			log.Fatalf("FATAL: Échec de la connexion à la base de données: %v", err)
+		}
+
+		sqlDB, err := db.DB()
+		if err != nil {
+			log.Fatalf("FATAL: Échec de l'obtention de la base de données SQL sous-jacente: %v", err)
+		}
+		defer sqlDB.Close()
+
+		linkRepo := repository.NewLinkRepository(db)
+		linkService, err := services.NewLinkServiceFromConfig(linkRepo, cfg)
+		if err != nil {
+			log.Fatalf("FATAL: %v", err)
+		}
+
+		retention := time.Duration(cfg.Trash.RetentionDays) * 24 * time.Hour
+		if _, err := linkService.RestoreLink(restoreCodeFlag, retention); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				log.Fatalf("ERREUR: Aucun lien à la corbeille avec le code '%s'", restoreCodeFlag)
+			}
+			if errors.Is(err, services.ErrRestoreWindowExpired) {
+				log.Fatalf("ERREUR: Le lien '%s' est à la corbeille depuis plus de %d jours", restoreCodeFlag, cfg.Trash.RetentionDays)
+			}
+			if errors.Is(err, services.ErrURLAlreadyExists) {
+				log.Fatalf("ERREUR: Un autre lien actif pointe déjà vers la même URL")
+			}
+			log.Fatalf("FATAL: Erreur lors de la restauration du lien: %v", err)
+		}
+
+		fmt.Printf("Lien '%s' restauré.\n\n", restoreCodeFlag)
+	},
+}
+
+func init() {
+	RestoreCmd.Flags().StringVarP(&restoreCodeFlag, "code", "c", "", "Code court du lien à restaurer")
+	RestoreCmd.MarkFlagRequired("code")
+
+	cmd2.RootCmd.AddCommand(RestoreCmd)
+}
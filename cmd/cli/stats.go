@@ -4,6 +4,11 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
 
 	cmd2 "github.com/axellelanca/urlshortener/cmd"
 	"github.com/axellelanca/urlshortener/internal/repository"
@@ -18,19 +23,32 @@ import (
 // TODO : variable shortCodeFlag qui stockera la valeur du flag --code
 var shortCodeFlag string
 
+// statsTagsFlag stocke les tags du flag --tag : statistiques agrégées des liens portant tous ces tags
+var statsTagsFlag []string
+
+// statsGroupByFlag stocke le paramètre UTM du flag --group-by : statistiques regroupées par valeur de ce paramètre
+var statsGroupByFlag string
+
 // StatsCmd représente la commande 'stats'
 var StatsCmd = &cobra.Command{
 	Use:   "stats",
-	Short: "Affiche les statistiques (nombre de clics) pour un lien court.",
+	Short: "Affiche les statistiques (nombre de clics) pour un lien court ou un ensemble de liens tagués.",
 	Long: `Cette commande permet de récupérer et d'afficher le nombre total de clics
-pour une URL courte spécifique en utilisant son code.
+pour une URL courte spécifique en utilisant son code, ou pour tous les liens
+portant un ou plusieurs tags, éventuellement regroupés par paramètre UTM.
 
 Exemple:
-  url-shortener stats --code="xyz123"`,
+  url-shortener stats --code="xyz123"
+  url-shortener stats --tag=campaign-q3
+  url-shortener stats --group-by=utm_source --tag=campaign-q3`,
 	Run: func(cmd *cobra.Command, args []string) {
 		// DONE : Valider que le flag --code a été fourni.
-		if shortCodeFlag == "" {
-			log.Fatal("FATAL: Le flag --code est requis")
+		aggregate := len(statsTagsFlag) > 0 || statsGroupByFlag != ""
+		if shortCodeFlag == "" && !aggregate {
+			log.Fatal("FATAL: Le flag --code, --tag ou --group-by est requis")
+		}
+		if shortCodeFlag != "" && aggregate {
+			log.Fatal("FATAL: Le flag --code ne peut pas être combiné avec --tag ou --group-by")
 		}
 
 		// DONE : Charger la configuration chargée globalement via cmd.Cfg
@@ -55,7 +73,19 @@ Exemple:
 
 		// DONE : Initialiser les repositories et services nécessaires NewLinkRepository & NewLinkService
 		linkRepo := repository.NewLinkRepository(db)
-		linkService := services.NewLinkService(linkRepo)
+		linkService, err := services.NewLinkServiceFromConfig(linkRepo, cfg)
+		if err != nil {
+			log.Fatalf("FATAL: %v", err)
+		}
+
+		if statsGroupByFlag != "" {
+			printStatsByUTM(linkService)
+			return
+		}
+		if len(statsTagsFlag) > 0 {
+			printStatsSummary(linkService)
+			return
+		}
 
 		// DONE : Appeler GetLinkStats pour récupérer le lien et ses statistiques.
 		link, totalClicks, err := linkService.GetLinkStats(shortCodeFlag)
@@ -68,18 +98,117 @@ Exemple:
 
 		fmt.Printf("Statistiques pour le code court: %s\n", link.Shortcode)
 		fmt.Printf("URL longue: %s\n", link.LongURL)
-		fmt.Printf("Total de clics: %d\n\n", totalClicks)
+		if link.Title != "" {
+			fmt.Printf("Titre: %s\n", link.Title)
+		}
+		fmt.Printf("Total de clics: %d\n", totalClicks)
+		if link.ExpiresAt != nil {
+			status := "actif"
+			if link.IsExpired(time.Now()) {
+				status = "expiré"
+			}
+			fmt.Printf("Expiration: %s (%s)\n", link.ExpiresAt.Format(time.RFC3339), status)
+		}
+		if link.HasClickLimit() {
+			fmt.Printf("Redirections consommées: %d/%d\n", link.ClickCount, link.MaxClicks)
+		}
+		if totalClicks > 0 {
+			breakdown, err := linkService.GetClickBreakdown(link)
+			if err != nil {
+				log.Fatalf("FATAL: Erreur lors de la récupération des statistiques: %v", err)
+			}
+			printClickBreakdown("Clics par appareil", breakdown.ByDevice)
+			printClickBreakdown("Clics par pays", breakdown.ByCountry)
+			if len(breakdown.ByVariant) > 0 {
+				printClickBreakdown("Clics par variante", breakdown.ByVariant)
+			}
+			printClickBreakdown("Clics par source", breakdown.BySource)
+			if len(breakdown.ByLanguage) > 0 {
+				printClickBreakdown("Clics par langue", breakdown.ByLanguage)
+			}
+		}
+		fmt.Println()
 	},
 }
 
+// printClickBreakdown affiche une répartition des clics, par nombre de clics décroissant.
+func printClickBreakdown(label string, counts map[string]int) {
+	values := make([]string, 0, len(counts))
+	for value := range counts {
+		values = append(values, value)
+	}
+	sort.Slice(values, func(i, j int) bool {
+		if counts[values[i]] != counts[values[j]] {
+			return counts[values[i]] > counts[values[j]]
+		}
+		return values[i] < values[j]
+	})
+	parts := make([]string, 0, len(values))
+	for _, value := range values {
+		parts = append(parts, fmt.Sprintf("%s %d", value, counts[value]))
+	}
+	fmt.Printf("%s: %s\n", label, strings.Join(parts, ", "))
+}
+
+// printStatsSummary affiche les statistiques agrégées des liens portant tous les tags de --tag.
+func printStatsSummary(linkService *services.LinkService) {
+	summary, err := linkService.GetStatsSummary(services.ListLinksParams{Tags: statsTagsFlag})
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidListParams) {
+			log.Fatalf("ERREUR: %v", err)
+		}
+		log.Fatalf("FATAL: Erreur lors de l'agrégation des statistiques: %v", err)
+	}
+
+	fmt.Printf("Statistiques pour le(s) tag(s): %s\n", strings.Join(statsTagsFlag, ", "))
+	fmt.Printf("Nombre de liens: %d\n", summary.TotalLinks)
+	fmt.Printf("Total de clics: %d\n", summary.TotalClicks)
+	if len(summary.TopLinks) > 0 {
+		fmt.Println("Liens les plus cliqués:")
+		for _, link := range summary.TopLinks {
+			fmt.Printf("  %-10s %6d  %s\n", link.Shortcode, link.TotalClicks, link.Title)
+		}
+	}
+	fmt.Println()
+}
+
+// printStatsByUTM affiche les statistiques des liens (filtrés par --tag) regroupées par valeur du paramètre UTM de --group-by.
+func printStatsByUTM(linkService *services.LinkService) {
+	groups, err := linkService.GetStatsByUTM(services.ListLinksParams{Tags: statsTagsFlag}, statsGroupByFlag)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidListParams) {
+			log.Fatalf("ERREUR: %v", err)
+		}
+		log.Fatalf("FATAL: Erreur lors de l'agrégation des statistiques: %v", err)
+	}
+
+	fmt.Printf("Statistiques par %s", statsGroupByFlag)
+	if len(statsTagsFlag) > 0 {
+		fmt.Printf(" (tag(s): %s)", strings.Join(statsTagsFlag, ", "))
+	}
+	fmt.Println()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VALEUR\tLIENS\tCLICS")
+	for _, group := range groups {
+		value := group.Value
+		if value == "" {
+			value = "(aucun)"
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\n", value, group.TotalLinks, group.TotalClicks)
+	}
+	w.Flush()
+	fmt.Println()
+}
+
 // init() s'exécute automatiquement lors de l'importation du package.
 // Il est utilisé pour définir les flags que cette commande accepte.
 func init() {
 	// DONE : Définir le flag --code pour la commande stats.
 	StatsCmd.Flags().StringVarP(&shortCodeFlag, "code", "c", "", "Code court de l'URL")
+	StatsCmd.Flags().StringVar(&statsGroupByFlag, "group-by", "", "Regrouper par paramètre UTM: utm_source, utm_medium, utm_campaign, utm_term ou utm_content")
+	StatsCmd.Flags().StringSliceVarP(&statsTagsFlag, "tag", "t", nil, "Statistiques agrégées des liens portant ce tag (répétable)")
 
-	// DONE Marquer le flag comme requis
-	StatsCmd.MarkFlagRequired("code")
+	// DONE Marquer le flag comme requis : --code ou --tag, vérifié dans Run
 
 	// DONE : Ajouter la commande à RootCmd
 	cmd2.RootCmd.AddCommand(StatsCmd)
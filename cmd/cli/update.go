@@ -0,0 +1,334 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	cmd2 "github.com/axellelanca/urlshortener/cmd"
+	"github.com/axellelanca/urlshortener/internal/models"
+	"github.com/axellelanca/urlshortener/internal/repository"
+	"github.com/axellelanca/urlshortener/internal/services"
+	"github.com/spf13/cobra"
+
+	// "github.com/glebarez/sqlite" WINDOWS
+	"gorm.io/driver/sqlite" // MAC
+	"gorm.io/gorm"
+)
+
+// Flags de la commande update : code du lien à modifier, nouvelle URL et auteur de la modification
+var (
+	updateCodeFlag  string
+	updateURLFlag   string
+	updateActorFlag string
+)
+
+// Flags de métadonnées de la commande update : seuls les flags fournis sont modifiés
+var (
+	updateTitleFlag       string
+	updateDescriptionFlag string
+	updateNotesFlag       string
+	updateTagsFlag        []string
+)
+
+// Flags d'options de redirection de la commande update (--forward-query=false pour désactiver)
+var (
+	updateForwardQueryFlag bool
+	updateForwardPathFlag  bool
+	updatePreviewFlag      bool
+	updateStickyVariant    bool
+	updateRedirectStatus   int
+	updateDeviceFlags      []string
+	updateCountryFlags     []string
+	updateLanguageFlags    []string
+	updateVariantFlags     []string
+)
+
+// Flags de programmation de la commande update (--activates-at="" active le lien immédiatement)
+var (
+	updateActivatesAtFlag string
+	updatePendingURLFlag  string
+	updateScheduleFlags   []string
+)
+
+// UpdateCmd représente la commande 'update'
+var UpdateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Modifie l'URL longue, les métadonnées ou les options de redirection d'un lien court existant.",
+	Long: `Cette commande change la destination et/ou le titre, la description, les notes,
+les tags et les options de redirection d'un lien court existant. Seuls les flags fournis sont modifiés.
+L'ancienne URL est conservée dans l'historique des révisions du lien.
+--tag remplace tous les tags du lien (--tag="" les retire).
+
+Exemple:
+  url-shortener update --code="xyz123" --url="https://www.example.com/nouvelle-page"
+  url-shortener update --code="xyz123" --title="Page produit" --tag produit --tag q3
+  url-shortener update --code="xyz123" --forward-path --forward-query=false
+  url-shortener update --code="xyz123" --redirect-status=301
+  url-shortener update --code="xyz123" --device ios=https://apps.apple.com/app/id123   # --device="" les retire
+  url-shortener update --code="xyz123" --country FR=https://shop.site.fr             # --country="" les retire
+  url-shortener update --code="xyz123" --language fr=https://docs.site.com/fr       # --language="" les retire
+  url-shortener update --code="xyz123" --variant a:50=https://site.com/a --variant b:50=https://site.com/b   # --variant="" met fin au test
+  url-shortener update --code="xyz123" --activates-at=2025-06-25T08:00:00+02:00 --pending-url="https://site.com/bientot"   # --activates-at="" l'active
+  url-shortener update --code="xyz123" --schedule 2025-06-02T18:00:00Z=https://site.com/replay   # --schedule="" les retire`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if updateCodeFlag == "" {
+			log.Fatal("FATAL: Le flag --code est requis")
+		}
+
+		var update services.LinkMetadataUpdate
+		if cmd.Flags().Changed("title") {
+			update.Title = &updateTitleFlag
+		}
+		if cmd.Flags().Changed("description") {
+			update.Description = &updateDescriptionFlag
+		}
+		if cmd.Flags().Changed("notes") {
+			update.Notes = &updateNotesFlag
+		}
+		if cmd.Flags().Changed("tag") {
+			update.Tags = &updateTagsFlag
+		}
+		var redirectUpdate services.LinkRedirectUpdate
+		if cmd.Flags().Changed("forward-query") {
+			redirectUpdate.ForwardQuery = &updateForwardQueryFlag
+		}
+		if cmd.Flags().Changed("forward-path") {
+			redirectUpdate.ForwardPath = &updateForwardPathFlag
+		}
+		if cmd.Flags().Changed("preview") {
+			redirectUpdate.Preview = &updatePreviewFlag
+		}
+		if cmd.Flags().Changed("sticky-variant") {
+			redirectUpdate.StickyVariant = &updateStickyVariant
+		}
+		if cmd.Flags().Changed("redirect-status") {
+			if updateRedirectStatus != 0 {
+				if err := services.ValidateRedirectStatus(updateRedirectStatus); err != nil {
+					log.Fatalf("ERREUR: %v", err)
+				}
+			}
+			redirectUpdate.RedirectStatus = &updateRedirectStatus
+		}
+		// Destinations alternatives à remplacer, par critère
+		targetUpdates := make(map[string]map[string]string)
+		if cmd.Flags().Changed("device") {
+			targetUpdates[models.TargetKindDevice] = parseTargetFlags("device", updateDeviceFlags)
+		}
+		if cmd.Flags().Changed("country") {
+			targetUpdates[models.TargetKindCountry] = parseTargetFlags("country", updateCountryFlags)
+		}
+		if cmd.Flags().Changed("language") {
+			targetUpdates[models.TargetKindLanguage] = parseTargetFlags("language", updateLanguageFlags)
+		}
+		for kind, urls := range targetUpdates {
+			if err := services.ValidateTargets(kind, urls); err != nil {
+				log.Fatalf("ERREUR: %v", err)
+			}
+		}
+		var variants []services.Variant
+		if cmd.Flags().Changed("variant") {
+			variants = parseVariantFlags("variant", updateVariantFlags)
+			if err := services.ValidateVariants(variants); err != nil {
+				log.Fatalf("ERREUR: %v", err)
+			}
+		}
+		var scheduleUpdate services.LinkScheduleUpdate
+		if cmd.Flags().Changed("activates-at") {
+			activatesAt := time.Time{}
+			if updateActivatesAtFlag != "" {
+				parsed, err := time.Parse(time.RFC3339, updateActivatesAtFlag)
+				if err != nil {
+					log.Fatalf("FATAL: --activates-at doit être au format RFC3339 (ex: 2025-06-25T08:00:00+02:00): %v", err)
+				}
+				activatesAt = parsed
+			}
+			scheduleUpdate.ActivatesAt = &activatesAt
+		}
+		if cmd.Flags().Changed("pending-url") {
+			scheduleUpdate.PendingURL = &updatePendingURLFlag
+		}
+		if cmd.Flags().Changed("schedule") {
+			schedule := parseScheduleFlags("schedule", updateScheduleFlags)
+			scheduleUpdate.Schedule = &schedule
+		}
+		if updateURLFlag == "" && update.IsEmpty() && redirectUpdate.IsEmpty() && len(targetUpdates) == 0 && variants == nil && scheduleUpdate.IsEmpty() {
+			log.Fatal("FATAL: Au moins un flag à modifier est requis (--url, --title, --description, --notes, --tag, --forward-query, --forward-path, --preview, --sticky-variant, --redirect-status, --device, --country, --language, --variant, --activates-at, --pending-url ou --schedule)")
+		}
+
+		if updateURLFlag != "" {
+			if _, err := url.ParseRequestURI(updateURLFlag); err != nil {
+				log.Fatalf("FATAL: URL invalide: %v", err)
+			}
+		}
+
+		cfg := cmd2.Cfg
+		if cfg == nil {
+			log.Fatal("FATAL: Configuration non chargée")
+		}
+
+		db, err := gorm.Open(sqlite.Open(cfg.Database.Name), &gorm.Config{})
+		if err != nil {
+			log.Fatalf("FATAL: Échec de la connexion à la base de données: %v", err)
+		}
+
+		sqlDB, err := db.DB()
+		if err != nil {
+			log.Fatalf("FATAL: Échec de l'obtention de la base de données SQL sous-jacente: %v", err)
+		}
+		defer sqlDB.Close()
+
+		linkRepo := repository.NewLinkRepository(db)
+		linkService, err := services.NewLinkServiceFromConfig(linkRepo, cfg)
+		if err != nil {
+			log.Fatalf("FATAL: %v", err)
+		}
+
+		actor := updateActorFlag
+		if actor == "" {
+			actor = defaultActor()
+		}
+
+		// La programmation dépend de la date d'expiration du lien : elle est vérifiée avant toute modification.
+		if !scheduleUpdate.IsEmpty() {
+			link, err := linkService.GetLinkByShortCode(updateCodeFlag)
+			if err != nil {
+				exitOnUpdateError(err)
+			}
+			if err := services.ValidateScheduleUpdate(link, scheduleUpdate); err != nil {
+				log.Fatalf("ERREUR: %v", err)
+			}
+		}
+
+		// Comme pour l'API, toutes les modifications sont enregistrées dans une seule transaction.
+		linkUpdate := services.LinkUpdate{
+			LongURL:  updateURLFlag,
+			Actor:    actor,
+			Metadata: update,
+			Redirect: redirectUpdate,
+			Targets:  targetUpdates,
+			Schedule: scheduleUpdate,
+		}
+		if variants != nil {
+			linkUpdate.Variants = &variants
+		}
+		link, err := linkService.UpdateLink(updateCodeFlag, linkUpdate)
+		if err != nil {
+			exitOnUpdateError(err)
+		}
+
+		fmt.Printf("Lien modifié avec succès:\n")
+		fmt.Printf("Code: %s\n", link.Shortcode)
+		fmt.Printf("URL longue: %s\n", link.LongURL)
+		if link.Title != "" {
+			fmt.Printf("Titre: %s\n", link.Title)
+		}
+		if len(link.Tags) > 0 {
+			fmt.Printf("Tags: %s\n", strings.Join(services.TagNames(link.Tags), ", "))
+		}
+		printRedirectOptions(link)
+		fmt.Println()
+	},
+}
+
+// printRedirectOptions affiche les options de redirection du lien qui diffèrent des valeurs par défaut :
+// éléments de la requête transmis à la destination, destinations par appareil, par pays et par langue, variantes A/B,
+// page d'aperçu, code HTTP de redirection, page d'attente et changements de destination programmés.
+func printRedirectOptions(link *models.Link) {
+	var forwarded []string
+	if link.ForwardQuery {
+		forwarded = append(forwarded, "query string")
+	}
+	if link.ForwardPath {
+		forwarded = append(forwarded, "chemin")
+	}
+	if len(forwarded) > 0 {
+		fmt.Printf("Transmis à la destination: %s\n", strings.Join(forwarded, ", "))
+	}
+	for _, target := range link.Targets {
+		switch target.Kind {
+		case models.TargetKindDevice:
+			fmt.Printf("Destination %s: %s\n", target.Key, target.URL)
+		case models.TargetKindCountry:
+			fmt.Printf("Destination pays %s: %s\n", target.Key, target.URL)
+		case models.TargetKindLanguage:
+			fmt.Printf("Destination langue %s: %s\n", target.Key, target.URL)
+		case models.TargetKindVariant:
+			fmt.Printf("Variante %s (poids %d): %s\n", target.Key, target.Weight, target.URL)
+		}
+	}
+	if link.StickyVariant {
+		fmt.Println("Variante conservée par visiteur: oui")
+	}
+	if link.Preview {
+		fmt.Println("Page d'aperçu: oui")
+	}
+	if link.RedirectStatus != 0 {
+		fmt.Printf("Code de redirection: %d\n", link.RedirectStatus)
+	}
+	if link.ActivatesAt != nil {
+		fmt.Printf("Actif à partir du: %s\n", link.ActivatesAt.Format(time.RFC3339))
+	}
+	if link.PendingURL != "" {
+		fmt.Printf("Page d'attente: %s\n", link.PendingURL)
+	}
+	for _, entry := range link.Schedule {
+		fmt.Printf("Destination à partir du %s: %s\n", entry.SwitchAt.Format(time.RFC3339), entry.URL)
+	}
+}
+
+// exitOnUpdateError affiche l'erreur d'une modification de lien et termine la commande.
+func exitOnUpdateError(err error) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Fatalf("ERREUR: Aucun lien trouvé avec le code '%s'", updateCodeFlag)
+	}
+	if errors.Is(err, services.ErrURLAlreadyExists) {
+		log.Fatalf("ERREUR: Un autre lien court existe déjà pour l'URL '%s'", updateURLFlag)
+	}
+	if errors.Is(err, services.ErrInvalidMetadata) || errors.Is(err, services.ErrInvalidSchedule) ||
+		errors.Is(err, services.ErrInvalidLongURL) {
+		log.Fatalf("ERREUR: %v", err)
+	}
+	log.Fatalf("FATAL: Erreur lors de la modification du lien: %v", err)
+}
+
+// defaultActor retourne l'auteur enregistré dans l'historique pour les modifications faites via la CLI.
+func defaultActor() string {
+	if user := os.Getenv("USER"); user != "" {
+		return "cli:" + user
+	}
+	if user := os.Getenv("USERNAME"); user != "" { // Windows
+		return "cli:" + user
+	}
+	return "cli"
+}
+
+func init() {
+	UpdateCmd.Flags().StringVarP(&updateCodeFlag, "code", "c", "", "Code court du lien à modifier")
+	UpdateCmd.Flags().StringVarP(&updateURLFlag, "url", "u", "", "Nouvelle URL longue")
+	UpdateCmd.Flags().StringVar(&updateActorFlag, "actor", "", "Auteur de la modification (par défaut: utilisateur courant)")
+	UpdateCmd.Flags().StringVar(&updateTitleFlag, "title", "", "Nouveau titre du lien")
+	UpdateCmd.Flags().StringVar(&updateDescriptionFlag, "description", "", "Nouvelle description du lien")
+	UpdateCmd.Flags().StringVar(&updateNotesFlag, "notes", "", "Nouvelles notes internes")
+	UpdateCmd.Flags().BoolVar(&updateForwardQueryFlag, "forward-query", false, "Transmettre la query string des visiteurs à la destination")
+	UpdateCmd.Flags().BoolVar(&updateForwardPathFlag, "forward-path", false, "Transmettre le chemin demandé après le code court à la destination")
+	UpdateCmd.Flags().BoolVar(&updatePreviewFlag, "preview", false, "Afficher une page d'aperçu avant chaque redirection (--preview=false pour la retirer)")
+	UpdateCmd.Flags().StringArrayVar(&updateDeviceFlags, "device", nil, "Destinations par type d'appareil (ios=URL, répétable), remplaçant les actuelles")
+	UpdateCmd.Flags().StringArrayVar(&updateVariantFlags, "variant", nil, "Variantes de test A/B (a:70=URL, répétable), remplaçant les actuelles")
+	UpdateCmd.Flags().BoolVar(&updateStickyVariant, "sticky-variant", false, "Conserver la variante tirée pour chaque visiteur (--sticky-variant=false pour un tirage à chaque clic)")
+	UpdateCmd.Flags().StringArrayVar(&updateCountryFlags, "country", nil, "Destinations par pays du visiteur (FR=URL, répétable), remplaçant les actuelles")
+	UpdateCmd.Flags().StringArrayVar(&updateLanguageFlags, "language", nil, "Destinations par langue du visiteur (fr=URL, répétable), remplaçant les actuelles")
+	UpdateCmd.Flags().IntVar(&updateRedirectStatus, "redirect-status", 0, "Code HTTP de redirection: 301, 302, 307 ou 308 (0 = code de la configuration)")
+	UpdateCmd.Flags().StringVar(&updateActivatesAtFlag, "activates-at", "", "Date d'activation du lien au format RFC3339 (\"\" pour l'activer immédiatement)")
+	UpdateCmd.Flags().StringVar(&updatePendingURLFlag, "pending-url", "", "URL d'attente avant l'activation (\"\" pour celle de la configuration)")
+	UpdateCmd.Flags().StringArrayVar(&updateScheduleFlags, "schedule", nil, "Changements de destination programmés (date=URL, répétable), remplaçant les actuels")
+	UpdateCmd.Flags().StringSliceVarP(&updateTagsFlag, "tag", "t", nil, "Tags du lien, remplaçant les actuels (répétable ou séparé par des virgules)")
+
+	UpdateCmd.MarkFlagRequired("code")
+
+	cmd2.RootCmd.AddCommand(UpdateCmd)
+}
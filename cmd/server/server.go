@@ -47,13 +47,34 @@ puis lance le serveur HTTP.`,
 		// DONE : Initialiser les repositories.
 		linkRepo := repository.NewLinkRepository(db)
 		clickRepo := repository.NewClickRepository(db)
+		campaignRepo := repository.NewCampaignRepository(db)
 
 		// Laissez le log
 		log.Println("Repositories initialisés.")
 
 		// DONE : Initialiser les services métiers.
-		linkService := services.NewLinkService(linkRepo)
+		linkService, err := services.NewLinkServiceFromConfig(linkRepo, cfg)
+		if err != nil {
+			log.Fatalf("FATAL: %v", err)
+		}
+		if cfg.GeoIP.Database != "" {
+			geoIP, err := services.OpenGeoIPDatabase(cfg.GeoIP.Database)
+			if err != nil {
+				log.Fatalf("FATAL: %v", err)
+			}
+			defer geoIP.Close()
+			linkService.SetCountryLocator(geoIP)
+			log.Printf("Base GeoIP chargée: %s", cfg.GeoIP.Database)
+		}
 		clickService := services.NewClickService(clickRepo)
+		campaignService := services.NewCampaignService(campaignRepo, clickRepo)
+		if cfg.Security.Secret == "" {
+			log.Println("WARN: security.secret non défini, une clé aléatoire est utilisée pour les liens protégés.")
+		}
+		accessService, err := services.NewAccessService(cfg.Security.Secret, time.Duration(cfg.Security.UnlockTTLMinutes)*time.Minute)
+		if err != nil {
+			log.Fatalf("FATAL: Échec de l'initialisation du service d'accès: %v", err)
+		}
 
 		// Laissez le log
 		log.Println("Services métiers initialisés.")
@@ -75,7 +96,7 @@ puis lance le serveur HTTP.`,
 
 		// DONE : Configurer le routeur Gin et les handlers API.
 		router := gin.Default()
-		api.SetupRoutes(router, linkService, clickService) // Pas toucher au log
+		api.SetupRoutes(router, cfg, linkService, clickService, accessService, campaignService) // Pas toucher au log
 		log.Println("Routes API configurées.")
 
 		// Créer le serveur HTTP Gin
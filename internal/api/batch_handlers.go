@@ -0,0 +1,106 @@
+package api
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/axellelanca/urlshortener/internal/apperr"
+	"github.com/axellelanca/urlshortener/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// maxBatchSize est le nombre maximum de liens acceptés par requête de création en lot.
+const maxBatchSize = 5000
+
+// CreateLinksBatchRequest représente le corps de la requête JSON de création de liens en lot.
+// Les éléments ne sont pas validés par Gin : chaque erreur est reportée dans le résultat de l'élément.
+type CreateLinksBatchRequest struct {
+	Links []CreateLinkRequest `json:"links" binding:"required,min=1"`
+}
+
+// CreateLinksBatchHandler gère la création de plusieurs liens en une seule requête.
+// La réponse contient un résultat par élément, dans l'ordre de la requête : un élément en échec
+// (URL déjà existante, alias invalide...) n'interrompt pas le lot. Les URLs courtes complètes sont construites avec baseURL.
+func CreateLinksBatchHandler(linkService *services.LinkService, baseURL string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req CreateLinksBatchRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			apperr.HandleError(c, apperr.ErrInvalidRequest("Le champ 'links' doit contenir au moins un élément", err))
+			return
+		}
+		if len(req.Links) > maxBatchSize {
+			apperr.HandleError(c, apperr.ErrInvalidRequest("Un lot ne peut pas dépasser 5000 liens", nil))
+			return
+		}
+
+		results := make([]gin.H, len(req.Links))
+		requests := make([]services.BatchLinkRequest, 0, len(req.Links))
+		positions := make([]int, 0, len(req.Links)) // Index dans req.Links de chaque élément envoyé au service
+		for i, item := range req.Links {
+			opts, appErr := item.options()
+			if appErr != nil {
+				results[i] = batchErrorResult(i, item.LongURL, appErr)
+				continue
+			}
+			requests = append(requests, services.BatchLinkRequest{LongURL: item.LongURL, Options: opts})
+			positions = append(positions, i)
+		}
+
+		batchResults, err := linkService.CreateLinksBatch(requests)
+		if err != nil {
+			apperr.HandleError(c, apperr.ErrDatabaseOperation("création des liens en lot", err))
+			return
+		}
+
+		created := 0
+		for _, result := range batchResults {
+			i := positions[result.Index]
+			if result.Err != nil {
+				appErr := createLinkError(result.Err, req.Links[i])
+				if appErr.InternalErr != nil {
+					log.Printf("[ERROR] Lot, élément %d: %v", i, appErr.InternalErr)
+				}
+				results[i] = batchErrorResult(i, result.LongURL, appErr)
+				continue
+			}
+			status := "created"
+			if result.Existing {
+				status = "existing"
+			} else {
+				created++
+			}
+			results[i] = gin.H{
+				"index":          i,
+				"status":         status,
+				"short_code":     result.Link.Shortcode,
+				"long_url":       result.Link.LongURL,
+				"full_short_url": services.ShortURL(baseURL, result.Link.Shortcode),
+			}
+		}
+
+		failed := 0
+		for _, result := range results {
+			if result["status"] == "error" {
+				failed++
+			}
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"total":    len(req.Links),
+			"created":  created,
+			"existing": len(req.Links) - created - failed,
+			"failed":   failed,
+			"results":  results,
+		})
+		c.Writer.Write([]byte("\n"))
+	}
+}
+
+// batchErrorResult construit le résultat d'un élément en échec.
+func batchErrorResult(index int, longURL string, appErr *apperr.AppError) gin.H {
+	return gin.H{
+		"index":    index,
+		"status":   "error",
+		"long_url": longURL,
+		"error":    appErr.ToJSON()["error"],
+	}
+}
@@ -0,0 +1,301 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/axellelanca/urlshortener/internal/apperr"
+	"github.com/axellelanca/urlshortener/internal/models"
+	"github.com/axellelanca/urlshortener/internal/services"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// CampaignRequest représente le corps de la requête JSON pour la création ou le remplacement d'une campagne.
+type CampaignRequest struct {
+	Name        string     `json:"name" binding:"required"`
+	Description string     `json:"description"`
+	StartsAt    *time.Time `json:"starts_at"` // Début de la campagne (optionnel, RFC3339)
+	EndsAt      *time.Time `json:"ends_at"`   // Fin de la campagne (optionnelle, RFC3339)
+}
+
+// input convertit la requête en champs de campagne pour le CampaignService.
+func (req CampaignRequest) input() services.CampaignInput {
+	return services.CampaignInput{
+		Name:        req.Name,
+		Description: req.Description,
+		StartsAt:    req.StartsAt,
+		EndsAt:      req.EndsAt,
+	}
+}
+
+// CampaignLinksRequest représente le corps de la requête JSON pour rattacher des liens à une campagne.
+type CampaignLinksRequest struct {
+	ShortCodes []string `json:"short_codes" binding:"required,min=1"`
+}
+
+// campaignID lit l'ID de campagne du chemin. Un ID non numérique est traité comme une campagne introuvable.
+func campaignID(c *gin.Context) (uint, *apperr.AppError) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return 0, apperr.ErrCampaignNotFound(c.Param("id"))
+	}
+	return uint(id), nil
+}
+
+// campaignError traduit une erreur du CampaignService en erreur applicative.
+func campaignError(c *gin.Context, err error, operation string) *apperr.AppError {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return apperr.ErrCampaignNotFound(c.Param("id"))
+	case errors.Is(err, services.ErrInvalidCampaign), errors.Is(err, services.ErrUnknownShortCodes):
+		return apperr.ErrInvalidRequest(err.Error(), err)
+	}
+	return apperr.ErrDatabaseOperation(operation, err)
+}
+
+// campaignResponse construit la représentation JSON d'une campagne.
+// Les liens ne sont inclus que s'ils ont été chargés ; la destination d'un lien protégé n'est pas exposée.
+func campaignResponse(campaign *models.Campaign, withLinks bool) gin.H {
+	response := gin.H{
+		"id":          campaign.ID,
+		"name":        campaign.Name,
+		"description": campaign.Description,
+		"starts_at":   campaign.StartsAt,
+		"ends_at":     campaign.EndsAt,
+		"running":     campaign.IsRunning(time.Now()),
+		"created_at":  campaign.CreatedAt,
+	}
+	if withLinks {
+		now := time.Now()
+		links := make([]gin.H, 0, len(campaign.Links))
+		for _, link := range campaign.Links {
+			item := gin.H{
+				"short_code": link.Shortcode,
+				"long_url":   link.LongURL,
+				"title":      link.Title,
+				"status":     link.Status(now),
+			}
+			maskProtected(item, &link)
+			links = append(links, item)
+		}
+		response["links"] = links
+	}
+	return response
+}
+
+// ListCampaignsHandler gère le listage des campagnes, avec leur nombre de liens.
+func ListCampaignsHandler(campaignService *services.CampaignService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		campaigns, err := campaignService.ListCampaigns()
+		if err != nil {
+			apperr.HandleError(c, apperr.ErrDatabaseOperation("listage des campagnes", err))
+			return
+		}
+
+		items := make([]gin.H, 0, len(campaigns))
+		for i := range campaigns {
+			item := campaignResponse(&campaigns[i].Campaign, false)
+			item["link_count"] = campaigns[i].LinkCount
+			items = append(items, item)
+		}
+		c.JSON(http.StatusOK, gin.H{"campaigns": items})
+		c.Writer.Write([]byte("\n"))
+	}
+}
+
+// CreateCampaignHandler gère la création d'une campagne.
+func CreateCampaignHandler(campaignService *services.CampaignService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req CampaignRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			apperr.HandleError(c, apperr.ErrInvalidRequest("Vérifiez le format de la requête et que tous les champs requis sont présents", err))
+			return
+		}
+
+		campaign, err := campaignService.CreateCampaign(req.input())
+		if err != nil {
+			if errors.Is(err, services.ErrCampaignAlreadyExists) {
+				apperr.HandleError(c, apperr.ErrCampaignAlreadyExists(req.Name))
+				return
+			}
+			apperr.HandleError(c, campaignError(c, err, "création de la campagne"))
+			return
+		}
+
+		c.JSON(http.StatusCreated, campaignResponse(campaign, false))
+		c.Writer.Write([]byte("\n"))
+	}
+}
+
+// GetCampaignHandler gère la récupération d'une campagne et de ses liens.
+func GetCampaignHandler(campaignService *services.CampaignService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, appErr := campaignID(c)
+		if appErr != nil {
+			apperr.HandleError(c, appErr)
+			return
+		}
+
+		campaign, err := campaignService.GetCampaign(id)
+		if err != nil {
+			apperr.HandleError(c, campaignError(c, err, "récupération de la campagne"))
+			return
+		}
+
+		c.JSON(http.StatusOK, campaignResponse(campaign, true))
+		c.Writer.Write([]byte("\n"))
+	}
+}
+
+// UpdateCampaignHandler gère le remplacement du nom, de la description et de la période d'une campagne.
+// Les champs absents sont remis à vide : une date omise retire la borne correspondante.
+func UpdateCampaignHandler(campaignService *services.CampaignService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, appErr := campaignID(c)
+		if appErr != nil {
+			apperr.HandleError(c, appErr)
+			return
+		}
+
+		var req CampaignRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			apperr.HandleError(c, apperr.ErrInvalidRequest("Vérifiez le format de la requête et que tous les champs requis sont présents", err))
+			return
+		}
+
+		campaign, err := campaignService.UpdateCampaign(id, req.input())
+		if err != nil {
+			if errors.Is(err, services.ErrCampaignAlreadyExists) {
+				apperr.HandleError(c, apperr.ErrCampaignAlreadyExists(req.Name))
+				return
+			}
+			apperr.HandleError(c, campaignError(c, err, "modification de la campagne"))
+			return
+		}
+
+		c.JSON(http.StatusOK, campaignResponse(campaign, false))
+		c.Writer.Write([]byte("\n"))
+	}
+}
+
+// DeleteCampaignHandler gère la suppression d'une campagne. Ses liens sont conservés, sans campagne.
+func DeleteCampaignHandler(campaignService *services.CampaignService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, appErr := campaignID(c)
+		if appErr != nil {
+			apperr.HandleError(c, appErr)
+			return
+		}
+
+		if _, err := campaignService.DeleteCampaign(id); err != nil {
+			apperr.HandleError(c, campaignError(c, err, "suppression de la campagne"))
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// AddCampaignLinksHandler gère le rattachement de liens (par code court) à une campagne.
+func AddCampaignLinksHandler(campaignService *services.CampaignService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, appErr := campaignID(c)
+		if appErr != nil {
+			apperr.HandleError(c, appErr)
+			return
+		}
+
+		var req CampaignLinksRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			apperr.HandleError(c, apperr.ErrInvalidRequest("Le champ 'short_codes' doit contenir au moins un code court", err))
+			return
+		}
+
+		campaign, err := campaignService.AddLinks(id, req.ShortCodes)
+		if err != nil {
+			apperr.HandleError(c, campaignError(c, err, "ajout de liens à la campagne"))
+			return
+		}
+
+		c.JSON(http.StatusOK, campaignResponse(campaign, true))
+		c.Writer.Write([]byte("\n"))
+	}
+}
+
+// RemoveCampaignLinkHandler gère le retrait d'un lien d'une campagne.
+func RemoveCampaignLinkHandler(campaignService *services.CampaignService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, appErr := campaignID(c)
+		if appErr != nil {
+			apperr.HandleError(c, appErr)
+			return
+		}
+
+		if _, err := campaignService.RemoveLinks(id, []string{c.Param("shortCode")}); err != nil {
+			apperr.HandleError(c, campaignError(c, err, "retrait du lien de la campagne"))
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// GetCampaignStatsHandler gère les statistiques agrégées d'une campagne : clics totaux, par lien et par jour.
+// Paramètres de requête optionnels : from et to (RFC3339 ou YYYY-MM-DD), par défaut la période de la campagne.
+func GetCampaignStatsHandler(campaignService *services.CampaignService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, appErr := campaignID(c)
+		if appErr != nil {
+			apperr.HandleError(c, appErr)
+			return
+		}
+
+		var since, until *time.Time
+		for name, target := range map[string]**time.Time{"from": &since, "to": &until} {
+			if value := c.Query(name); value != "" {
+				date, err := services.ParseDateFilter(value)
+				if err != nil {
+					apperr.HandleError(c, apperr.ErrInvalidRequest(err.Error(), err))
+					return
+				}
+				*target = date
+			}
+		}
+
+		stats, err := campaignService.GetCampaignStats(id, since, until)
+		if err != nil {
+			apperr.HandleError(c, campaignError(c, err, "statistiques de la campagne"))
+			return
+		}
+
+		links := make([]gin.H, 0, len(stats.Links))
+		for _, item := range stats.Links {
+			links = append(links, gin.H{
+				"short_code": item.Link.Shortcode,
+				"clicks":     item.Clicks,
+			})
+		}
+		daily := make([]gin.H, 0, len(stats.Daily))
+		for _, day := range stats.Daily {
+			daily = append(daily, gin.H{
+				"date":   day.Day,
+				"clicks": day.Clicks,
+			})
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"campaign_id":  stats.Campaign.ID,
+			"name":         stats.Campaign.Name,
+			"from":         stats.Since,
+			"to":           stats.Until,
+			"total_links":  len(stats.Links),
+			"total_clicks": stats.TotalClicks,
+			"links":        links,
+			"daily":        daily,
+		})
+		c.Writer.Write([]byte("\n"))
+	}
+}
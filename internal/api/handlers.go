@@ -2,11 +2,16 @@ package api
 
 import (
 	"errors"
+	"fmt"
 	"log"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/axellelanca/urlshortener/internal/apperr"
+	"github.com/axellelanca/urlshortener/internal/config"
 	"github.com/axellelanca/urlshortener/internal/models"
 	"github.com/axellelanca/urlshortener/internal/services"
 	"github.com/gin-gonic/gin"
@@ -21,22 +26,175 @@ var ClickEventsChan chan<- models.ClickEvent
 // aux workers asynchrones. Il est bufferisé pour ne pas bloquer les requêtes de redirection.
 // PHASE 3 : Pas utilisé pour l'instant (sans async)
 
+// Nom du header permettant aux clients de l'API de fournir le mot de passe d'un lien protégé.
+const linkPasswordHeader = "X-Link-Password"
+
 // SetupRoutes configure toutes les routes de l'API Gin et injecte les dépendances nécessaires
-func SetupRoutes(router *gin.Engine, linkService *services.LinkService, clickService *services.ClickService) {
+func SetupRoutes(router *gin.Engine, cfg *config.Config, linkService *services.LinkService, clickService *services.ClickService, accessService *services.AccessService, campaignService *services.CampaignService) {
 	// PHASE 3 : Pas de channel pour l'instant (sans async)
 
+	// Pages HTML (formulaire de mot de passe des liens protégés)
+	router.SetHTMLTemplate(pageTemplates)
+
 	// DONE : Route de Health Check , /health
 	router.GET("/health", HealthCheckHandler)
 
 	// DONE : Routes de l'API
 	// Doivent être au format /api/v1/
-	router.POST("/api/v1/links", CreateShortLinkHandler(linkService))
-	router.GET("/api/v1/links/:shortCode", GetLinkInfoHandler(linkService))
-	router.GET("/api/v1/links/:shortCode/stats", GetLinkStatsHandler(linkService))
+	router.GET("/api/v1/links", ListLinksHandler(linkService))
+	router.POST("/api/v1/links", CreateShortLinkHandler(linkService, cfg.Server.BaseURL))
+	router.POST("/api/v1/links/batch", CreateLinksBatchHandler(linkService, cfg.Server.BaseURL))
+	router.GET("/api/v1/lookup", LookupLinksHandler(linkService, cfg.Server.BaseURL))
+	router.GET("/api/v1/stats", GetStatsSummaryHandler(linkService))
+	router.GET("/api/v1/links/:shortCode", GetLinkInfoHandler(linkService, accessService))
+	router.PATCH("/api/v1/links/:shortCode", UpdateLinkHandler(linkService, accessService))
+	router.DELETE("/api/v1/links/:shortCode", DeleteLinkHandler(linkService, accessService))
+	router.POST("/api/v1/links/:shortCode/disable", SetLinkDisabledHandler(linkService, accessService, true))
+	router.POST("/api/v1/links/:shortCode/enable", SetLinkDisabledHandler(linkService, accessService, false))
+	router.POST("/api/v1/links/:shortCode/restore", RestoreLinkHandler(linkService, accessService, time.Duration(cfg.Trash.RetentionDays)*24*time.Hour))
+	router.GET("/api/v1/links/:shortCode/stats", GetLinkStatsHandler(linkService, accessService))
+	router.GET("/api/v1/links/:shortCode/history", GetLinkHistoryHandler(linkService, accessService))
+	router.POST("/api/v1/links/:shortCode/destination/refresh", RefreshLinkDestinationHandler(linkService, accessService))
+	router.GET("/api/v1/links/:shortCode/qr", QRCodeHandler(linkService, cfg.Server.BaseURL))
+
+	// Campagnes : regroupement de liens et statistiques agrégées
+	router.GET("/api/v1/campaigns", ListCampaignsHandler(campaignService))
+	router.POST("/api/v1/campaigns", CreateCampaignHandler(campaignService))
+	router.GET("/api/v1/campaigns/:id", GetCampaignHandler(campaignService))
+	router.PUT("/api/v1/campaigns/:id", UpdateCampaignHandler(campaignService))
+	router.DELETE("/api/v1/campaigns/:id", DeleteCampaignHandler(campaignService))
+	router.POST("/api/v1/campaigns/:id/links", AddCampaignLinksHandler(campaignService))
+	router.DELETE("/api/v1/campaigns/:id/links/:shortCode", RemoveCampaignLinkHandler(campaignService))
+	router.GET("/api/v1/campaigns/:id/stats", GetCampaignStatsHandler(campaignService))
 
 	// Route de Redirection (au niveau racine pour les short codes)
 	// IMPORTANT: Doit être APRÈS les routes /api/v1/ pour éviter les conflits
-	router.GET("/:shortCode", RedirectHandler(linkService, clickService))
+	router.GET("/:shortCode", RedirectHandler(linkService, clickService, accessService))
+	// Chemin après le code court, transmis à la destination pour les liens qui l'autorisent (forward_path)
+	router.GET("/:shortCode/*path", RedirectHandler(linkService, clickService, accessService))
+	// Soumission des formulaires HTML : mot de passe d'un lien protégé, ou "Continuer" de la page d'aperçu
+	router.POST("/:shortCode", LinkFormHandler(linkService, clickService, accessService))
+	router.POST("/:shortCode/*path", LinkFormHandler(linkService, clickService, accessService))
+}
+
+// redirectRequest extrait de la requête le chemin après le code court et la query string,
+// transmissibles à la destination selon les options du lien, le User-Agent, l'adresse IP
+// et les langues acceptées utilisés pour le ciblage, et la source du clic (scan d'un QR code).
+func redirectRequest(c *gin.Context) services.RedirectRequest {
+	return services.RedirectRequest{
+		Path:           c.Param("path"),
+		RawQuery:       c.Request.URL.RawQuery,
+		UserAgent:      c.Request.UserAgent(),
+		ClientIP:       c.ClientIP(),
+		AcceptLanguage: c.GetHeader("Accept-Language"),
+		Source:         services.ClickSource(c.Request.URL.RawQuery),
+	}
+}
+
+// wantsJSON indique si le client demande explicitement du JSON (Accept: application/json)
+// plutôt qu'une redirection HTTP.
+func wantsJSON(c *gin.Context) bool {
+	for _, accept := range c.Request.Header.Values("Accept") {
+		for _, mediaType := range strings.Split(accept, ",") {
+			mediaType, _, _ = strings.Cut(mediaType, ";")
+			if strings.EqualFold(strings.TrimSpace(mediaType), gin.MIMEJSON) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// shortLinkPath reconstruit le chemin de la requête sur le lien court (code, suffixe et query string),
+// utilisé comme cible des formulaires HTML et de la redirection qui suit le déverrouillage.
+func shortLinkPath(shortCode string, req services.RedirectRequest) string {
+	target := url.URL{Path: "/" + shortCode + req.Path, RawQuery: req.RawQuery}
+	return target.RequestURI()
+}
+
+// unlockCookieName retourne le nom du cookie de déverrouillage propre à un lien.
+func unlockCookieName(link *models.Link) string {
+	return "unlock_" + link.Shortcode
+}
+
+// variantCookieMaxAge est la durée pendant laquelle un visiteur conserve la variante A/B tirée pour un lien.
+const variantCookieMaxAge = 30 * 24 * time.Hour
+
+// variantCookieName retourne le nom du cookie qui conserve la variante A/B tirée pour un lien.
+func variantCookieName(link *models.Link) string {
+	return "variant_" + link.Shortcode
+}
+
+// hasAccess indique si l'appelant peut accéder à la destination d'un lien.
+// Un lien protégé nécessite un cookie de déverrouillage valide ou le mot de passe dans le header X-Link-Password,
+// dont les essais sont limités par client (voir denyAccess).
+func hasAccess(c *gin.Context, link *models.Link, accessService *services.AccessService) bool {
+	if !link.IsProtected() {
+		return true
+	}
+	if token, err := c.Cookie(unlockCookieName(link)); err == nil && accessService.VerifyToken(link, token, time.Now()) {
+		return true
+	}
+	if password := c.GetHeader(linkPasswordHeader); password != "" {
+		return accessService.CheckPassword(link, password, c.ClientIP(), time.Now())
+	}
+	return false
+}
+
+// denyAccess répond à un appelant de l'API sans accès à un lien protégé : 429 (avec Retry-After)
+// s'il a épuisé ses essais de mot de passe, 401 sinon.
+func denyAccess(c *gin.Context, link *models.Link, accessService *services.AccessService) {
+	if wait := retryAfter(c, link, accessService); wait > 0 {
+		apperr.HandleError(c, apperr.ErrTooManyPasswordAttempts(link.Shortcode, wait))
+		return
+	}
+	apperr.HandleError(c, apperr.ErrLinkPasswordRequired(link.Shortcode))
+}
+
+// renderPasswordForm affiche le formulaire de mot de passe d'un lien protégé, qui renvoie vers action :
+// en 429 (avec Retry-After) si le visiteur a épuisé ses essais, en 401 sinon avec le message errorMessage éventuel.
+func renderPasswordForm(c *gin.Context, link *models.Link, accessService *services.AccessService, action, errorMessage string) {
+	status := http.StatusUnauthorized
+	if wait := retryAfter(c, link, accessService); wait > 0 {
+		status = http.StatusTooManyRequests
+		errorMessage = fmt.Sprintf("Trop de tentatives. Réessayez dans %d min.", (wait+time.Minute-1)/time.Minute)
+	}
+	c.HTML(status, "password.html", gin.H{
+		"ShortCode": link.Shortcode,
+		"Action":    action,
+		"Error":     errorMessage,
+	})
+}
+
+// retryAfter retourne le temps pendant lequel les essais de mot de passe de l'appelant sur un lien sont refusés
+// et, s'il est positif, le renseigne dans le header Retry-After (en secondes, arrondi au supérieur).
+func retryAfter(c *gin.Context, link *models.Link, accessService *services.AccessService) time.Duration {
+	wait := accessService.RetryAfter(link, c.ClientIP(), time.Now())
+	if wait > 0 {
+		c.Header("Retry-After", strconv.Itoa(int((wait+time.Second-1)/time.Second)))
+	}
+	return wait
+}
+
+// protectedFields liste les champs de réponse masqués pour un lien protégé : sa destination, sous toutes ses formes,
+// et les métadonnées qui pourraient la décrire. Toute réponse décrivant un lien passe par maskProtected.
+var protectedFields = []string{
+	"long_url", "domain", "expired_url", "activates_at", "pending_url", "schedule",
+	"title", "description", "notes", "tags",
+	"device_urls", "country_urls", "language_urls", "variants",
+	"destination", "page_title", "destination_status",
+}
+
+// maskProtected retire d'une réponse décrivant un lien protégé les champs de protectedFields et signale la protection.
+// Sans effet sur un lien public ; les handlers qui acceptent un déverrouillage ne l'appellent que sans accès (hasAccess).
+func maskProtected(item gin.H, link *models.Link) {
+	if !link.IsProtected() {
+		return
+	}
+	for _, field := range protectedFields {
+		delete(item, field)
+	}
+	item["password_protected"] = true
 }
 
 // HealthCheckHandler gère la route /health pour vérifier l'état du service.
@@ -49,10 +207,145 @@ func HealthCheckHandler(c *gin.Context) {
 // CreateLinkRequest représente le corps de la requête JSON pour la création d'un lien.
 type CreateLinkRequest struct {
 	LongURL string `json:"long_url" binding:"required,url"` // 'binding:required' pour validation, 'url' pour format URL
+	Alias   string `json:"alias"`                           // Code court personnalisé (optionnel)
+
+	// Expiration (optionnelle) : soit une date absolue, soit une durée relative (ex: "72h")
+	ExpiresAt  *time.Time `json:"expires_at"`
+	TTL        string     `json:"ttl"`
+	ExpiredURL string     `json:"expired_url" binding:"omitempty,url"` // URL de repli une fois le lien expiré
+
+	// Activation différée (optionnelle) : avant activates_at, redirection vers pending_url (ou l'URL d'attente de la configuration)
+	ActivatesAt *time.Time `json:"activates_at"`
+	PendingURL  string     `json:"pending_url" binding:"omitempty,url"`
+	// Changements de destination programmés : [{"at": "2025-06-01T09:00:00Z", "url": "..."}, ...] (optionnels)
+	Schedule []services.ScheduledURL `json:"schedule"`
+
+	// Limite de redirections (optionnelle) : nombre maximum de clics, ou lien à usage unique
+	MaxClicks int  `json:"max_clicks"`
+	OneTime   bool `json:"one_time"`
+
+	Password string `json:"password"` // Mot de passe de protection (optionnel, stocké hashé)
+
+	// Métadonnées (optionnelles) : titre, description, notes internes et tags
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Notes       string   `json:"notes"`
+	Tags        []string `json:"tags"`
+
+	// Paramètres UTM (optionnels) ajoutés à la query string de long_url, éventuellement à partir d'un preset
+	UTMPreset   string `json:"utm_preset"`
+	UTMSource   string `json:"utm_source"`
+	UTMMedium   string `json:"utm_medium"`
+	UTMCampaign string `json:"utm_campaign"`
+	UTMTerm     string `json:"utm_term"`
+	UTMContent  string `json:"utm_content"`
+
+	// Transmission à la destination de la query string et du chemin demandé après le code court (optionnelle)
+	ForwardQuery bool `json:"forward_query"`
+	ForwardPath  bool `json:"forward_path"`
+
+	// Destinations alternatives par type d'appareil : {"ios": "...", "android": "...", "desktop": "..."} (optionnelles)
+	DeviceURLs map[string]string `json:"device_urls"`
+	// Destinations alternatives par pays du visiteur (GeoIP) : {"FR": "...", "DE": "..."} (optionnelles)
+	CountryURLs map[string]string `json:"country_urls"`
+	// Destinations alternatives par langue du visiteur (Accept-Language) : {"fr": "...", "en": "..."} (optionnelles)
+	LanguageURLs map[string]string `json:"language_urls"`
+
+	// Test A/B : destinations tirées au sort selon leur poids, [{"id": "a", "url": "...", "weight": 70}, ...] (optionnelles),
+	// et conservation de la variante tirée pour chaque visiteur (cookie)
+	Variants      []services.Variant `json:"variants"`
+	StickyVariant bool               `json:"sticky_variant"`
+
+	Preview        bool `json:"preview"`         // Page d'aperçu systématique avant la redirection (optionnelle)
+	RedirectStatus int  `json:"redirect_status"` // 301, 302, 307 ou 308 (optionnel, code de la configuration par défaut)
+
+	// Récupération en arrière-plan des métadonnées de la destination (optionnelle, destination.fetch_on_create par défaut)
+	FetchDestination *bool `json:"fetch_destination"`
+}
+
+// options convertit la requête en options de création pour le LinkService.
+func (req CreateLinkRequest) options() (services.CreateLinkOptions, *apperr.AppError) {
+	opts := services.CreateLinkOptions{
+		Alias:      req.Alias,
+		ExpiresAt:  req.ExpiresAt,
+		ExpiredURL: req.ExpiredURL,
+		MaxClicks:  req.MaxClicks,
+		OneTime:    req.OneTime,
+		Password:   req.Password,
+
+		ActivatesAt: req.ActivatesAt,
+		PendingURL:  req.PendingURL,
+		Schedule:    req.Schedule,
+
+		Title:       req.Title,
+		Description: req.Description,
+		Notes:       req.Notes,
+		Tags:        req.Tags,
+
+		UTMPreset: req.UTMPreset,
+		UTM: services.UTMParams{
+			Source:   req.UTMSource,
+			Medium:   req.UTMMedium,
+			Campaign: req.UTMCampaign,
+			Term:     req.UTMTerm,
+			Content:  req.UTMContent,
+		},
+
+		ForwardQuery: req.ForwardQuery,
+		ForwardPath:  req.ForwardPath,
+
+		DeviceURLs:     req.DeviceURLs,
+		CountryURLs:    req.CountryURLs,
+		LanguageURLs:   req.LanguageURLs,
+		Variants:       req.Variants,
+		StickyVariant:  req.StickyVariant,
+		Preview:        req.Preview,
+		RedirectStatus: req.RedirectStatus,
+
+		FetchDestination: req.FetchDestination,
+	}
+	if req.TTL != "" {
+		ttl, err := time.ParseDuration(req.TTL)
+		if err != nil {
+			return opts, apperr.ErrInvalidRequest("Le champ 'ttl' doit être une durée valide (ex: 30m, 72h)", err)
+		}
+		opts.TTL = ttl
+	}
+	return opts, nil
+}
+
+// createLinkError traduit une erreur de LinkService.CreateLink en erreur applicative.
+func createLinkError(err error, req CreateLinkRequest) *apperr.AppError {
+	// Vérifier si c'est une erreur de lien existant
+	if errors.Is(err, services.ErrURLAlreadyExists) {
+		return apperr.ErrLinkAlreadyExists(req.LongURL)
+	}
+	if errors.Is(err, services.ErrInvalidLongURL) {
+		return apperr.ErrInvalidURL(req.LongURL)
+	}
+	// Vérifier si l'alias demandé est invalide ou déjà pris
+	if errors.Is(err, services.ErrInvalidAlias) {
+		return apperr.ErrInvalidShortCode(req.Alias).WithDetails(err.Error())
+	}
+	if errors.Is(err, services.ErrAliasAlreadyExists) {
+		return apperr.ErrShortCodeAlreadyExists(req.Alias)
+	}
+	if errors.Is(err, services.ErrInvalidExpiration) || errors.Is(err, services.ErrInvalidClickLimit) ||
+		errors.Is(err, services.ErrInvalidPassword) || errors.Is(err, services.ErrInvalidMetadata) ||
+		errors.Is(err, services.ErrInvalidUTM) || errors.Is(err, services.ErrInvalidRedirectStatus) ||
+		errors.Is(err, services.ErrInvalidTarget) || errors.Is(err, services.ErrInvalidSchedule) {
+		return apperr.ErrInvalidRequest(err.Error(), err)
+	}
+	// Vérifier si c'est une erreur de collision de code court
+	if errors.Is(err, services.ErrShortCodeCollision) {
+		return apperr.ErrInternalServer("Impossible de générer un code court unique", err)
+	}
+	return apperr.ErrFailedToCreateLink(err)
 }
 
 // CreateShortLinkHandler gère la création d'une URL courte.
-func CreateShortLinkHandler(linkService *services.LinkService) gin.HandlerFunc {
+// L'URL courte complète (full_short_url) est construite avec baseURL, l'URL publique du serveur.
+func CreateShortLinkHandler(linkService *services.LinkService, baseURL string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var req CreateLinkRequest
 		// DONE : Tente de lier le JSON de la requête à la structure CreateLinkRequest.
@@ -62,101 +355,377 @@ func CreateShortLinkHandler(linkService *services.LinkService) gin.HandlerFunc {
 			return
 		}
 
+		opts, appErr := req.options()
+		if appErr != nil {
+			apperr.HandleError(c, appErr)
+			return
+		}
+
 		// DONE: Appeler le LinkService (CreateLink) pour créer le nouveau lien.
-		link, err := linkService.CreateLink(req.LongURL)
+		// Avec la politique de doublons return_existing, le lien existant est retourné (created = false).
+		link, created, err := linkService.CreateLink(req.LongURL, opts)
 		if err != nil {
-			// Vérifier si c'est une erreur de lien existant
-			if errors.Is(err, services.ErrURLAlreadyExists) {
-				apperr.HandleError(c, apperr.ErrLinkAlreadyExists(req.LongURL))
-				return
-			}
-			// Vérifier si c'est une erreur de collision de code court
-			if errors.Is(err, services.ErrShortCodeCollision) {
-				apperr.HandleError(c, apperr.ErrInternalServer("Impossible de générer un code court unique", err))
-				return
-			}
-			apperr.HandleError(c, apperr.ErrFailedToCreateLink(err))
+			apperr.HandleError(c, createLinkError(err, req))
 			return
 		}
 
 		// Retourne le code court et l'URL longue dans la réponse JSON.
 		// DONE Choisir le bon code HTTP
-		c.JSON(http.StatusCreated, gin.H{
+		response := gin.H{
 			"short_code":     link.Shortcode,
 			"long_url":       link.LongURL,
-			"full_short_url": "http://localhost:8080/" + link.Shortcode,
-		})
+			"full_short_url": services.ShortURL(baseURL, link.Shortcode),
+		}
+		if link.ExpiresAt != nil {
+			response["expires_at"] = link.ExpiresAt
+		}
+		if link.ActivatesAt != nil {
+			response["activates_at"] = link.ActivatesAt
+		}
+		if link.PendingURL != "" {
+			response["pending_url"] = link.PendingURL
+		}
+		if len(link.Schedule) > 0 {
+			response["schedule"] = services.LinkScheduleEntries(link.Schedule)
+		}
+		if link.HasClickLimit() {
+			response["max_clicks"] = link.MaxClicks
+		}
+		if link.IsProtected() {
+			response["password_protected"] = true
+		}
+		if link.Title != "" {
+			response["title"] = link.Title
+		}
+		if len(link.Tags) > 0 {
+			response["tags"] = services.TagNames(link.Tags)
+		}
+		if link.ForwardQuery {
+			response["forward_query"] = true
+		}
+		if link.ForwardPath {
+			response["forward_path"] = true
+		}
+		if deviceURLs := services.TargetURLs(link.Targets, models.TargetKindDevice); len(deviceURLs) > 0 {
+			response["device_urls"] = deviceURLs
+		}
+		if countryURLs := services.TargetURLs(link.Targets, models.TargetKindCountry); len(countryURLs) > 0 {
+			response["country_urls"] = countryURLs
+		}
+		if languageURLs := services.TargetURLs(link.Targets, models.TargetKindLanguage); len(languageURLs) > 0 {
+			response["language_urls"] = languageURLs
+		}
+		if variants := services.LinkVariants(link.Targets); len(variants) > 0 {
+			response["variants"] = variants
+		}
+		if link.StickyVariant {
+			response["sticky_variant"] = true
+		}
+		if link.Preview {
+			response["preview"] = true
+		}
+		if link.RedirectStatus != 0 {
+			response["redirect_status"] = link.RedirectStatus
+		}
+		status := http.StatusCreated
+		if !created {
+			status = http.StatusOK
+			response["existing"] = true
+		}
+		c.JSON(status, response)
 		c.Writer.Write([]byte("\n"))
 	}
 }
 
 // RedirectHandler gère la redirection d'une URL courte vers l'URL longue.
-func RedirectHandler(linkService *services.LinkService, clickService *services.ClickService) gin.HandlerFunc {
+// Un '+' après le code court (/aB3Xy9+) affiche la page d'aperçu au lieu de rediriger,
+// comme pour les liens dont l'aperçu est systématique (preview).
+func RedirectHandler(linkService *services.LinkService, clickService *services.ClickService, accessService *services.AccessService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// DONE Récupère le shortCode de l'URL avec c.Param
-		shortCode := c.Param("shortCode")
+		shortCode, previewRequested := strings.CutSuffix(c.Param("shortCode"), "+")
+
+		link, destination, ok := resolveRedirect(c, linkService, accessService, shortCode, c.Param("shortCode"))
+		if !ok {
+			return
+		}
+
+		if previewRequested || link.Preview {
+			renderPreview(c, linkService, link, destination)
+			return
+		}
+		redirectToDestination(c, linkService, clickService, link, destination, linkService.RedirectStatus(link))
+	}
+}
+
+// resolveRedirect récupère le lien d'une requête de redirection et calcule sa destination.
+// Elle écrit elle-même la réponse et retourne ok = false si la redirection ne peut pas avoir lieu :
+// lien introuvable, désactivé, expiré ou ayant atteint sa limite de clics, chemin non transmis, ou lien protégé pas encore déverrouillé
+// (le formulaire de mot de passe renvoie alors vers requestedCode, tel que demandé).
+func resolveRedirect(c *gin.Context, linkService *services.LinkService, accessService *services.AccessService, shortCode, requestedCode string) (*models.Link, services.Destination, bool) {
+	// DONE: Récupérer l'URL longue associée au shortCode depuis le linkService (GetLinkByShortCode)
+	link, err := linkService.GetLinkByShortCode(shortCode)
+	if err != nil {
+		// Si le lien n'est pas trouvé, retourner HTTP 404 Not Found.
+		// Utiliser errors.Is et l'erreur Gorm
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			apperr.HandleError(c, apperr.ErrLinkNotFound(shortCode))
+			return nil, services.Destination{}, false
+		}
+		// Gérer d'autres erreurs potentielles de la base de données ou du service
+		apperr.HandleError(c, apperr.ErrDatabaseOperation("récupération du lien", err))
+		return nil, services.Destination{}, false
+	}
+
+	// Un lien désactivé renvoie 410 Gone, ses statistiques restent consultables.
+	if link.Disabled {
+		apperr.HandleError(c, apperr.ErrLinkDisabled(shortCode))
+		return nil, services.Destination{}, false
+	}
+
+	// Un lien expiré renvoie 410 Gone, ou redirige vers son URL de repli si elle est définie.
+	// Aucun clic n'est enregistré dans ce cas.
+	if link.IsExpired(time.Now()) {
+		if link.ExpiredURL != "" {
+			c.Header("Cache-Control", "no-store")
+			c.Redirect(http.StatusFound, link.ExpiredURL)
+			return nil, services.Destination{}, false
+		}
+		apperr.HandleError(c, apperr.ErrLinkExpired(shortCode))
+		return nil, services.Destination{}, false
+	}
+
+	// Un lien ayant consommé toutes ses redirections renvoie 410 Gone, y compris sur la page d'aperçu :
+	// elle révélerait sinon sa destination. La limite reste réservée atomiquement par ConsumeClick.
+	if link.IsExhausted() {
+		apperr.HandleError(c, apperr.ErrLinkClickLimitReached(shortCode))
+		return nil, services.Destination{}, false
+	}
+
+	// Un lien pas encore activé redirige vers son URL d'attente (ou celle de la configuration),
+	// ou renvoie 404 s'il n'en a pas. Aucun clic n'est enregistré dans ce cas.
+	if link.IsPending(time.Now()) {
+		if pendingURL := linkService.PendingURL(link); pendingURL != "" {
+			c.Header("Cache-Control", "no-store")
+			c.Redirect(http.StatusFound, pendingURL)
+			return nil, services.Destination{}, false
+		}
+		apperr.HandleError(c, apperr.ErrLinkNotYetActive(shortCode, *link.ActivatesAt))
+		return nil, services.Destination{}, false
+	}
+
+	// Un lien protégé sans déverrouillage valide affiche le formulaire de mot de passe, avant tout calcul
+	// de la destination : la réponse ne dépend pas de ses options, et aucun changement programmé n'est appliqué.
+	redirectReq := redirectRequest(c)
+	if !hasAccess(c, link, accessService) {
+		renderPasswordForm(c, link, accessService, shortLinkPath(requestedCode, redirectReq), "")
+		return nil, services.Destination{}, false
+	}
+
+	// Un chemin après le code court n'est accepté que si le lien le transmet à sa destination.
+	redirectReq.Variant, _ = c.Cookie(variantCookieName(link))
+	destination, err := linkService.ResolveDestination(link, redirectReq)
+	if err != nil {
+		if errors.Is(err, services.ErrPathForwardingDisabled) {
+			apperr.HandleError(c, apperr.ErrLinkNotFound(shortCode).WithDetails(err.Error()))
+			return nil, services.Destination{}, false
+		}
+		apperr.HandleError(c, apperr.ErrInternalServer("Impossible de construire l'URL de destination", err))
+		return nil, services.Destination{}, false
+	}
+
+	// Un lien à variante persistante mémorise la variante tirée : le visiteur la retrouve aux clics suivants,
+	// et après la page d'aperçu.
+	if link.StickyVariant && destination.Variant != "" && destination.Variant != redirectReq.Variant {
+		c.SetCookie(variantCookieName(link), destination.Variant, int(variantCookieMaxAge.Seconds()), "/"+link.Shortcode, "", c.Request.TLS != nil, true)
+	}
+	return link, destination, true
+}
+
+// redirectToDestination enregistre le clic puis redirige vers la destination avec le code status,
+// ou renvoie la destination en JSON si le client le demande.
+func redirectToDestination(c *gin.Context, linkService *services.LinkService, clickService *services.ClickService, link *models.Link, destination services.Destination, status int) {
+	// Pour un lien limité, la redirection est réservée atomiquement en base avant
+	// l'envoi du ClickEvent : la limite est respectée même si les workers
+	// n'ont pas encore persisté les clics précédents.
+	if err := linkService.ConsumeClick(link); err != nil {
+		if errors.Is(err, services.ErrClickLimitReached) {
+			apperr.HandleError(c, apperr.ErrLinkClickLimitReached(link.Shortcode))
+			return
+		}
+		apperr.HandleError(c, apperr.ErrDatabaseOperation("réservation du clic", err))
+		return
+	}
+
+	// Créer un ClickEvent avec les informations pertinentes.
+	userAgent := c.Request.UserAgent()
+	ipAddress := c.ClientIP()
+
+	// Créer l'objet Click
+	click := &models.Click{
+		LinkID:    link.ID,
+		Timestamp: time.Now(),
+		UserAgent: userAgent,
+		IPAddress: ipAddress,
+		Device:    destination.Device,
+		Country:   destination.Country,
+		Variant:   destination.Variant,
+		Language:  destination.Language,
+		Source:    destination.Source,
+	}
+
+	// DONE : Créer un ClickEvent et l'envoyer dans le channel (async)
+	evt := models.ClickEvent{
+		LinkID:    link.ID,
+		Timestamp: time.Now(),
+		UserAgent: userAgent,
+		IPAddress: ipAddress,
+		Device:    destination.Device,
+		Country:   destination.Country,
+		Variant:   destination.Variant,
+		Language:  destination.Language,
+		Source:    destination.Source,
+	}
+
+	if ClickEventsChan != nil {
+		select {
+		case ClickEventsChan <- evt:
+			// envoyé de façon asynchrone
+		default:
+			log.Printf("WARN: click event dropped for link %d", link.ID)
+		}
+	} else {
+		// Fallback synchrone si le channel n'est pas configuré
+		if err := clickService.RecordClick(click); err != nil {
+			log.Printf("Error recording click for link %d: %v", link.ID, err)
+		}
+	}
+
+	// Un client qui demande explicitement du JSON reçoit la destination au lieu d'être redirigé.
+	if wantsJSON(c) {
+		c.JSON(http.StatusOK, gin.H{
+			"short_code":      link.Shortcode,
+			"long_url":        link.LongURL,
+			"destination":     destination.URL,
+			"device":          destination.Device,
+			"country":         destination.Country,
+			"variant":         destination.Variant,
+			"language":        destination.Language,
+			"redirect_status": status,
+		})
+		c.Writer.Write([]byte("\n"))
+		return
+	}
+
+	// REDIRECTION HTTP (302 par défaut, ou code du lien), avec l'en-tête de cache correspondant
+	c.Header("Cache-Control", linkService.RedirectCacheControl(link, status))
+	c.Redirect(status, destination.URL)
+}
+
+// unlockLinkHandler gère la soumission du formulaire de mot de passe d'un lien protégé (voir LinkFormHandler).
+// Si le mot de passe est correct, il dépose un cookie de déverrouillage signé et de courte durée,
+// puis redirige vers le lien court (avec le chemin et la query string demandés) qui effectue alors la redirection habituelle.
+// Les essais sont limités par visiteur : une fois épuisés, le formulaire est réaffiché en 429 sans vérifier le mot de passe.
+func unlockLinkHandler(linkService *services.LinkService, accessService *services.AccessService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Le '+' d'une demande d'aperçu est conservé dans la cible de la redirection qui suit.
+		shortCode, _ := strings.CutSuffix(c.Param("shortCode"), "+")
 
-		// DONE: Récupérer l'URL longue associée au shortCode depuis le linkService (GetLinkByShortCode)
 		link, err := linkService.GetLinkByShortCode(shortCode)
 		if err != nil {
-			// Si le lien n'est pas trouvé, retourner HTTP 404 Not Found.
-			// Utiliser errors.Is et l'erreur Gorm
 			if errors.Is(err, gorm.ErrRecordNotFound) {
 				apperr.HandleError(c, apperr.ErrLinkNotFound(shortCode))
 				return
 			}
-			// Gérer d'autres erreurs potentielles de la base de données ou du service
 			apperr.HandleError(c, apperr.ErrDatabaseOperation("récupération du lien", err))
 			return
 		}
 
-		// Créer un ClickEvent avec les informations pertinentes.
-		userAgent := c.Request.UserAgent()
-		ipAddress := c.ClientIP()
-
-		// Créer l'objet Click
-		click := &models.Click{
-			LinkID:    link.ID,
-			UserAgent: userAgent,
-			IPAddress: ipAddress,
+		target := shortLinkPath(c.Param("shortCode"), redirectRequest(c))
+		if !link.IsProtected() {
+			c.Redirect(http.StatusSeeOther, target)
+			return
 		}
 
-		// DONE : Créer un ClickEvent et l'envoyer dans le channel (async)
-		evt := models.ClickEvent{
-			LinkID:    link.ID,
-			Timestamp: time.Now(),
-			UserAgent: userAgent,
-			IPAddress: ipAddress,
+		if !accessService.CheckPassword(link, c.PostForm("password"), c.ClientIP(), time.Now()) {
+			renderPasswordForm(c, link, accessService, target, "Mot de passe incorrect.")
+			return
 		}
 
-		if ClickEventsChan != nil {
-			select {
-			case ClickEventsChan <- evt:
-				// envoyé de façon asynchrone
-			default:
-				log.Printf("WARN: click event dropped for link %d", link.ID)
-			}
-		} else {
-			// Fallback synchrone si le channel n'est pas configuré
-			if err := clickService.RecordClick(click); err != nil {
-				log.Printf("Error recording click for link %d: %v", link.ID, err)
+		// Le cookie couvre tout le site : un chemin "/<code>" ne correspondrait pas à l'aperçu "/<code>+",
+		// et le formulaire serait réaffiché indéfiniment. Son nom et sa signature restent propres au lien.
+		token := accessService.IssueToken(link, time.Now())
+		c.SetSameSite(http.SameSiteLaxMode)
+		c.SetCookie(unlockCookieName(link), token, int(accessService.TTL().Seconds()), "/", "", c.Request.TLS != nil, true)
+		c.Redirect(http.StatusSeeOther, target)
+	}
+}
+
+// GetLinkInfoHandler gère la récupération des informations d'un lien sans redirection.
+// La destination d'un lien protégé n'est exposée qu'aux appelants authentifiés.
+func GetLinkInfoHandler(linkService *services.LinkService, accessService *services.AccessService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		shortCode := c.Param("shortCode")
+
+		link, err := linkService.GetLinkInfo(shortCode)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				apperr.HandleError(c, apperr.ErrLinkNotFound(shortCode))
+				return
 			}
+			apperr.HandleError(c, apperr.ErrDatabaseOperation("récupération des informations du lien", err))
+			return
 		}
 
-		// Retourner l'URL en JSON au lieu de rediriger
-		c.JSON(http.StatusOK, gin.H{
-			"short_code": link.Shortcode,
-			"long_url":   link.LongURL,
-		})
-		c.Writer.Write([]byte("\n"))
+		if link.IsExpired(time.Now()) {
+			apperr.HandleError(c, apperr.ErrLinkExpired(shortCode))
+			return
+		}
 
-		// REDIRECTION HTTP 302 :
-		// c.Redirect(http.StatusFound, link.LongURL)
+		response := gin.H{
+			"short_code":         link.Shortcode,
+			"long_url":           link.LongURL,
+			"created_at":         link.CreatedAt,
+			"expires_at":         link.ExpiresAt,
+			"activates_at":       link.ActivatesAt,
+			"pending_url":        link.PendingURL,
+			"schedule":           services.LinkScheduleEntries(link.Schedule),
+			"password_protected": link.IsProtected(),
+			"status":             link.Status(time.Now()),
+			"title":              link.Title,
+			"description":        link.Description,
+			"notes":              link.Notes,
+			"tags":               services.TagNames(link.Tags),
+			"forward_query":      link.ForwardQuery,
+			"forward_path":       link.ForwardPath,
+			"device_urls":        services.TargetURLs(link.Targets, models.TargetKindDevice),
+			"country_urls":       services.TargetURLs(link.Targets, models.TargetKindCountry),
+			"language_urls":      services.TargetURLs(link.Targets, models.TargetKindLanguage),
+			"variants":           services.LinkVariants(link.Targets),
+			"sticky_variant":     link.StickyVariant,
+			"preview":            link.Preview,
+			"redirect_status":    linkService.RedirectStatus(link),
+			"destination":        services.LinkDestinationInfo(link.Destination),
+		}
+		if link.CampaignID != nil {
+			response["campaign_id"] = *link.CampaignID
+		}
+		if link.HasClickLimit() {
+			response["max_clicks"] = link.MaxClicks
+			response["remaining_clicks"] = link.MaxClicks - link.ClickCount
+		}
+		// Les métadonnées d'un lien protégé peuvent décrire sa destination : elles sont masquées comme elle.
+		if !hasAccess(c, link, accessService) {
+			maskProtected(response, link)
+		}
+		c.JSON(http.StatusOK, response)
+		c.Writer.Write([]byte("\n"))
 	}
 }
 
-// GetLinkInfoHandler gère la récupération des informations d'un lien sans redirection.
-func GetLinkInfoHandler(linkService *services.LinkService) gin.HandlerFunc {
+// RefreshLinkDestinationHandler récupère immédiatement les métadonnées de la destination d'un lien
+// (URL finale, code HTTP, titre, balises Open Graph) et les retourne. Un lien protégé exige d'être déverrouillé.
+func RefreshLinkDestinationHandler(linkService *services.LinkService, accessService *services.AccessService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		shortCode := c.Param("shortCode")
 
@@ -166,20 +735,32 @@ func GetLinkInfoHandler(linkService *services.LinkService) gin.HandlerFunc {
 				apperr.HandleError(c, apperr.ErrLinkNotFound(shortCode))
 				return
 			}
-			apperr.HandleError(c, apperr.ErrDatabaseOperation("récupération des informations du lien", err))
+			apperr.HandleError(c, apperr.ErrDatabaseOperation("récupération du lien", err))
+			return
+		}
+		if !hasAccess(c, link, accessService) {
+			denyAccess(c, link, accessService)
+			return
+		}
+
+		link, err = linkService.RefreshDestination(c.Request.Context(), shortCode)
+		if err != nil {
+			apperr.HandleError(c, apperr.ErrDatabaseOperation("récupération des métadonnées de la destination", err))
 			return
 		}
 
 		c.JSON(http.StatusOK, gin.H{
-			"short_code": link.Shortcode,
-			"long_url":   link.LongURL,
+			"short_code":  link.Shortcode,
+			"long_url":    link.LongURL,
+			"destination": services.LinkDestinationInfo(link.Destination),
 		})
 		c.Writer.Write([]byte("\n"))
 	}
 }
 
 // GetLinkStatsHandler gère la récupération des statistiques pour un lien spécifique.
-func GetLinkStatsHandler(linkService *services.LinkService) gin.HandlerFunc {
+// Comme pour GetLinkInfoHandler, la destination d'un lien protégé n'est pas exposée sans authentification.
+func GetLinkStatsHandler(linkService *services.LinkService, accessService *services.AccessService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// DONE Récupère le shortCode de l'URL avec c.Param
 		shortCode := c.Param("shortCode")
@@ -197,11 +778,269 @@ func GetLinkStatsHandler(linkService *services.LinkService) gin.HandlerFunc {
 			return
 		}
 
+		if link.IsExpired(time.Now()) {
+			apperr.HandleError(c, apperr.ErrLinkExpired(shortCode))
+			return
+		}
+
+		breakdown, err := linkService.GetClickBreakdown(link)
+		if err != nil {
+			apperr.HandleError(c, apperr.ErrDatabaseOperation("récupération des statistiques", err))
+			return
+		}
+
 		// Retourne les statistiques dans la réponse JSON.
+		response := gin.H{
+			"short_code":         link.Shortcode,
+			"long_url":           link.LongURL,
+			"total_clicks":       totalClicks,
+			"clicks_by_device":   breakdown.ByDevice,
+			"clicks_by_country":  breakdown.ByCountry,
+			"clicks_by_variant":  breakdown.ByVariant,
+			"clicks_by_language": breakdown.ByLanguage,
+			"clicks_by_source":   breakdown.BySource,
+		}
+		if !hasAccess(c, link, accessService) {
+			maskProtected(response, link)
+		}
+		c.JSON(http.StatusOK, response)
+		c.Writer.Write([]byte("\n"))
+	}
+}
+
+// UpdateLinkRequest représente le corps de la requête JSON pour la modification d'un lien.
+// Seuls les champs présents sont modifiés ; au moins un champ doit être fourni.
+type UpdateLinkRequest struct {
+	LongURL string `json:"long_url" binding:"omitempty,url"` // Nouvelle URL de destination
+	Actor   string `json:"actor"`                            // Auteur de la modification (optionnel, "api" par défaut)
+
+	Title       *string   `json:"title"`
+	Description *string   `json:"description"`
+	Notes       *string   `json:"notes"`
+	Tags        *[]string `json:"tags"` // Remplace tous les tags du lien ([] pour les retirer)
+
+	ForwardQuery *bool `json:"forward_query"`
+	ForwardPath  *bool `json:"forward_path"`
+
+	Preview        *bool `json:"preview"`
+	RedirectStatus *int  `json:"redirect_status"` // 0 rétablit le code de la configuration
+
+	DeviceURLs   *map[string]string `json:"device_urls"`   // Remplace les destinations par type d'appareil ({} pour les retirer)
+	CountryURLs  *map[string]string `json:"country_urls"`  // Remplace les destinations par pays ({} pour les retirer)
+	LanguageURLs *map[string]string `json:"language_urls"` // Remplace les destinations par langue ({} pour les retirer)
+
+	Variants      *[]services.Variant `json:"variants"` // Remplace les variantes A/B ([] pour mettre fin au test)
+	StickyVariant *bool               `json:"sticky_variant"`
+
+	ActivatesAt *string                  `json:"activates_at"` // Date RFC 3339 ("" pour activer le lien immédiatement)
+	PendingURL  *string                  `json:"pending_url"`  // URL d'attente avant l'activation ("" pour la configuration)
+	Schedule    *[]services.ScheduledURL `json:"schedule"`     // Remplace les changements de destination programmés ([] pour les retirer)
+}
+
+// scheduleUpdate extrait de la requête la modification de la programmation du lien.
+func (req UpdateLinkRequest) scheduleUpdate() (services.LinkScheduleUpdate, error) {
+	update := services.LinkScheduleUpdate{PendingURL: req.PendingURL, Schedule: req.Schedule}
+	if req.ActivatesAt != nil {
+		activatesAt := time.Time{}
+		if *req.ActivatesAt != "" {
+			parsed, err := time.Parse(time.RFC3339, *req.ActivatesAt)
+			if err != nil {
+				return update, err
+			}
+			activatesAt = parsed
+		}
+		update.ActivatesAt = &activatesAt
+	}
+	return update, nil
+}
+
+// targetUpdates extrait de la requête les destinations alternatives à remplacer, par critère.
+func (req UpdateLinkRequest) targetUpdates() map[string]map[string]string {
+	updates := make(map[string]map[string]string)
+	if req.DeviceURLs != nil {
+		updates[models.TargetKindDevice] = *req.DeviceURLs
+	}
+	if req.CountryURLs != nil {
+		updates[models.TargetKindCountry] = *req.CountryURLs
+	}
+	if req.LanguageURLs != nil {
+		updates[models.TargetKindLanguage] = *req.LanguageURLs
+	}
+	return updates
+}
+
+// metadataUpdate extrait de la requête la modification des métadonnées.
+func (req UpdateLinkRequest) metadataUpdate() services.LinkMetadataUpdate {
+	return services.LinkMetadataUpdate{
+		Title:       req.Title,
+		Description: req.Description,
+		Notes:       req.Notes,
+		Tags:        req.Tags,
+	}
+}
+
+// redirectUpdate extrait de la requête la modification des options de redirection.
+func (req UpdateLinkRequest) redirectUpdate() services.LinkRedirectUpdate {
+	return services.LinkRedirectUpdate{
+		ForwardQuery: req.ForwardQuery,
+		ForwardPath:  req.ForwardPath,
+
+		Preview:        req.Preview,
+		StickyVariant:  req.StickyVariant,
+		RedirectStatus: req.RedirectStatus,
+	}
+}
+
+// UpdateLinkHandler gère la modification de l'URL longue, des métadonnées et/ou des options de redirection d'un lien existant.
+// Chaque modification de l'URL longue est conservée dans l'historique des révisions.
+func UpdateLinkHandler(linkService *services.LinkService, accessService *services.AccessService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		shortCode := c.Param("shortCode")
+
+		var req UpdateLinkRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			apperr.HandleError(c, apperr.ErrInvalidRequest("Vérifiez le format de la requête et que tous les champs requis sont présents", err))
+			return
+		}
+		scheduleUpdate, err := req.scheduleUpdate()
+		if err != nil {
+			apperr.HandleError(c, apperr.ErrInvalidRequest("Le champ 'activates_at' doit être une date RFC 3339 (ex: 2025-06-01T09:00:00Z)", err))
+			return
+		}
+		update := services.LinkUpdate{
+			LongURL:  req.LongURL,
+			Actor:    req.Actor,
+			Metadata: req.metadataUpdate(),
+			Redirect: req.redirectUpdate(),
+			Targets:  req.targetUpdates(),
+			Variants: req.Variants,
+			Schedule: scheduleUpdate,
+		}
+		if update.IsEmpty() {
+			apperr.HandleError(c, apperr.ErrInvalidRequest("Au moins un champ à modifier est requis (long_url, title, description, notes, tags, forward_query, forward_path, preview, sticky_variant, redirect_status, device_urls, country_urls, language_urls, variants, activates_at, pending_url ou schedule)", nil))
+			return
+		}
+		if req.Variants != nil {
+			if err := services.ValidateVariants(*req.Variants); err != nil {
+				apperr.HandleError(c, apperr.ErrInvalidRequest(err.Error(), err))
+				return
+			}
+		}
+		for kind, urls := range update.Targets {
+			if err := services.ValidateTargets(kind, urls); err != nil {
+				apperr.HandleError(c, apperr.ErrInvalidRequest(err.Error(), err))
+				return
+			}
+		}
+		if req.RedirectStatus != nil && *req.RedirectStatus != 0 {
+			if err := services.ValidateRedirectStatus(*req.RedirectStatus); err != nil {
+				apperr.HandleError(c, apperr.ErrInvalidRequest(err.Error(), err))
+				return
+			}
+		}
+		if update.Actor == "" {
+			update.Actor = "api"
+		}
+
+		link, err := linkService.GetLinkByShortCode(shortCode)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				apperr.HandleError(c, apperr.ErrLinkNotFound(shortCode))
+				return
+			}
+			apperr.HandleError(c, apperr.ErrDatabaseOperation("récupération du lien", err))
+			return
+		}
+		if !hasAccess(c, link, accessService) {
+			denyAccess(c, link, accessService)
+			return
+		}
+		// La programmation dépend de la date d'expiration du lien : elle est vérifiée une fois le lien récupéré.
+		if !scheduleUpdate.IsEmpty() {
+			if err := services.ValidateScheduleUpdate(link, scheduleUpdate); err != nil {
+				apperr.HandleError(c, apperr.ErrInvalidRequest(err.Error(), err))
+				return
+			}
+		}
+
+		// Toutes les modifications sont enregistrées dans une seule transaction : une URL longue déjà utilisée
+		// par un autre lien (409) ou une valeur invalide (400) n'en laisse aucune à moitié appliquée.
+		link, err = linkService.UpdateLink(shortCode, update)
+		if err != nil {
+			if errors.Is(err, services.ErrURLAlreadyExists) {
+				apperr.HandleError(c, apperr.ErrLinkAlreadyExists(req.LongURL))
+				return
+			}
+			if errors.Is(err, services.ErrInvalidLongURL) {
+				apperr.HandleError(c, apperr.ErrInvalidURL(req.LongURL))
+				return
+			}
+			if errors.Is(err, services.ErrInvalidMetadata) || errors.Is(err, services.ErrInvalidRedirectStatus) ||
+				errors.Is(err, services.ErrInvalidTarget) || errors.Is(err, services.ErrInvalidSchedule) {
+				apperr.HandleError(c, apperr.ErrInvalidRequest(err.Error(), err))
+				return
+			}
+			apperr.HandleError(c, apperr.ErrDatabaseOperation("modification du lien", err))
+			return
+		}
 		c.JSON(http.StatusOK, gin.H{
-			"short_code":   link.Shortcode,
-			"long_url":     link.LongURL,
-			"total_clicks": totalClicks,
+			"short_code":      link.Shortcode,
+			"long_url":        link.LongURL,
+			"title":           link.Title,
+			"description":     link.Description,
+			"notes":           link.Notes,
+			"tags":            services.TagNames(link.Tags),
+			"forward_query":   link.ForwardQuery,
+			"forward_path":    link.ForwardPath,
+			"preview":         link.Preview,
+			"sticky_variant":  link.StickyVariant,
+			"variants":        services.LinkVariants(link.Targets),
+			"redirect_status": linkService.RedirectStatus(link),
+			"device_urls":     services.TargetURLs(link.Targets, models.TargetKindDevice),
+			"country_urls":    services.TargetURLs(link.Targets, models.TargetKindCountry),
+			"language_urls":   services.TargetURLs(link.Targets, models.TargetKindLanguage),
+			"activates_at":    link.ActivatesAt,
+			"pending_url":     link.PendingURL,
+			"schedule":        services.LinkScheduleEntries(link.Schedule),
+		})
+		c.Writer.Write([]byte("\n"))
+	}
+}
+
+// GetLinkHistoryHandler gère la récupération de l'historique des modifications d'un lien.
+// L'historique contient les destinations passées : il est soumis aux mêmes règles d'accès que le lien.
+func GetLinkHistoryHandler(linkService *services.LinkService, accessService *services.AccessService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		shortCode := c.Param("shortCode")
+
+		link, revisions, err := linkService.GetLinkHistory(shortCode)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				apperr.HandleError(c, apperr.ErrLinkNotFound(shortCode))
+				return
+			}
+			apperr.HandleError(c, apperr.ErrDatabaseOperation("récupération de l'historique", err))
+			return
+		}
+		if !hasAccess(c, link, accessService) {
+			denyAccess(c, link, accessService)
+			return
+		}
+
+		history := make([]gin.H, 0, len(revisions))
+		for _, rev := range revisions {
+			history = append(history, gin.H{
+				"old_url":    rev.OldURL,
+				"new_url":    rev.NewURL,
+				"actor":      rev.Actor,
+				"changed_at": rev.CreatedAt,
+			})
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"short_code": link.Shortcode,
+			"long_url":   link.LongURL,
+			"history":    history,
 		})
 		c.Writer.Write([]byte("\n"))
 	}
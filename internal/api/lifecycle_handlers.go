@@ -0,0 +1,126 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/axellelanca/urlshortener/internal/apperr"
+	"github.com/axellelanca/urlshortener/internal/services"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// DeleteLinkHandler gère la suppression d'un lien.
+// Par défaut le lien est mis à la corbeille (restaurable) ; avec ?purge=true il est supprimé
+// définitivement avec ses clics et son historique.
+func DeleteLinkHandler(linkService *services.LinkService, accessService *services.AccessService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		shortCode := c.Param("shortCode")
+		purge := c.Query("purge") == "true"
+
+		link, err := linkService.GetLinkByShortCode(shortCode)
+		if errors.Is(err, gorm.ErrRecordNotFound) && purge {
+			link, err = linkService.GetDeletedLinkByShortCode(shortCode)
+		}
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				apperr.HandleError(c, apperr.ErrLinkNotFound(shortCode))
+				return
+			}
+			apperr.HandleError(c, apperr.ErrDatabaseOperation("récupération du lien", err))
+			return
+		}
+		if !hasAccess(c, link, accessService) {
+			denyAccess(c, link, accessService)
+			return
+		}
+
+		if purge {
+			_, err = linkService.PurgeLink(shortCode)
+		} else {
+			_, err = linkService.DeleteLink(shortCode)
+		}
+		if err != nil {
+			apperr.HandleError(c, apperr.ErrDatabaseOperation("suppression du lien", err))
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// SetLinkDisabledHandler gère la désactivation (disabled = true) ou la réactivation d'un lien.
+func SetLinkDisabledHandler(linkService *services.LinkService, accessService *services.AccessService, disabled bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		shortCode := c.Param("shortCode")
+
+		link, err := linkService.GetLinkByShortCode(shortCode)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				apperr.HandleError(c, apperr.ErrLinkNotFound(shortCode))
+				return
+			}
+			apperr.HandleError(c, apperr.ErrDatabaseOperation("récupération du lien", err))
+			return
+		}
+		if !hasAccess(c, link, accessService) {
+			denyAccess(c, link, accessService)
+			return
+		}
+
+		link, err = linkService.SetLinkDisabled(shortCode, disabled)
+		if err != nil {
+			apperr.HandleError(c, apperr.ErrDatabaseOperation("modification du statut du lien", err))
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"short_code": link.Shortcode,
+			"status":     link.Status(time.Now()),
+		})
+		c.Writer.Write([]byte("\n"))
+	}
+}
+
+// RestoreLinkHandler gère la restauration d'un lien depuis la corbeille,
+// tant qu'il y est depuis moins de la durée de rétention configurée.
+func RestoreLinkHandler(linkService *services.LinkService, accessService *services.AccessService, retention time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		shortCode := c.Param("shortCode")
+
+		link, err := linkService.GetDeletedLinkByShortCode(shortCode)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				apperr.HandleError(c, apperr.ErrResourceNotFound("corbeille/"+shortCode))
+				return
+			}
+			apperr.HandleError(c, apperr.ErrDatabaseOperation("récupération du lien supprimé", err))
+			return
+		}
+		if !hasAccess(c, link, accessService) {
+			denyAccess(c, link, accessService)
+			return
+		}
+
+		restored, err := linkService.RestoreLink(shortCode, retention)
+		if err != nil {
+			if errors.Is(err, services.ErrRestoreWindowExpired) {
+				apperr.HandleError(c, apperr.ErrRestoreWindowExpired(shortCode))
+				return
+			}
+			if errors.Is(err, services.ErrURLAlreadyExists) {
+				apperr.HandleError(c, apperr.ErrLinkAlreadyExists(link.LongURL))
+				return
+			}
+			apperr.HandleError(c, apperr.ErrDatabaseOperation("restauration du lien", err))
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"short_code": restored.Shortcode,
+			"status":     restored.Status(time.Now()),
+		})
+		c.Writer.Write([]byte("\n"))
+	}
+}
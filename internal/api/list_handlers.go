@@ -0,0 +1,216 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/axellelanca/urlshortener/internal/apperr"
+	"github.com/axellelanca/urlshortener/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// listParamsFromQuery lit les paramètres de listage communs aux routes de liste et de statistiques :
+// limit, cursor, sort, order, domain, created_after, created_before, status et tag.
+// Le paramètre tag peut être répété ou contenir plusieurs tags séparés par des virgules.
+func listParamsFromQuery(c *gin.Context) (services.ListLinksParams, *apperr.AppError) {
+	params := services.ListLinksParams{
+		Domain: c.Query("domain"),
+		Status: c.Query("status"),
+		Sort:   c.Query("sort"),
+		Order:  c.Query("order"),
+		Cursor: c.Query("cursor"),
+	}
+	for _, value := range c.QueryArray("tag") {
+		params.Tags = append(params.Tags, strings.Split(value, ",")...)
+	}
+	if limit := c.Query("limit"); limit != "" {
+		value, err := strconv.Atoi(limit)
+		if err != nil {
+			return params, apperr.ErrInvalidRequest("Le paramètre 'limit' doit être un entier", err)
+		}
+		params.Limit = value
+	}
+	for name, target := range map[string]**time.Time{
+		"created_after":  &params.CreatedAfter,
+		"created_before": &params.CreatedBefore,
+	} {
+		if value := c.Query(name); value != "" {
+			date, err := services.ParseDateFilter(value)
+			if err != nil {
+				return params, apperr.ErrInvalidRequest(err.Error(), err)
+			}
+			*target = date
+		}
+	}
+	return params, nil
+}
+
+// ListLinksHandler gère le listage paginé des liens.
+// Paramètres de requête : limit, cursor, sort (created_at|clicks), order (asc|desc),
+// domain, created_after, created_before (RFC3339 ou YYYY-MM-DD), status et tag.
+func ListLinksHandler(linkService *services.LinkService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		params, appErr := listParamsFromQuery(c)
+		if appErr != nil {
+			apperr.HandleError(c, appErr)
+			return
+		}
+
+		page, err := linkService.ListLinks(params)
+		if err != nil {
+			if errors.Is(err, services.ErrInvalidListParams) {
+				apperr.HandleError(c, apperr.ErrInvalidRequest(err.Error(), err))
+				return
+			}
+			apperr.HandleError(c, apperr.ErrDatabaseOperation("listage des liens", err))
+			return
+		}
+
+		now := time.Now()
+		links := make([]gin.H, 0, len(page.Links))
+		for _, link := range page.Links {
+			item := gin.H{
+				"short_code":   link.Shortcode,
+				"long_url":     link.LongURL,
+				"domain":       link.Domain,
+				"created_at":   link.CreatedAt,
+				"status":       link.Status(now),
+				"total_clicks": link.TotalClicks,
+				"title":        link.Title,
+				"tags":         services.TagNames(link.Tags),
+				"page_title":   link.Destination.PageTitle(),
+			}
+			if link.Destination.FetchedAt != nil {
+				item["destination_status"] = link.Destination.StatusCode
+			}
+			// La destination d'un lien protégé n'est jamais listée, ni les métadonnées qui pourraient la décrire.
+			maskProtected(item, &link.Link)
+			links = append(links, item)
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"links":       links,
+			"next_cursor": page.NextCursor,
+		})
+		c.Writer.Write([]byte("\n"))
+	}
+}
+
+// LookupLinksHandler retrouve les liens existants pour une URL longue (paramètre 'url'), comparée sous forme canonique.
+// Les liens protégés par mot de passe ne sont pas retournés : cela révélerait leur destination.
+func LookupLinksHandler(linkService *services.LinkService, baseURL string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rawURL := c.Query("url")
+		if rawURL == "" {
+			apperr.HandleError(c, apperr.ErrInvalidRequest("Le paramètre 'url' est requis", nil))
+			return
+		}
+
+		canonicalURL, found, err := linkService.LookupLinks(rawURL)
+		if err != nil {
+			if errors.Is(err, services.ErrInvalidLongURL) {
+				apperr.HandleError(c, apperr.ErrInvalidURL(rawURL))
+				return
+			}
+			apperr.HandleError(c, apperr.ErrDatabaseOperation("recherche des liens", err))
+			return
+		}
+
+		now := time.Now()
+		links := make([]gin.H, 0, len(found))
+		for _, link := range found {
+			if link.IsProtected() {
+				continue
+			}
+			links = append(links, gin.H{
+				"short_code":     link.Shortcode,
+				"long_url":       link.LongURL,
+				"full_short_url": services.ShortURL(baseURL, link.Shortcode),
+				"created_at":     link.CreatedAt,
+				"status":         link.Status(now),
+			})
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"url":           rawURL,
+			"canonical_url": canonicalURL,
+			"links":         links,
+		})
+		c.Writer.Write([]byte("\n"))
+	}
+}
+
+// GetStatsSummaryHandler agrège les statistiques des liens correspondant aux filtres de listage
+// (domain, created_after, created_before, status et tag), par exemple tous les liens d'une campagne taguée.
+// Avec group_by=utm_source (ou utm_medium, utm_campaign, utm_term, utm_content), les liens sont regroupés
+// par valeur de ce paramètre UTM dans leur URL longue.
+func GetStatsSummaryHandler(linkService *services.LinkService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		params, appErr := listParamsFromQuery(c)
+		if appErr != nil {
+			apperr.HandleError(c, appErr)
+			return
+		}
+
+		if groupBy := c.Query("group_by"); groupBy != "" {
+			groups, err := linkService.GetStatsByUTM(params, groupBy)
+			if err != nil {
+				if errors.Is(err, services.ErrInvalidListParams) {
+					apperr.HandleError(c, apperr.ErrInvalidRequest(err.Error(), err))
+					return
+				}
+				apperr.HandleError(c, apperr.ErrDatabaseOperation("agrégation des statistiques", err))
+				return
+			}
+
+			items := make([]gin.H, 0, len(groups))
+			for _, group := range groups {
+				items = append(items, gin.H{
+					"value":        group.Value,
+					"total_links":  group.TotalLinks,
+					"total_clicks": group.TotalClicks,
+				})
+			}
+			c.JSON(http.StatusOK, gin.H{
+				"group_by": groupBy,
+				"groups":   items,
+			})
+			c.Writer.Write([]byte("\n"))
+			return
+		}
+
+		summary, err := linkService.GetStatsSummary(params)
+		if err != nil {
+			if errors.Is(err, services.ErrInvalidListParams) {
+				apperr.HandleError(c, apperr.ErrInvalidRequest(err.Error(), err))
+				return
+			}
+			apperr.HandleError(c, apperr.ErrDatabaseOperation("agrégation des statistiques", err))
+			return
+		}
+
+		// Les tags ont déjà été validés par GetStatsSummary : seule la forme normalisée est reprise ici.
+		tags, _ := services.NormalizeTags(params.Tags)
+		topLinks := make([]gin.H, 0, len(summary.TopLinks))
+		for _, link := range summary.TopLinks {
+			item := gin.H{
+				"short_code":   link.Shortcode,
+				"title":        link.Title,
+				"total_clicks": link.TotalClicks,
+			}
+			maskProtected(item, &link.Link)
+			topLinks = append(topLinks, item)
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"tags":         tags,
+			"total_links":  summary.TotalLinks,
+			"total_clicks": summary.TotalClicks,
+			"top_links":    topLinks,
+		})
+		c.Writer.Write([]byte("\n"))
+	}
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/axellelanca/urlshortener/internal/apperr"
+	"github.com/axellelanca/urlshortener/internal/models"
+	"github.com/axellelanca/urlshortener/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// Nom du champ du bouton "Continuer" de la page d'aperçu, qui le distingue du formulaire de mot de passe.
+const continueFormField = "continue"
+
+// renderPreview affiche la page d'aperçu d'un lien : destination, titre, date de création et nombre de clics,
+// avec un bouton "Continuer". L'aperçu n'enregistre pas de clic et ne consomme pas la limite du lien.
+func renderPreview(c *gin.Context, linkService *services.LinkService, link *models.Link, destination services.Destination) {
+	_, totalClicks, err := linkService.GetLinkStats(link.Shortcode)
+	if err != nil {
+		apperr.HandleError(c, apperr.ErrDatabaseOperation("récupération des statistiques", err))
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	if wantsJSON(c) {
+		c.JSON(http.StatusOK, gin.H{
+			"short_code":   link.Shortcode,
+			"destination":  destination.URL,
+			"title":        link.Title,
+			"created_at":   link.CreatedAt,
+			"total_clicks": totalClicks,
+			"preview":      true,
+		})
+		c.Writer.Write([]byte("\n"))
+		return
+	}
+	c.HTML(http.StatusOK, "preview.html", gin.H{
+		"ShortCode":   link.Shortcode,
+		"Destination": destination.URL,
+		"Title":       link.Title,
+		"CreatedAt":   link.CreatedAt.Format("02/01/2006 à 15:04"),
+		"TotalClicks": totalClicks,
+		"Action":      shortLinkPath(link.Shortcode, redirectRequest(c)),
+		"Field":       continueFormField,
+	})
+}
+
+// ContinueLinkHandler gère le bouton "Continuer" de la page d'aperçu : le clic est enregistré comme pour
+// une redirection habituelle, puis le navigateur est redirigé en 303 (GET) vers la destination.
+func ContinueLinkHandler(linkService *services.LinkService, clickService *services.ClickService, accessService *services.AccessService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		shortCode, _ := strings.CutSuffix(c.Param("shortCode"), "+")
+
+		link, destination, ok := resolveRedirect(c, linkService, accessService, shortCode, c.Param("shortCode"))
+		if !ok {
+			return
+		}
+		redirectToDestination(c, linkService, clickService, link, destination, http.StatusSeeOther)
+	}
+}
+
+// LinkFormHandler gère la soumission des formulaires HTML d'un lien court :
+// le bouton "Continuer" de la page d'aperçu, ou le formulaire de mot de passe d'un lien protégé.
+func LinkFormHandler(linkService *services.LinkService, clickService *services.ClickService, accessService *services.AccessService) gin.HandlerFunc {
+	continueHandler := ContinueLinkHandler(linkService, clickService, accessService)
+	unlockHandler := unlockLinkHandler(linkService, accessService)
+	return func(c *gin.Context) {
+		if c.PostForm(continueFormField) != "" {
+			continueHandler(c)
+			return
+		}
+		unlockHandler(c)
+	}
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/axellelanca/urlshortener/internal/apperr"
+	"github.com/axellelanca/urlshortener/internal/services"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// qrOptions extrait de la query string les options de l'image d'un QR code
+// (format, size, margin, level, fg et bg) ; les paramètres absents prennent la valeur par défaut.
+func qrOptions(c *gin.Context) (services.QROptions, *apperr.AppError) {
+	opts := services.QROptions{
+		Format:     c.DefaultQuery("format", services.QRFormatPNG),
+		Level:      c.Query("level"),
+		Foreground: c.Query("fg"),
+		Background: c.Query("bg"),
+	}
+	if size := c.Query("size"); size != "" {
+		parsed, err := strconv.Atoi(size)
+		if err != nil {
+			return opts, apperr.ErrInvalidRequest("Le paramètre 'size' doit être un nombre de pixels", err)
+		}
+		opts.Size = parsed
+	}
+	if margin := c.Query("margin"); margin != "" {
+		parsed, err := strconv.Atoi(margin)
+		if err != nil {
+			return opts, apperr.ErrInvalidRequest("Le paramètre 'margin' doit être un nombre de modules", err)
+		}
+		opts.Margin = &parsed
+	}
+	return opts, nil
+}
+
+// QRCodeHandler gère la génération du QR code d'un lien (PNG ou SVG).
+// Le QR code encode l'URL courte construite à partir de server.base_url, marquée src=qr :
+// les scans sont ainsi distingués des clics dans les statistiques. Il ne révèle pas la destination,
+// et n'est donc pas soumis au mot de passe d'un lien protégé.
+func QRCodeHandler(linkService *services.LinkService, baseURL string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		shortCode := c.Param("shortCode")
+
+		opts, appErr := qrOptions(c)
+		if appErr != nil {
+			apperr.HandleError(c, appErr)
+			return
+		}
+
+		link, err := linkService.GetLinkByShortCode(shortCode)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				apperr.HandleError(c, apperr.ErrLinkNotFound(shortCode))
+				return
+			}
+			apperr.HandleError(c, apperr.ErrDatabaseOperation("récupération du lien", err))
+			return
+		}
+
+		image, err := services.GenerateQRCode(services.QRCodeURL(baseURL, link.Shortcode), opts)
+		if err != nil {
+			if errors.Is(err, services.ErrInvalidQROptions) {
+				apperr.HandleError(c, apperr.ErrInvalidRequest(err.Error(), err))
+				return
+			}
+			apperr.HandleError(c, apperr.ErrInternalServer("Impossible de générer le QR code", err))
+			return
+		}
+		c.Data(http.StatusOK, services.QRContentType(opts.Format), image)
+	}
+}
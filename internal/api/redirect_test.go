@@ -0,0 +1,184 @@
+package api
+
+import (
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/axellelanca/urlshortener/internal/config"
+	"github.com/axellelanca/urlshortener/internal/repository"
+	"github.com/axellelanca/urlshortener/internal/services"
+	"github.com/axellelanca/urlshortener/internal/testutil"
+	"github.com/gin-gonic/gin"
+)
+
+// newTestRouter configure les routes de l'API sur une base SQLite fraîchement migrée (voir testutil.NewDB).
+// Les clics sont enregistrés de façon synchrone (pas de channel ni de workers).
+func newTestRouter(t *testing.T) (*gin.Engine, *services.LinkService) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	db := testutil.NewDB(t)
+
+	clickRepo := repository.NewClickRepository(db)
+	linkService := services.NewLinkService(repository.NewLinkRepository(db))
+	accessService, err := services.NewAccessService("test-secret", 15*time.Minute)
+	if err != nil {
+		t.Fatalf("NewAccessService: %v", err)
+	}
+	cfg := &config.Config{}
+	cfg.Server.BaseURL = "http://sho.rt"
+
+	router := gin.New()
+	SetupRoutes(router, cfg, linkService, services.NewClickService(clickRepo), accessService,
+		services.NewCampaignService(repository.NewCampaignRepository(db), clickRepo))
+	return router, linkService
+}
+
+// get exécute une requête GET sur le routeur, avec les en-têtes donnés.
+func get(router *gin.Engine, path string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestExhaustedLinkHidesPreview(t *testing.T) {
+	const destination = "https://example.com/secret-destination"
+	router, linkService := newTestRouter(t)
+	if _, _, err := linkService.CreateLink(destination, services.CreateLinkOptions{Alias: "once", OneTime: true}); err != nil {
+		t.Fatalf("CreateLink: %v", err)
+	}
+
+	if rec := get(router, "/once", nil); rec.Code != http.StatusFound {
+		t.Fatalf("premier clic: code %d, attendu %d", rec.Code, http.StatusFound)
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		headers map[string]string
+	}{
+		{"redirection", "/once", nil},
+		{"aperçu", "/once+", nil},
+		{"aperçu JSON", "/once+", map[string]string{"Accept": "application/json"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := get(router, tt.path, tt.headers)
+			if rec.Code != http.StatusGone {
+				t.Errorf("GET %s: code %d, attendu %d", tt.path, rec.Code, http.StatusGone)
+			}
+			if strings.Contains(rec.Body.String(), destination) {
+				t.Errorf("GET %s: la destination d'un lien épuisé est révélée: %s", tt.path, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestLockedLinkShowsPasswordFormBeforeResolving(t *testing.T) {
+	router, linkService := newTestRouter(t)
+	if _, _, err := linkService.CreateLink("https://example.com/private", services.CreateLinkOptions{Alias: "lock", Password: "s3cret-pass"}); err != nil {
+		t.Fatalf("CreateLink: %v", err)
+	}
+
+	// Le lien ne transmet pas le chemin : sans mot de passe, /lock/extra doit répondre comme /lock,
+	// sans révéler cette option par un 404.
+	for _, path := range []string{"/lock", "/lock/extra"} {
+		rec := get(router, path, nil)
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("GET %s: code %d, attendu %d", path, rec.Code, http.StatusUnauthorized)
+		}
+		if strings.Contains(rec.Body.String(), "example.com/private") {
+			t.Errorf("GET %s: la destination d'un lien protégé est révélée", path)
+		}
+	}
+}
+
+func TestUnlockFromPreviewKeepsAccess(t *testing.T) {
+	router, linkService := newTestRouter(t)
+	if _, _, err := linkService.CreateLink("https://example.com/private", services.CreateLinkOptions{Alias: "lock", Password: "s3cret-pass"}); err != nil {
+		t.Fatalf("CreateLink: %v", err)
+	}
+	// Le cookie jar applique la correspondance des chemins des cookies, comme un navigateur.
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		t.Fatalf("cookiejar: %v", err)
+	}
+
+	form := url.Values{"password": {"s3cret-pass"}}
+	req := httptest.NewRequest(http.MethodPost, "/lock+", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusSeeOther {
+		t.Fatalf("POST /lock+: code %d, attendu %d", rec.Code, http.StatusSeeOther)
+	}
+	location := rec.Header().Get("Location")
+	jar.SetCookies(&url.URL{Scheme: "http", Host: "sho.rt", Path: "/lock+"}, rec.Result().Cookies())
+
+	for _, path := range []string{location, "/lock"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		for _, cookie := range jar.Cookies(&url.URL{Scheme: "http", Host: "sho.rt", Path: path}) {
+			req.AddCookie(cookie)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		if rec.Code == http.StatusUnauthorized {
+			t.Errorf("GET %s après déverrouillage: formulaire de mot de passe réaffiché", path)
+		}
+	}
+}
+
+func TestPasswordAttemptsAreThrottled(t *testing.T) {
+	router, linkService := newTestRouter(t)
+	if _, _, err := linkService.CreateLink("https://example.com/private", services.CreateLinkOptions{Alias: "lock", Password: "s3cret-pass"}); err != nil {
+		t.Fatalf("CreateLink: %v", err)
+	}
+
+	// unlock soumet le formulaire de mot de passe depuis l'adresse donnée (get utilise celle de httptest, 192.0.2.1).
+	unlock := func(password, remoteAddr string) *httptest.ResponseRecorder {
+		form := url.Values{"password": {password}}
+		req := httptest.NewRequest(http.MethodPost, "/lock", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.RemoteAddr = remoteAddr
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	const attacker, visitor = "192.0.2.1:1234", "192.0.2.2:1234"
+	// Le cinquième échec épuise les essais : le formulaire l'indique déjà.
+	for i, want := range []int{401, 401, 401, 401, 429} {
+		if rec := unlock("mauvais", attacker); rec.Code != want {
+			t.Fatalf("essai %d: code %d, attendu %d", i+1, rec.Code, want)
+		}
+	}
+
+	tests := []struct {
+		name string
+		rec  *httptest.ResponseRecorder
+		want int
+	}{
+		{"formulaire, essais épuisés", unlock("s3cret-pass", attacker), http.StatusTooManyRequests},
+		{"header, essais épuisés", get(router, "/api/v1/links/lock/history", map[string]string{"X-Link-Password": "s3cret-pass"}), http.StatusTooManyRequests},
+		{"autre visiteur", unlock("s3cret-pass", visitor), http.StatusSeeOther},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.rec.Code != tt.want {
+				t.Errorf("code %d, attendu %d", tt.rec.Code, tt.want)
+			}
+			if tt.want == http.StatusTooManyRequests && tt.rec.Header().Get("Retry-After") == "" {
+				t.Error("header Retry-After absent")
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package api
+
+import "html/template"
+
+// pageTemplates regroupe les pages HTML servies par le serveur (hors API JSON).
+// Elles sont enregistrées sur le routeur Gin via SetHTMLTemplate dans SetupRoutes.
+var pageTemplates = template.Must(template.New("pages").Parse(`
+{{define "password.html"}}<!DOCTYPE html>
+<html lang="fr">
+<head>
+  <meta charset="utf-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+  <meta name="robots" content="noindex">
+  <title>Lien protégé</title>
+  <style>
+    body { font-family: sans-serif; max-width: 24rem; margin: 4rem auto; padding: 0 1rem; }
+    input, button { font-size: 1rem; padding: .5rem; width: 100%; box-sizing: border-box; margin-top: .5rem; }
+    .error { color: #b00020; }
+  </style>
+</head>
+<body>
+  <h1>Lien protégé</h1>
+  <p>Ce lien est protégé par un mot de passe.</p>
+  {{if .Error}}<p class="error">{{.Error}}</p>{{end}}
+  <form method="post" action="{{.Action}}">
+    <label for="password">Mot de passe</label>
+    <input type="password" id="password" name="password" autofocus required>
+    <button type="submit">Continuer</button>
+  </form>
+</body>
+</html>
+{{end}}
+
+{{define "preview.html"}}<!DOCTYPE html>
+<html lang="fr">
+<head>
+  <meta charset="utf-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+  <meta name="robots" content="noindex">
+  <title>Aperçu du lien {{.ShortCode}}</title>
+  <style>
+    body { font-family: sans-serif; max-width: 32rem; margin: 4rem auto; padding: 0 1rem; }
+    dt { font-weight: bold; margin-top: .75rem; }
+    dd { margin: .25rem 0 0; overflow-wrap: anywhere; }
+    button { font-size: 1rem; padding: .5rem; width: 100%; box-sizing: border-box; margin-top: 1.5rem; }
+  </style>
+</head>
+<body>
+  <h1>{{if .Title}}{{.Title}}{{else}}Aperçu du lien{{end}}</h1>
+  <p>Ce lien court vous redirige vers l'adresse suivante.</p>
+  <dl>
+    <dt>Destination</dt>
+    <dd>{{.Destination}}</dd>
+    <dt>Créé le</dt>
+    <dd>{{.CreatedAt}}</dd>
+    <dt>Clics</dt>
+    <dd>{{.TotalClicks}}</dd>
+  </dl>
+  <form method="post" action="{{.Action}}">
+    <button type="submit" name="{{.Field}}" value="1">Continuer</button>
+  </form>
+</body>
+</html>
+{{end}}
+`))
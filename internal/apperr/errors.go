@@ -3,6 +3,7 @@ package apperr
 import (
 	"fmt"
 	"net/http"
+	"time"
 )
 
 // AppError représente une erreur applicative avec un code HTTP et un message clair
@@ -96,6 +97,17 @@ func ErrInvalidShortCode(shortCode string) *AppError {
 	}
 }
 
+// Erreurs 401 - Unauthorized
+
+// ErrLinkPasswordRequired retourne une erreur quand un lien protégé est manipulé sans mot de passe valide
+func ErrLinkPasswordRequired(shortCode string) *AppError {
+	return &AppError{
+		Code:    http.StatusUnauthorized,
+		Message: "Ce lien est protégé par un mot de passe",
+		Details: fmt.Sprintf("Fournissez le mot de passe du lien '%s' via le header X-Link-Password", shortCode),
+	}
+}
+
 // Erreurs 404 - Not Found
 
 // ErrLinkNotFound retourne une erreur quand un lien n'est pas trouvé
@@ -107,6 +119,24 @@ func ErrLinkNotFound(shortCode string) *AppError {
 	}
 }
 
+// ErrLinkNotYetActive retourne une erreur quand un lien n'a pas encore atteint sa date d'activation
+func ErrLinkNotYetActive(shortCode string, activatesAt time.Time) *AppError {
+	return &AppError{
+		Code:    http.StatusNotFound,
+		Message: "Ce lien n'est pas encore disponible",
+		Details: fmt.Sprintf("Le lien '%s' sera disponible à partir du %s", shortCode, activatesAt.Format(time.RFC3339)),
+	}
+}
+
+// ErrCampaignNotFound retourne une erreur quand une campagne n'est pas trouvée
+func ErrCampaignNotFound(id string) *AppError {
+	return &AppError{
+		Code:    http.StatusNotFound,
+		Message: "Campagne introuvable",
+		Details: fmt.Sprintf("Aucune campagne trouvée pour l'ID: %s", id),
+	}
+}
+
 // ErrResourceNotFound retourne une erreur pour une ressource introuvable
 func ErrResourceNotFound(resource string) *AppError {
 	return &AppError{
@@ -136,6 +166,64 @@ func ErrShortCodeAlreadyExists(shortCode string) *AppError {
 	}
 }
 
+// ErrCampaignAlreadyExists retourne une erreur quand le nom d'une campagne est déjà utilisé
+func ErrCampaignAlreadyExists(name string) *AppError {
+	return &AppError{
+		Code:    http.StatusConflict,
+		Message: "Ce nom de campagne est déjà utilisé",
+		Details: fmt.Sprintf("Campagne: %s", name),
+	}
+}
+
+// Erreurs 410 - Gone
+
+// ErrLinkExpired retourne une erreur quand un lien a dépassé sa date d'expiration
+func ErrLinkExpired(shortCode string) *AppError {
+	return &AppError{
+		Code:    http.StatusGone,
+		Message: "Ce lien a expiré",
+		Details: fmt.Sprintf("Le lien '%s' n'est plus disponible", shortCode),
+	}
+}
+
+// ErrLinkClickLimitReached retourne une erreur quand un lien a atteint son nombre maximum de redirections
+func ErrLinkClickLimitReached(shortCode string) *AppError {
+	return &AppError{
+		Code:    http.StatusGone,
+		Message: "Ce lien a atteint son nombre maximum de clics",
+		Details: fmt.Sprintf("Le lien '%s' n'est plus disponible", shortCode),
+	}
+}
+
+// ErrLinkDisabled retourne une erreur quand un lien a été désactivé
+func ErrLinkDisabled(shortCode string) *AppError {
+	return &AppError{
+		Code:    http.StatusGone,
+		Message: "Ce lien a été désactivé",
+		Details: fmt.Sprintf("Le lien '%s' n'est plus disponible", shortCode),
+	}
+}
+
+// ErrRestoreWindowExpired retourne une erreur quand un lien supprimé ne peut plus être restauré
+func ErrRestoreWindowExpired(shortCode string) *AppError {
+	return &AppError{
+		Code:    http.StatusGone,
+		Message: "Le délai de restauration de ce lien est dépassé",
+		Details: fmt.Sprintf("Le lien '%s' est à la corbeille depuis trop longtemps pour être restauré", shortCode),
+	}
+}
+
+// Erreurs 429 - Too Many Requests
+
+// ErrTooManyPasswordAttempts retourne une erreur quand un client a épuisé ses essais de mot de passe sur un lien protégé
+func ErrTooManyPasswordAttempts(shortCode string, retryAfter time.Duration) *AppError {
+	return &AppError{
+		Code:    http.StatusTooManyRequests,
+		Message: "Trop de tentatives de mot de passe",
+		Details: fmt.Sprintf("Réessayez le mot de passe du lien '%s' dans %s", shortCode, retryAfter.Round(time.Second)),
+	}
+}
+
 // Erreurs 500 - Internal Server Error
 
 // ErrDatabaseOperation retourne une erreur pour un problème de base de données
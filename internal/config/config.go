@@ -25,6 +25,49 @@ type Config struct {
 	Monitor struct {
 		IntervalMinutes int `mapstructure:"interval_minutes"`
 	} `mapstructure:"monitor"`
+	Security struct {
+		Secret           string `mapstructure:"secret"`
+		UnlockTTLMinutes int    `mapstructure:"unlock_ttl_minutes"`
+	} `mapstructure:"security"`
+	Trash struct {
+		RetentionDays int `mapstructure:"retention_days"`
+	} `mapstructure:"trash"`
+	ShortCode struct {
+		Strategy string `mapstructure:"strategy"`
+		Length   int    `mapstructure:"length"`
+		Alphabet string `mapstructure:"alphabet"`
+	} `mapstructure:"shortcode"`
+	Links struct {
+		DuplicatePolicy     string   `mapstructure:"duplicate_policy"`
+		StripTrackingParams bool     `mapstructure:"strip_tracking_params"`
+		TrackingParams      []string `mapstructure:"tracking_params"`
+	} `mapstructure:"links"`
+	Redirect struct {
+		Status      int `mapstructure:"status"`
+		CacheMaxAge int `mapstructure:"cache_max_age"`
+	} `mapstructure:"redirect"`
+	UTM struct {
+		Presets map[string]UTMPreset `mapstructure:"presets"`
+	} `mapstructure:"utm"`
+	Schedule struct {
+		PlaceholderURL string `mapstructure:"placeholder_url"`
+	} `mapstructure:"schedule"`
+	GeoIP struct {
+		Database string `mapstructure:"database"`
+	} `mapstructure:"geoip"`
+	Destination struct {
+		FetchOnCreate  bool `mapstructure:"fetch_on_create"`
+		TimeoutSeconds int  `mapstructure:"timeout_seconds"`
+	} `mapstructure:"destination"`
+}
+
+// UTMPreset est un jeu nommé de paramètres UTM (section utm.presets), applicable à la création d'un lien.
+type UTMPreset struct {
+	Source   string `mapstructure:"source"`
+	Medium   string `mapstructure:"medium"`
+	Campaign string `mapstructure:"campaign"`
+	Term     string `mapstructure:"term"`
+	Content  string `mapstructure:"content"`
 }
 
 // LoadConfig charge la configuration de l'application en utilisant Viper.
@@ -52,6 +95,21 @@ func LoadConfig() (*Config, error) {
 	viper.SetDefault("analytics.buffer_size", 1000)
 	viper.SetDefault("analytics.worker_count", 5)
 	viper.SetDefault("monitor.interval_minutes", 5)
+	viper.SetDefault("security.secret", "")
+	viper.SetDefault("security.unlock_ttl_minutes", 15)
+	viper.SetDefault("trash.retention_days", 30)
+	viper.SetDefault("shortcode.strategy", "random")
+	viper.SetDefault("shortcode.length", 6)
+	viper.SetDefault("shortcode.alphabet", "")
+	viper.SetDefault("links.duplicate_policy", "reject")
+	viper.SetDefault("links.strip_tracking_params", false)
+	viper.SetDefault("links.tracking_params", []string{})
+	viper.SetDefault("redirect.status", 302)
+	viper.SetDefault("redirect.cache_max_age", 0)
+	viper.SetDefault("schedule.placeholder_url", "")
+	viper.SetDefault("geoip.database", "")
+	viper.SetDefault("destination.fetch_on_create", false)
+	viper.SetDefault("destination.timeout_seconds", 10)
 
 	// Lire le fichier de configuration.
 	if err := viper.ReadInConfig(); err != nil {
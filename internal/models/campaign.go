@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// Campaign regroupe des liens (par exemple ceux d'une opération marketing) pour en agréger les statistiques.
+// Un lien appartient au plus à une campagne (Link.CampaignID).
+// Name : nom unique de la campagne
+// StartsAt, EndsAt : période optionnelle de la campagne, utilisée par défaut pour ses statistiques
+type Campaign struct {
+	ID          uint   `gorm:"primaryKey"`
+	Name        string `gorm:"size:100;uniqueIndex;not null"`
+	Description string
+	StartsAt    *time.Time
+	EndsAt      *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+
+	Links []Link `gorm:"foreignKey:CampaignID"`
+}
+
+// IsRunning indique si la campagne est en cours à l'instant donné (bornes non définies = ouvertes).
+func (c *Campaign) IsRunning(now time.Time) bool {
+	if c.StartsAt != nil && now.Before(*c.StartsAt) {
+		return false
+	}
+	return c.EndsAt == nil || now.Before(*c.EndsAt)
+}
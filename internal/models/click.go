@@ -11,15 +11,26 @@ type Click struct {
 	Timestamp time.Time // Horodatage précis du clic
 	UserAgent string    `gorm:"size:255"` // User-Agent de l'utilisateur qui a cliqué (informations sur le navigateur/OS)
 	IPAddress string    `gorm:"size:50"`  // Adresse IP de l'utilisateur
+	Device    string    `gorm:"size:20"`  // Type d'appareil détecté (ios, android, desktop), qui choisit la destination ciblée
+	Country   string    `gorm:"size:2"`   // Pays du visiteur (code ISO 3166-1 alpha-2), vide si la géolocalisation est désactivée ou impossible
+	Variant   string    `gorm:"size:50"`  // Variante A/B vers laquelle le visiteur a été redirigé, vide hors test A/B
+	Language  string    `gorm:"size:50"`  // Langue (Accept-Language) dont la destination a été choisie, vide si aucune ne correspondait
+	Source    string    `gorm:"size:20"`  // Source du clic : "qr" pour le scan d'un QR code (src=qr), vide pour un clic direct
 }
 
 // Done créer la struct pour ClickEvent
 // ClickEvent représente un événement de clic brut, destiné à être passé via un channel
 // Ce n'est pas un modèle GORM direct.
-// Un Click event a un LinkID(uint), un Timestamp (Time.Time), un UserAgent (string) et un IP (string).
+// Un Click event a un LinkID(uint), un Timestamp (Time.Time), un UserAgent (string) et un IP (string),
+// ainsi que le résultat du ciblage de la redirection (type d'appareil, pays, variante A/B, langue) et sa source (scan d'un QR code).
 type ClickEvent struct {
 	LinkID    uint
 	Timestamp time.Time
 	UserAgent string
 	IPAddress string
+	Device    string
+	Country   string
+	Variant   string
+	Language  string
+	Source    string
 }
@@ -1,6 +1,10 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 // Done : Créer la struct Link
 // Link représente un lien raccourci dans la base de données.
@@ -8,11 +12,140 @@ import "time"
 // ID qui est une primaryKey
 // Shortcode : doit être unique, indexé pour des recherches rapide (voir doc), taille max 10 caractères
 // LongURL : doit pas être null
+// Domain : Hôte de l'URL longue (en minuscules), indexé pour le filtrage des listes
+// CanonicalURL : Forme canonique de l'URL longue, indexée pour la détection des doublons et la recherche par URL
+// Title, Description, Notes : Métadonnées libres décrivant l'usage du lien (notes internes)
+// Tags : Étiquettes du lien (many-to-many via la table 'link_tags')
+// Targets : Destinations alternatives choisies à la redirection (par type d'appareil, pays, langue, variantes A/B), table 'link_targets'
+// Schedule : Changements programmés de l'URL longue, table 'link_schedules'
+// CampaignID : Campagne à laquelle appartient le lien (nil = aucune)
 // CreateAt : Horodatage de la créatino du lien
+// ExpiresAt : Date d'expiration optionnelle (nil = le lien n'expire jamais)
+// ExpiredURL : URL de repli optionnelle vers laquelle rediriger une fois le lien expiré
+// ActivatesAt : Date d'activation optionnelle (nil = le lien est actif dès sa création)
+// PendingURL : URL d'attente optionnelle vers laquelle rediriger avant l'activation
+// MaxClicks : Nombre maximum de redirections autorisées (0 = illimité, 1 = lien à usage unique)
+// ClickCount : Nombre de redirections déjà consommées, incrémenté atomiquement pour les liens limités
+// ForwardQuery : Transmettre la query string de la requête de redirection à la destination
+// ForwardPath : Transmettre le chemin demandé après le code court (/code/suite/du/chemin) à la destination
+// Preview : Afficher systématiquement la page d'aperçu (destination, clics...) avant la redirection
+// StickyVariant : Conserver la variante A/B tirée pour un visiteur (cookie), au lieu d'un tirage à chaque clic
+// RedirectStatus : Code HTTP de redirection (301, 302, 307 ou 308), 0 = code par défaut de la configuration
+// PasswordHash : Hash bcrypt du mot de passe protégeant le lien (vide = lien public)
+// Destination : Métadonnées de la destination récupérées par une requête HTTP (colonnes 'destination_*')
+// Disabled : Lien désactivé manuellement (la redirection renvoie 410 mais les stats restent disponibles)
+// DeletedAt : Date de mise à la corbeille (soft-delete GORM, nil = lien actif)
 
 type Link struct {
-	ID        uint   `gorm:"primaryKey"`
-	Shortcode string `gorm:"size:10;uniqueIndex"`
-	LongURL   string `gorm:"not null"`
-	CreatedAt time.Time
+	ID           uint   `gorm:"primaryKey"`
+	Shortcode    string `gorm:"size:10;uniqueIndex"`
+	LongURL      string `gorm:"not null"`
+	Domain       string `gorm:"size:255;index"`
+	CanonicalURL string `gorm:"index"`
+	Title        string `gorm:"size:255"`
+	Description  string
+	Notes        string
+	Tags         []Tag          `gorm:"many2many:link_tags"`
+	Targets      []LinkTarget   `gorm:"foreignKey:LinkID"`
+	Schedule     []LinkSchedule `gorm:"foreignKey:LinkID"`
+	CampaignID   *uint          `gorm:"index"`
+	CreatedAt    time.Time
+	ExpiresAt    *time.Time `gorm:"index"`
+	ExpiredURL   string
+	ActivatesAt  *time.Time `gorm:"index"`
+	PendingURL   string
+	MaxClicks    int  `gorm:"not null;default:0"`
+	ClickCount   int  `gorm:"not null;default:0"`
+	ForwardQuery bool `gorm:"not null;default:false"`
+	ForwardPath  bool `gorm:"not null;default:false"`
+	Preview      bool `gorm:"not null;default:false"`
+
+	StickyVariant bool `gorm:"not null;default:false"`
+
+	RedirectStatus int `gorm:"not null;default:0"`
+
+	PasswordHash string `json:"-"`
+
+	Destination DestinationMetadata `gorm:"embedded;embeddedPrefix:destination_"`
+
+	Disabled  bool           `gorm:"not null;default:false"`
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+// DestinationMetadata décrit la destination d'un lien telle que récupérée par une requête HTTP sur son URL longue :
+// URL finale après redirections, code HTTP, type de contenu, titre de la page et balises Open Graph.
+// Error contient l'erreur réseau si la destination n'a pas pu être jointe ; FetchedAt est nil tant
+// qu'aucune récupération n'a été faite.
+type DestinationMetadata struct {
+	FinalURL      string
+	StatusCode    int    `gorm:"not null;default:0"`
+	ContentType   string `gorm:"size:255"`
+	Title         string `gorm:"size:255"`
+	OGTitle       string `gorm:"column:og_title;size:255"`
+	OGDescription string `gorm:"column:og_description"`
+	OGImage       string `gorm:"column:og_image"`
+	Error         string
+	FetchedAt     *time.Time
+}
+
+// PageTitle retourne le titre lisible de la destination : son titre Open Graph, sinon le <title> de la page.
+func (d DestinationMetadata) PageTitle() string {
+	if d.OGTitle != "" {
+		return d.OGTitle
+	}
+	return d.Title
+}
+
+// Statuts possibles d'un lien, tels qu'exposés par l'API et la CLI.
+const (
+	LinkStatusActive    = "active"
+	LinkStatusScheduled = "scheduled"
+	LinkStatusDisabled  = "disabled"
+	LinkStatusExpired   = "expired"
+	LinkStatusExhausted = "exhausted"
+	LinkStatusDeleted   = "deleted"
+)
+
+// IsExpired indique si le lien a dépassé sa date d'expiration à l'instant donné.
+func (l *Link) IsExpired(now time.Time) bool {
+	return l.ExpiresAt != nil && !now.Before(*l.ExpiresAt)
+}
+
+// IsPending indique si le lien n'est pas encore actif à l'instant donné (date d'activation future).
+func (l *Link) IsPending(now time.Time) bool {
+	return l.ActivatesAt != nil && now.Before(*l.ActivatesAt)
+}
+
+// HasClickLimit indique si le lien est limité en nombre de redirections.
+func (l *Link) HasClickLimit() bool {
+	return l.MaxClicks > 0
+}
+
+// IsExhausted indique si un lien limité a consommé toutes ses redirections.
+func (l *Link) IsExhausted() bool {
+	return l.HasClickLimit() && l.ClickCount >= l.MaxClicks
+}
+
+// IsProtected indique si le lien est protégé par un mot de passe.
+func (l *Link) IsProtected() bool {
+	return l.PasswordHash != ""
+}
+
+// Status retourne le statut du lien à l'instant donné.
+// Un lien à la corbeille est "deleted", puis un lien désactivé est "disabled", avant l'expiration et la limite de clics.
+func (l *Link) Status(now time.Time) string {
+	switch {
+	case l.DeletedAt.Valid:
+		return LinkStatusDeleted
+	case l.Disabled:
+		return LinkStatusDisabled
+	case l.IsExpired(now):
+		return LinkStatusExpired
+	case l.IsPending(now):
+		return LinkStatusScheduled
+	case l.IsExhausted():
+		return LinkStatusExhausted
+	default:
+		return LinkStatusActive
+	}
 }
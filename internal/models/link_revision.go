@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// LinkRevision représente une modification de l'URL longue d'un lien.
+// GORM utilisera ces tags pour créer la table 'link_revisions'.
+type LinkRevision struct {
+	ID        uint      `gorm:"primaryKey"`
+	LinkID    uint      `gorm:"index"`             // Clé étrangère vers la table 'links'
+	Link      Link      `gorm:"foreignKey:LinkID"` // Relation GORM vers le lien modifié
+	OldURL    string    `gorm:"not null"`          // URL longue avant la modification
+	NewURL    string    `gorm:"not null"`          // URL longue après la modification
+	Actor     string    `gorm:"size:100"`          // Auteur de la modification (utilisateur CLI, client API...)
+	CreatedAt time.Time // Horodatage de la modification
+}
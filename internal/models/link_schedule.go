@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// LinkSchedule représente un changement programmé de la destination d'un lien :
+// à partir de SwitchAt, l'URL longue du lien devient URL.
+// GORM utilisera ces tags pour créer la table 'link_schedules'.
+// Un changement échu est appliqué lors de la redirection suivante (révision d'auteur "schedule"), puis supprimé.
+type LinkSchedule struct {
+	ID        uint      `gorm:"primaryKey"`
+	LinkID    uint      `gorm:"not null;uniqueIndex:idx_link_schedule_at"` // Clé étrangère vers la table 'links'
+	SwitchAt  time.Time `gorm:"not null;uniqueIndex:idx_link_schedule_at"`
+	URL       string    `gorm:"not null"`
+	CreatedAt time.Time
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// Critères de choix des destinations alternatives d'un lien (LinkTarget.Kind).
+const (
+	TargetKindDevice   = "device"   // Type d'appareil détecté dans le User-Agent (Key: ios, android, desktop)
+	TargetKindCountry  = "country"  // Pays du visiteur déterminé par GeoIP (Key: code ISO 3166-1 alpha-2, ex: FR)
+	TargetKindVariant  = "variant"  // Variante d'un test A/B, tirée au sort selon son poids (Key: identifiant de la variante)
+	TargetKindLanguage = "language" // Langue négociée avec l'en-tête Accept-Language (Key: étiquette BCP 47 en minuscules, ex: fr, en-us)
+)
+
+// LinkTarget représente une destination alternative d'un lien, choisie à la redirection
+// quand la requête correspond à sa clé pour le critère Kind (par exemple Kind "device", Key "ios").
+// GORM utilisera ces tags pour créer la table 'link_targets'.
+// Un lien a au plus une destination par couple (Kind, Key) ; sans correspondance, l'URL longue du lien est utilisée.
+// Weight n'est utilisé que par les variantes : c'est leur part relative du trafic (0 = variante en pause).
+type LinkTarget struct {
+	ID        uint   `gorm:"primaryKey"`
+	LinkID    uint   `gorm:"not null;uniqueIndex:idx_link_target_key"` // Clé étrangère vers la table 'links'
+	Kind      string `gorm:"size:20;not null;uniqueIndex:idx_link_target_key"`
+	Key       string `gorm:"column:target_key;size:50;not null;uniqueIndex:idx_link_target_key"`
+	URL       string `gorm:"not null"`
+	Weight    int    `gorm:"not null;default:0"`
+	CreatedAt time.Time
+}
@@ -0,0 +1,15 @@
+package models
+
+// AllModels retourne les modèles persistés par l'application, dans l'ordre des migrations.
+// Tout nouveau modèle doit y être ajouté : la liste sert aux migrations et à l'export/import des données.
+func AllModels() []interface{} {
+	return []interface{}{
+		&Campaign{},
+		&Link{},
+		&Click{},
+		&LinkRevision{},
+		&Tag{},
+		&LinkTarget{},
+		&LinkSchedule{},
+	}
+}
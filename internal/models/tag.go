@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// Tag représente une étiquette libre (ex: "campaign-q3") associée à des liens.
+// La relation many-to-many avec Link passe par la table de jointure 'link_tags'.
+// Name : nom normalisé (minuscules), unique
+type Tag struct {
+	ID        uint   `gorm:"primaryKey"`
+	Name      string `gorm:"size:50;uniqueIndex;not null"`
+	CreatedAt time.Time
+}
@@ -58,7 +58,13 @@ func (m *UrlMonitor) checkUrls() {
 		return
 	}
 
+	now := time.Now()
 	for _, link := range links {
+		// Les liens expirés ou désactivés ne sont plus surveillés.
+		if link.IsExpired(now) || link.Disabled {
+			continue
+		}
+
 		// TODO : Pour chaque lien, vérifier son accessibilité (isUrlAccessible).
 		currentState := m.isUrlAccessible(link.LongURL)
 
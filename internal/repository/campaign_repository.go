@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"github.com/axellelanca/urlshortener/internal/models"
+	"gorm.io/gorm"
+)
+
+// CampaignRepository est une interface qui définit les méthodes d'accès aux données
+// pour les campagnes et l'appartenance des liens à une campagne.
+type CampaignRepository interface {
+	// CreateCampaign insère une nouvelle campagne. Il retourne ErrDuplicateKey si le nom est déjà utilisé.
+	CreateCampaign(campaign *models.Campaign) error
+	// GetCampaignByID récupère une campagne via son ID.
+	GetCampaignByID(id uint) (*models.Campaign, error)
+	// GetAllCampaigns récupère toutes les campagnes avec leur nombre de liens.
+	GetAllCampaigns() ([]CampaignWithLinkCount, error)
+	// UpdateCampaign enregistre le nom, la description et la période d'une campagne.
+	// Il retourne ErrDuplicateKey si le nouveau nom est déjà utilisé.
+	UpdateCampaign(campaign *models.Campaign) error
+	// DeleteCampaign supprime une campagne ; ses liens sont conservés, sans campagne.
+	DeleteCampaign(id uint) error
+	// GetLinksByCampaignID récupère les liens (hors corbeille) d'une campagne.
+	GetLinksByCampaignID(id uint) ([]models.Link, error)
+	// GetLinksByShortCodes récupère les liens (hors corbeille) ayant ces codes courts.
+	GetLinksByShortCodes(shortCodes []string) ([]models.Link, error)
+	// SetLinksCampaign rattache des liens à une campagne (nil = les détacher de toute campagne).
+	SetLinksCampaign(linkIDs []uint, campaignID *uint) error
+}
+
+// CampaignWithLinkCount est une campagne accompagnée de son nombre de liens, tel que retourné par GetAllCampaigns.
+type CampaignWithLinkCount struct {
+	models.Campaign
+	LinkCount int
+}
+
+// GormCampaignRepository est l'implémentation de CampaignRepository utilisant GORM.
+type GormCampaignRepository struct {
+	db *gorm.DB
+}
+
+// NewCampaignRepository crée et retourne une nouvelle instance de GormCampaignRepository.
+func NewCampaignRepository(db *gorm.DB) *GormCampaignRepository {
+	return &GormCampaignRepository{db: db}
+}
+
+// CreateCampaign insère une nouvelle campagne. L'index unique sur Name départage les créations concurrentes.
+func (r *GormCampaignRepository) CreateCampaign(campaign *models.Campaign) error {
+	result := r.db.Create(campaign)
+	if result.Error != nil {
+		return translateError(result.Error)
+	}
+	return nil
+}
+
+// GetCampaignByID récupère une campagne via son ID.
+// Il renvoie gorm.ErrRecordNotFound si aucune campagne n'a cet ID.
+func (r *GormCampaignRepository) GetCampaignByID(id uint) (*models.Campaign, error) {
+	var campaign models.Campaign
+	result := r.db.First(&campaign, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &campaign, nil
+}
+
+// GetAllCampaigns récupère toutes les campagnes, de la plus récente à la plus ancienne,
+// avec leur nombre de liens (hors corbeille).
+func (r *GormCampaignRepository) GetAllCampaigns() ([]CampaignWithLinkCount, error) {
+	var campaigns []CampaignWithLinkCount
+	result := r.db.Model(&models.Campaign{}).
+		Select("campaigns.*, (SELECT COUNT(*) FROM links WHERE links.campaign_id = campaigns.id AND links.deleted_at IS NULL) AS link_count").
+		Order("campaigns.created_at DESC, campaigns.id DESC").
+		Find(&campaigns)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return campaigns, nil
+}
+
+// UpdateCampaign enregistre le nom, la description et la période d'une campagne.
+// Les dates sont toujours écrites, y compris à NULL pour retirer une borne.
+func (r *GormCampaignRepository) UpdateCampaign(campaign *models.Campaign) error {
+	updates := map[string]interface{}{
+		"name":        campaign.Name,
+		"description": campaign.Description,
+		"starts_at":   campaign.StartsAt,
+		"ends_at":     campaign.EndsAt,
+	}
+	result := r.db.Model(campaign).Updates(updates)
+	if result.Error != nil {
+		return translateError(result.Error)
+	}
+	return nil
+}
+
+// DeleteCampaign supprime définitivement une campagne après en avoir détaché tous les liens,
+// corbeille comprise, dans une transaction.
+func (r *GormCampaignRepository) DeleteCampaign(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Unscoped().Model(&models.Link{}).Where("campaign_id = ?", id).Update("campaign_id", nil).Error
+		if err != nil {
+			return err
+		}
+		return tx.Delete(&models.Campaign{}, id).Error
+	})
+}
+
+// GetLinksByCampaignID récupère les liens (hors corbeille) d'une campagne, du plus ancien au plus récent.
+func (r *GormCampaignRepository) GetLinksByCampaignID(id uint) ([]models.Link, error) {
+	var links []models.Link
+	result := r.db.Where("campaign_id = ?", id).Order("created_at ASC, id ASC").Find(&links)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return links, nil
+}
+
+// GetLinksByShortCodes récupère les liens (hors corbeille) ayant ces codes courts.
+// Les codes inconnus sont ignorés : l'appelant compare le résultat à sa liste.
+func (r *GormCampaignRepository) GetLinksByShortCodes(shortCodes []string) ([]models.Link, error) {
+	var links []models.Link
+	if len(shortCodes) == 0 {
+		return links, nil
+	}
+	result := r.db.Where("shortcode IN ?", shortCodes).Find(&links)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return links, nil
+}
+
+// SetLinksCampaign rattache des liens à une campagne, ou les en détache si campaignID est nil.
+func (r *GormCampaignRepository) SetLinksCampaign(linkIDs []uint, campaignID *uint) error {
+	if len(linkIDs) == 0 {
+		return nil
+	}
+	result := r.db.Model(&models.Link{}).Where("id IN ?", linkIDs).Update("campaign_id", campaignID)
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/axellelanca/urlshortener/internal/models"
 	"gorm.io/gorm"
@@ -17,6 +18,15 @@ type ClickRepository interface {
 	CountClicksByLinkID(linkID uint) (int, error)
 	// Utilisé par LinkService lors de la création d'un clic
 	CreateClick(click *models.Click) error
+	// Utilisé par CampaignService pour les statistiques agrégées d'un ensemble de liens
+	CountClicksByLinkIDs(linkIDs []uint, since, until *time.Time) (map[uint]int, error)
+	CountClicksByDay(linkIDs []uint, since, until *time.Time) ([]DailyClickCount, error)
+}
+
+// DailyClickCount est le nombre de clics d'une journée, tel que retourné par CountClicksByDay.
+type DailyClickCount struct {
+	Day    string // Date au format YYYY-MM-DD
+	Clicks int
 }
 
 // GormClickRepository est l'implémentation de l'interface ClickRepository utilisant GORM.
@@ -54,3 +64,57 @@ func (r *GormClickRepository) CountClicksByLinkID(linkID uint) (int, error) {
 
 	return int(count), nil // Convert the int64 count to an int
 }
+
+// CountClicksByLinkIDs compte les clics de chacun des liens donnés, sur la période [since, until[ (bornes optionnelles).
+// Les liens sans clic sont absents de la map retournée.
+func (r *GormClickRepository) CountClicksByLinkIDs(linkIDs []uint, since, until *time.Time) (map[uint]int, error) {
+	counts := make(map[uint]int, len(linkIDs))
+	if len(linkIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		LinkID uint
+		Clicks int
+	}
+	query := r.db.Model(&models.Click{}).
+		Select("link_id, COUNT(*) AS clicks").
+		Where("link_id IN ?", linkIDs)
+	result := withClickPeriod(query, since, until).Group("link_id").Scan(&rows)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to count clicks by link: %w", result.Error)
+	}
+	for _, row := range rows {
+		counts[row.LinkID] = row.Clicks
+	}
+	return counts, nil
+}
+
+// CountClicksByDay compte les clics des liens donnés jour par jour, sur la période [since, until[ (bornes optionnelles).
+// Seuls les jours ayant au moins un clic sont retournés, du plus ancien au plus récent.
+func (r *GormClickRepository) CountClicksByDay(linkIDs []uint, since, until *time.Time) ([]DailyClickCount, error) {
+	var days []DailyClickCount
+	if len(linkIDs) == 0 {
+		return days, nil
+	}
+
+	query := r.db.Model(&models.Click{}).
+		Select("DATE(timestamp) AS day, COUNT(*) AS clicks").
+		Where("link_id IN ?", linkIDs)
+	result := withClickPeriod(query, since, until).Group("DATE(timestamp)").Order("day").Scan(&days)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to count clicks by day: %w", result.Error)
+	}
+	return days, nil
+}
+
+// withClickPeriod restreint une requête sur les clics à la période [since, until[ (bornes optionnelles).
+func withClickPeriod(db *gorm.DB, since, until *time.Time) *gorm.DB {
+	if since != nil {
+		db = db.Where("timestamp >= ?", *since)
+	}
+	if until != nil {
+		db = db.Where("timestamp < ?", *until)
+	}
+	return db
+}
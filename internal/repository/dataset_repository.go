@@ -0,0 +1,305 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// TableInfo décrit une table de la base : ses colonnes et ses clés étrangères.
+type TableInfo struct {
+	Name        string
+	PrimaryKeys []string
+	Columns     []ColumnInfo
+	ForeignKeys []ForeignKey
+}
+
+// ColumnInfo décrit une colonne et le type Go de ses valeurs.
+type ColumnInfo struct {
+	Name string
+	Type schema.DataType
+}
+
+// ForeignKey décrit une référence d'une colonne vers la clé d'une autre table.
+type ForeignKey struct {
+	Column    string
+	RefTable  string
+	RefColumn string
+}
+
+// Column retourne la colonne nommée name de la table.
+func (t TableInfo) Column(name string) (ColumnInfo, bool) {
+	for _, col := range t.Columns {
+		if col.Name == name {
+			return col, true
+		}
+	}
+	return ColumnInfo{}, false
+}
+
+// DatasetRepository donne un accès générique, table par table, à l'ensemble des données persistées.
+// Il est utilisé pour l'export et l'import complets de la base (sauvegarde, migration d'environnement).
+type DatasetRepository interface {
+	// Transaction exécute fn dans une transaction, avec un repository lié à cette transaction.
+	Transaction(fn func(txRepo DatasetRepository) error) error
+	// Tables retourne les tables des modèles (tables de jointure incluses), les tables référencées en premier.
+	Tables() ([]TableInfo, error)
+	// CountRows compte les lignes d'une table, lignes à la corbeille incluses.
+	CountRows(table string) (int64, error)
+	// EachRow parcourt toutes les lignes d'une table par ordre de clé primaire.
+	// Les valeurs passées à fn sont alignées sur table.Columns ; une valeur NULL vaut nil.
+	EachRow(table TableInfo, fn func(values []interface{}) error) error
+	// InsertRow insère une ligne telle quelle, clé primaire comprise.
+	InsertRow(table string, row map[string]interface{}) error
+	// CountOrphans compte les lignes de table dont la clé étrangère ne référence aucune ligne existante.
+	CountOrphans(table string, fk ForeignKey) (int64, error)
+}
+
+// GormDatasetRepository est l'implémentation de DatasetRepository utilisant GORM.
+type GormDatasetRepository struct {
+	db     *gorm.DB
+	models []interface{}
+}
+
+// NewDatasetRepository crée et retourne une nouvelle instance de GormDatasetRepository
+// couvrant les tables des modèles donnés (typiquement models.AllModels()).
+func NewDatasetRepository(db *gorm.DB, models ...interface{}) *GormDatasetRepository {
+	return &GormDatasetRepository{db: db, models: models}
+}
+
+// Transaction exécute fn dans une transaction GORM.
+func (r *GormDatasetRepository) Transaction(fn func(txRepo DatasetRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&GormDatasetRepository{db: tx, models: r.models})
+	})
+}
+
+// Tables décrit les tables à partir des schémas GORM des modèles. Les clés étrangères sont déduites
+// des relations (belongs-to, has-many, many-to-many) : aucune table n'a besoin d'être déclarée à la main.
+func (r *GormDatasetRepository) Tables() ([]TableInfo, error) {
+	var schemas []*schema.Schema
+	seen := make(map[string]bool)
+	addSchema := func(s *schema.Schema) {
+		if s != nil && !seen[s.Table] {
+			seen[s.Table] = true
+			schemas = append(schemas, s)
+		}
+	}
+	for _, model := range r.models {
+		stmt := &gorm.Statement{DB: r.db}
+		if err := stmt.Parse(model); err != nil {
+			return nil, fmt.Errorf("failed to parse model %T: %w", model, err)
+		}
+		addSchema(stmt.Schema)
+		for _, rel := range stmt.Schema.Relationships.Relations {
+			if rel.Type == schema.Many2Many {
+				addSchema(rel.JoinTable)
+			}
+		}
+	}
+
+	tables := make(map[string]*TableInfo, len(schemas))
+	var names []string
+	for _, s := range schemas {
+		table := &TableInfo{Name: s.Table}
+		for _, field := range s.Fields {
+			if field.DBName == "" {
+				continue
+			}
+			table.Columns = append(table.Columns, ColumnInfo{Name: field.DBName, Type: field.DataType})
+			if field.PrimaryKey {
+				table.PrimaryKeys = append(table.PrimaryKeys, field.DBName)
+			}
+		}
+		tables[s.Table] = table
+		names = append(names, s.Table)
+	}
+
+	// Clés étrangères : chaque contrainte est rattachée à la table qui porte la colonne.
+	seenFK := make(map[string]bool)
+	for _, s := range schemas {
+		for _, rel := range s.Relationships.Relations {
+			constraint := rel.ParseConstraint()
+			if constraint == nil {
+				continue
+			}
+			owner, ok := tables[constraint.Schema.Table]
+			if !ok {
+				continue
+			}
+			for i, fkField := range constraint.ForeignKeys {
+				fk := ForeignKey{
+					Column:    fkField.DBName,
+					RefTable:  constraint.ReferenceSchema.Table,
+					RefColumn: constraint.References[i].DBName,
+				}
+				key := owner.Name + "." + fk.Column + ">" + fk.RefTable
+				if !seenFK[key] {
+					seenFK[key] = true
+					owner.ForeignKeys = append(owner.ForeignKeys, fk)
+				}
+			}
+		}
+	}
+
+	return sortTablesByDependency(names, tables)
+}
+
+// sortTablesByDependency ordonne les tables de sorte qu'une table référencée précède celles qui la référencent.
+// À dépendances égales, l'ordre de déclaration des modèles est conservé.
+func sortTablesByDependency(names []string, tables map[string]*TableInfo) ([]TableInfo, error) {
+	ordered := make([]TableInfo, 0, len(names))
+	done := make(map[string]bool, len(names))
+	for len(ordered) < len(names) {
+		progressed := false
+		for _, name := range names {
+			if done[name] {
+				continue
+			}
+			ready := true
+			for _, fk := range tables[name].ForeignKeys {
+				if fk.RefTable != name && !done[fk.RefTable] && tables[fk.RefTable] != nil {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				done[name] = true
+				ordered = append(ordered, *tables[name])
+				progressed = true
+			}
+		}
+		if !progressed {
+			var remaining []string
+			for _, name := range names {
+				if !done[name] {
+					remaining = append(remaining, name)
+				}
+			}
+			sort.Strings(remaining)
+			return nil, fmt.Errorf("circular foreign keys between tables: %s", strings.Join(remaining, ", "))
+		}
+	}
+	return ordered, nil
+}
+
+// CountRows compte les lignes d'une table, lignes à la corbeille incluses.
+func (r *GormDatasetRepository) CountRows(table string) (int64, error) {
+	var count int64
+	if err := r.db.Table(table).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count rows of %s: %w", table, err)
+	}
+	return count, nil
+}
+
+// EachRow parcourt toutes les lignes d'une table par ordre de clé primaire, sans tout charger en mémoire.
+func (r *GormDatasetRepository) EachRow(table TableInfo, fn func(values []interface{}) error) error {
+	columns := make([]string, len(table.Columns))
+	for i, col := range table.Columns {
+		columns[i] = col.Name
+	}
+	query := r.db.Table(table.Name).Select(columns)
+	for _, pk := range table.PrimaryKeys {
+		query = query.Order(pk)
+	}
+	rows, err := query.Rows()
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", table.Name, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		dest := make([]interface{}, len(table.Columns))
+		for i, col := range table.Columns {
+			dest[i] = scanDestination(col.Type)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return fmt.Errorf("failed to scan row of %s: %w", table.Name, err)
+		}
+		values := make([]interface{}, len(dest))
+		for i, d := range dest {
+			values[i] = scannedValue(d)
+		}
+		if err := fn(values); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// scanDestination retourne la destination de Scan adaptée au type de la colonne.
+// Les types sql.Null* permettent de distinguer NULL de la valeur zéro.
+func scanDestination(dataType schema.DataType) interface{} {
+	switch dataType {
+	case schema.Bool:
+		return &sql.NullBool{}
+	case schema.Int, schema.Uint:
+		return &sql.NullInt64{}
+	case schema.Float:
+		return &sql.NullFloat64{}
+	case schema.Time:
+		return &sql.NullTime{}
+	case schema.Bytes:
+		return &[]byte{}
+	default:
+		return &sql.NullString{}
+	}
+}
+
+// scannedValue extrait la valeur Go d'une destination de Scan (nil pour NULL).
+func scannedValue(dest interface{}) interface{} {
+	switch d := dest.(type) {
+	case *sql.NullBool:
+		if d.Valid {
+			return d.Bool
+		}
+	case *sql.NullInt64:
+		if d.Valid {
+			return d.Int64
+		}
+	case *sql.NullFloat64:
+		if d.Valid {
+			return d.Float64
+		}
+	case *sql.NullTime:
+		if d.Valid {
+			return d.Time
+		}
+	case *[]byte:
+		if *d != nil {
+			return *d
+		}
+	case *sql.NullString:
+		if d.Valid {
+			return d.String
+		}
+	}
+	return nil
+}
+
+// InsertRow insère une ligne telle quelle, clé primaire comprise.
+func (r *GormDatasetRepository) InsertRow(table string, row map[string]interface{}) error {
+	if err := r.db.Table(table).Create(row).Error; err != nil {
+		return fmt.Errorf("failed to insert into %s: %w", table, err)
+	}
+	return nil
+}
+
+// CountOrphans compte les lignes de table dont la clé étrangère (non NULL) ne référence aucune ligne.
+func (r *GormDatasetRepository) CountOrphans(table string, fk ForeignKey) (int64, error) {
+	var count int64
+	err := r.db.Table(table+" AS child").
+		Where("child."+fk.Column+" IS NOT NULL").
+		Where("NOT EXISTS (?)", r.db.Table(fk.RefTable+" AS parent").
+			Select("1").
+			Where("parent."+fk.RefColumn+" = child."+fk.Column)).
+		Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to check %s.%s references: %w", table, fk.Column, err)
+	}
+	return count, nil
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// ErrDuplicateKey est retourné quand une écriture viole une contrainte d'unicité (ex: code court déjà pris).
+// Les services s'appuient dessus pour réserver une valeur par insertion plutôt que par vérification préalable.
+var ErrDuplicateKey = errors.New("duplicate key")
+
+// uniqueViolationMarkers sont les fragments (en minuscules) des messages de violation d'unicité des drivers courants.
+// Ils couvrent les drivers qui ne passent pas par la traduction d'erreurs de GORM (gorm.Config.TranslateError).
+var uniqueViolationMarkers = []string{
+	"unique constraint failed",                       // SQLite (mattn/go-sqlite3, glebarez/sqlite)
+	"duplicate key value violates unique constraint", // PostgreSQL
+	"sqlstate 23505",                                 // PostgreSQL (pgx)
+	"error 1062",                                     // MySQL / MariaDB
+	"duplicate entry",                                // MySQL / MariaDB
+	"cannot insert duplicate key",                    // SQL Server
+}
+
+// translateError convertit une violation de contrainte d'unicité, quel que soit le driver, en ErrDuplicateKey.
+// Les autres erreurs sont retournées telles quelles.
+func translateError(err error) error {
+	if err == nil || !isUniqueViolation(err) {
+		return err
+	}
+	return fmt.Errorf("%w: %v", ErrDuplicateKey, err)
+}
+
+// isUniqueViolation indique si err est une violation de contrainte d'unicité.
+func isUniqueViolation(err error) bool {
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return true
+	}
+	msg := strings.ToLower(err.Error())
+	for _, marker := range uniqueViolationMarkers {
+		if strings.Contains(msg, marker) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/axellelanca/urlshortener/internal/models"
+	"gorm.io/gorm"
+)
+
+// Champs de tri acceptés par ListLinks.
+const (
+	SortByCreatedAt = "created_at"
+	SortByClicks    = "clicks"
+)
+
+// LinkCursor identifie la position du dernier élément d'une page (pagination par curseur).
+// Value contient la valeur du champ de tri : horodatage UnixNano pour created_at, nombre de clics pour clicks.
+type LinkCursor struct {
+	Value int64
+	ID    uint
+}
+
+// LinkListFilter regroupe les critères de filtrage, de tri et de pagination de ListLinks.
+type LinkListFilter struct {
+	Domain        string     // Sous-chaîne recherchée dans le domaine de l'URL longue
+	CreatedAfter  *time.Time // Borne inférieure (incluse) de la date de création
+	CreatedBefore *time.Time // Borne supérieure (exclue) de la date de création
+	Status        string     // Statut (models.LinkStatus*), vide = tous sauf la corbeille
+	Tags          []string   // Tags (normalisés) que le lien doit tous porter
+	SortBy        string     // SortByCreatedAt ou SortByClicks
+	Desc          bool       // Tri décroissant
+	Limit         int        // Nombre maximum de liens retournés
+	After         *LinkCursor
+	Now           time.Time // Instant de référence pour les statuts expired/exhausted
+}
+
+// LinkWithClicks est un lien accompagné de son nombre total de clics, tel que retourné par ListLinks.
+type LinkWithClicks struct {
+	models.Link
+	TotalClicks int
+}
+
+// ListLinks récupère une page de liens selon les filtres donnés.
+// La pagination se fait par curseur (keyset) sur le couple (champ de tri, id) :
+// les performances ne se dégradent pas avec la profondeur de la page, contrairement à un OFFSET.
+func (r *GormLinkRepository) ListLinks(filter LinkListFilter) ([]LinkWithClicks, error) {
+	inner := r.db.Model(&models.Link{}).
+		Select("links.*, (SELECT COUNT(*) FROM clicks WHERE clicks.link_id = links.id) AS total_clicks")
+	if filter.Status == models.LinkStatusDeleted {
+		inner = inner.Unscoped()
+	}
+	inner = applyLinkFilters(inner, filter)
+
+	sortColumn := "created_at"
+	if filter.SortBy == SortByClicks {
+		sortColumn = "total_clicks"
+	}
+	direction, comparator := "ASC", ">"
+	if filter.Desc {
+		direction, comparator = "DESC", "<"
+	}
+
+	query := r.db.Table("(?) AS l", inner)
+	if filter.After != nil {
+		var value interface{} = filter.After.Value
+		if filter.SortBy != SortByClicks {
+			value = time.Unix(0, filter.After.Value)
+		}
+		query = query.Where(
+			sortColumn+" "+comparator+" ? OR ("+sortColumn+" = ? AND id "+comparator+" ?)",
+			value, value, filter.After.ID,
+		)
+	}
+
+	var links []LinkWithClicks
+	result := query.
+		Order(sortColumn + " " + direction).
+		Order("id " + direction).
+		Limit(filter.Limit).
+		Find(&links)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return links, nil
+}
+
+// applyLinkFilters ajoute à la requête les conditions de domaine, de date de création, de tags et de statut.
+// Les conditions de statut reproduisent l'ordre de priorité de models.Link.Status.
+// Le filtre de domaine exclut les liens protégés : il permettrait sinon de deviner leur destination caractère par caractère.
+// SQLite compare les dates sous forme de texte : les bornes sont converties dans le fuseau local, celui des dates enregistrées.
+func applyLinkFilters(db *gorm.DB, filter LinkListFilter) *gorm.DB {
+	now := filter.Now.Local()
+	if filter.Domain != "" {
+		db = db.Where("links.domain LIKE ? AND COALESCE(links.password_hash, '') = ''", "%"+filter.Domain+"%")
+	}
+	if filter.CreatedAfter != nil {
+		db = db.Where("links.created_at >= ?", filter.CreatedAfter.Local())
+	}
+	if filter.CreatedBefore != nil {
+		db = db.Where("links.created_at < ?", filter.CreatedBefore.Local())
+	}
+	for _, tag := range filter.Tags {
+		db = db.Where("EXISTS (SELECT 1 FROM link_tags JOIN tags ON tags.id = link_tags.tag_id"+
+			" WHERE link_tags.link_id = links.id AND tags.name = ?)", tag)
+	}
+
+	notExpired := db.Session(&gorm.Session{NewDB: true}).
+		Where("links.expires_at IS NULL").Or("links.expires_at > ?", now)
+	switch filter.Status {
+	case models.LinkStatusDeleted:
+		db = db.Where("links.deleted_at IS NOT NULL")
+	case models.LinkStatusDisabled:
+		db = db.Where("links.disabled = ?", true)
+	case models.LinkStatusExpired:
+		db = db.Where("links.disabled = ? AND links.expires_at IS NOT NULL AND links.expires_at <= ?", false, now)
+	case models.LinkStatusExhausted:
+		db = db.Where("links.disabled = ?", false).Where(notExpired).
+			Where("links.max_clicks > 0 AND links.click_count >= links.max_clicks")
+	case models.LinkStatusScheduled:
+		db = db.Where("links.disabled = ?", false).Where(notExpired).
+			Where("links.activates_at IS NOT NULL AND links.activates_at > ?", now)
+	case models.LinkStatusActive:
+		db = db.Where("links.disabled = ?", false).Where(notExpired).
+			Where("links.activates_at IS NULL OR links.activates_at <= ?", now).
+			Where("links.max_clicks = 0 OR links.click_count < links.max_clicks")
+	}
+	return db
+}
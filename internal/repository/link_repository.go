@@ -1,6 +1,9 @@
 package repository
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/axellelanca/urlshortener/internal/models"
 	"gorm.io/gorm"
 )
@@ -9,18 +12,75 @@ import (
 // pour les opérations CRUD sur les liens.
 // L'implémenter avec les méthodes nécessaires
 type LinkRepository interface {
+	// Transaction exécute fn dans une transaction, avec un repository lié à cette transaction.
+	// Un appel imbriqué crée un point de sauvegarde (savepoint).
+	Transaction(fn func(txRepo LinkRepository) error) error
 	// CreateLink insère un nouveau lien dans la base de données.
+	// Il retourne ErrDuplicateKey si le code court est déjà utilisé (corbeille comprise).
 	CreateLink(link *models.Link) error
-	// DeleteLink supprime un lien de la base de données en utilisant son ID.
+	// DeleteLink met un lien à la corbeille (soft-delete) en utilisant son ID.
 	DeleteLink(linkID uint) error
+	// PurgeLink supprime définitivement un lien ainsi que ses clics, ses révisions, ses destinations alternatives et ses tags.
+	PurgeLink(linkID uint) error
+	// RestoreLink sort un lien de la corbeille.
+	RestoreLink(linkID uint) error
+	// SetLinkDisabled active ou désactive un lien.
+	SetLinkDisabled(linkID uint, disabled bool) error
+	// SetLinkShortcode remplace le code court d'un lien.
+	// Il retourne ErrDuplicateKey si le code court est déjà utilisé.
+	SetLinkShortcode(linkID uint, shortCode string) error
+	// GetDeletedLinkByShortCode récupère un lien à la corbeille en utilisant son shortCode.
+	GetDeletedLinkByShortCode(shortCode string) (*models.Link, error)
+	// GetDeletedLinksBefore récupère les liens mis à la corbeille avant la date donnée.
+	GetDeletedLinksBefore(before time.Time) ([]models.Link, error)
 	// GetLinkByShortCode récupère un lien de la base de données en utilisant son shortCode.
 	GetLinkByShortCode(shortCode string) (*models.Link, error)
-	// GetLinkByLongURL récupère un lien de la base de données en utilisant son URL longue.
-	GetLinkByLongURL(longURL string) (*models.Link, error)
+	// GetLinksByCanonicalURL récupère tous les liens ayant cette URL canonique.
+	GetLinksByCanonicalURL(canonicalURL string) ([]models.Link, error)
 	// GetAllLinks récupère tous les liens de la base de données.
 	GetAllLinks() ([]models.Link, error)
+	// ListLinks récupère une page de liens filtrée, triée et paginée par curseur.
+	ListLinks(filter LinkListFilter) ([]LinkWithClicks, error)
+	// GetLinksWithoutDomain récupère les liens (corbeille incluse) dont le domaine n'est pas encore renseigné.
+	GetLinksWithoutDomain() ([]models.Link, error)
+	// SetLinkDomain renseigne le domaine d'un lien.
+	SetLinkDomain(linkID uint, domain string) error
+	// GetAllLinksWithTrash récupère tous les liens, corbeille incluse.
+	GetAllLinksWithTrash() ([]models.Link, error)
+	// SetLinkCanonicalURL renseigne l'URL canonique d'un lien.
+	SetLinkCanonicalURL(linkID uint, canonicalURL string) error
 	// CountClicksByLinkID compte le nombre total de clics pour un ID de lien donné.
 	CountClicksByLinkID(linkID uint) (int, error)
+	// CountClicksGroupedBy compte les clics d'un lien pour chaque valeur d'une caractéristique des visiteurs (device, country, variant, language, source).
+	CountClicksGroupedBy(linkID uint, column string) (map[string]int, error)
+	// ConsumeClick incrémente atomiquement le compteur de redirections d'un lien limité.
+	ConsumeClick(linkID uint) (bool, error)
+	// UpdateLongURL enregistre l'URL longue (avec son domaine et son URL canonique) d'un lien et la révision correspondante.
+	UpdateLongURL(link *models.Link, revision *models.LinkRevision) error
+	// UpdateLinkRedirect enregistre les options de redirection d'un lien (transmission, aperçu, variante persistante, code HTTP).
+	UpdateLinkRedirect(link *models.Link) error
+	// ReplaceLinkTargets remplace les destinations alternatives d'un lien pour un critère donné.
+	ReplaceLinkTargets(linkID uint, kind string, targets []models.LinkTarget) error
+	// GetLinkTargets récupère les destinations alternatives d'un lien.
+	GetLinkTargets(linkID uint) ([]models.LinkTarget, error)
+	// UpdateLinkActivation enregistre la date d'activation et l'URL d'attente d'un lien.
+	UpdateLinkActivation(link *models.Link) error
+	// UpdateLinkDestination enregistre les métadonnées de la destination d'un lien.
+	UpdateLinkDestination(linkID uint, destination models.DestinationMetadata) error
+	// ReplaceLinkSchedule remplace les changements de destination programmés d'un lien.
+	ReplaceLinkSchedule(linkID uint, schedule []models.LinkSchedule) error
+	// GetLinkSchedule récupère les changements de destination programmés d'un lien.
+	GetLinkSchedule(linkID uint) ([]models.LinkSchedule, error)
+	// ApplyLinkSchedule applique les changements programmés échus d'un lien (URL longue et révision).
+	ApplyLinkSchedule(link *models.Link, until time.Time, revision *models.LinkRevision) (bool, error)
+	// GetRevisionsByLinkID récupère l'historique des modifications d'un lien.
+	GetRevisionsByLinkID(linkID uint) ([]models.LinkRevision, error)
+	// FindOrCreateTags retourne les tags portant ces noms, en créant ceux qui n'existent pas encore.
+	FindOrCreateTags(names []string) ([]models.Tag, error)
+	// UpdateLinkMetadata enregistre le titre, la description, les notes et éventuellement les tags d'un lien.
+	UpdateLinkMetadata(link *models.Link, replaceTags bool) error
+	// GetTagsByLinkIDs récupère les tags de plusieurs liens, indexés par ID de lien.
+	GetTagsByLinkIDs(linkIDs []uint) (map[uint][]models.Tag, error)
 }
 
 // Done :  GormLinkRepository est l'implémentation de LinkRepository utilisant GORM.
@@ -35,17 +95,30 @@ func NewLinkRepository(db *gorm.DB) *GormLinkRepository {
 	return &GormLinkRepository{db: db}
 }
 
+// Transaction exécute fn dans une transaction GORM. Le repository passé à fn utilise la transaction :
+// toutes ses opérations sont validées ensemble, ou annulées si fn retourne une erreur.
+// Appelée sur un repository déjà transactionnel, elle crée un savepoint (transaction imbriquée GORM).
+func (r *GormLinkRepository) Transaction(fn func(txRepo LinkRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&GormLinkRepository{db: tx})
+	})
+}
+
 // CreateLink insère un nouveau lien dans la base de données.
+// L'index unique sur Shortcode garantit qu'un code n'est attribué qu'une fois, même en cas de créations
+// concurrentes : une violation est retournée sous forme de ErrDuplicateKey.
 func (r *GormLinkRepository) CreateLink(link *models.Link) error {
 	// Done 1: Utiliser GORM pour créer un nouvel enregistrement (link) dans la table des liens.
 	result := r.db.Create(link)
 	if result.Error != nil {
-		return result.Error
+		return translateError(result.Error)
 	}
 	return nil
 }
 
-// DeleteLink supprime un lien de la base de données en utilisant son ID.
+// DeleteLink met un lien à la corbeille en utilisant son ID.
+// Le modèle Link ayant un champ DeletedAt, GORM effectue un soft-delete : le lien et ses clics
+// restent en base mais le lien est exclu de toutes les requêtes classiques.
 func (r *GormLinkRepository) DeleteLink(linkID uint) error {
 	// Utiliser GORM pour supprimer le lien avec l'ID donné.
 	result := r.db.Delete(&models.Link{}, linkID)
@@ -55,6 +128,115 @@ func (r *GormLinkRepository) DeleteLink(linkID uint) error {
 	return nil
 }
 
+// PurgeLink supprime définitivement un lien (y compris à la corbeille), ses clics, ses révisions,
+// ses destinations alternatives et ses associations de tags (les tags eux-mêmes sont conservés). Les suppressions sont faites dans une transaction pour ne jamais laisser de clics orphelins.
+func (r *GormLinkRepository) PurgeLink(linkID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("link_id = ?", linkID).Delete(&models.Click{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("link_id = ?", linkID).Delete(&models.LinkRevision{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM link_tags WHERE link_id = ?", linkID).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("link_id = ?", linkID).Delete(&models.LinkTarget{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("link_id = ?", linkID).Delete(&models.LinkSchedule{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(&models.Link{}, linkID).Error
+	})
+}
+
+// RestoreLink sort un lien de la corbeille en remettant son DeletedAt à NULL.
+func (r *GormLinkRepository) RestoreLink(linkID uint) error {
+	result := r.db.Unscoped().Model(&models.Link{}).Where("id = ?", linkID).Update("deleted_at", nil)
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
+
+// SetLinkDisabled active ou désactive un lien.
+func (r *GormLinkRepository) SetLinkDisabled(linkID uint, disabled bool) error {
+	result := r.db.Model(&models.Link{}).Where("id = ?", linkID).Update("disabled", disabled)
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
+
+// UpdateLinkRedirect enregistre les options de redirection d'un lien.
+func (r *GormLinkRepository) UpdateLinkRedirect(link *models.Link) error {
+	updates := map[string]interface{}{
+		"forward_query":   link.ForwardQuery,
+		"forward_path":    link.ForwardPath,
+		"preview":         link.Preview,
+		"sticky_variant":  link.StickyVariant,
+		"redirect_status": link.RedirectStatus,
+	}
+	return r.db.Model(link).Updates(updates).Error
+}
+
+// UpdateLinkActivation enregistre la date d'activation et l'URL d'attente d'un lien.
+func (r *GormLinkRepository) UpdateLinkActivation(link *models.Link) error {
+	updates := map[string]interface{}{
+		"activates_at": link.ActivatesAt,
+		"pending_url":  link.PendingURL,
+	}
+	return r.db.Model(link).Updates(updates).Error
+}
+
+// UpdateLinkDestination enregistre les métadonnées de la destination d'un lien.
+// Les valeurs vides sont écrites aussi : elles remplacent celles d'une récupération précédente.
+func (r *GormLinkRepository) UpdateLinkDestination(linkID uint, destination models.DestinationMetadata) error {
+	updates := map[string]interface{}{
+		"destination_final_url":      destination.FinalURL,
+		"destination_status_code":    destination.StatusCode,
+		"destination_content_type":   destination.ContentType,
+		"destination_title":          destination.Title,
+		"destination_og_title":       destination.OGTitle,
+		"destination_og_description": destination.OGDescription,
+		"destination_og_image":       destination.OGImage,
+		"destination_error":          destination.Error,
+		"destination_fetched_at":     destination.FetchedAt,
+	}
+	return r.db.Model(&models.Link{}).Where("id = ?", linkID).Updates(updates).Error
+}
+
+// SetLinkShortcode remplace le code court d'un lien.
+func (r *GormLinkRepository) SetLinkShortcode(linkID uint, shortCode string) error {
+	result := r.db.Model(&models.Link{}).Where("id = ?", linkID).Update("shortcode", shortCode)
+	if result.Error != nil {
+		return translateError(result.Error)
+	}
+	return nil
+}
+
+// GetDeletedLinkByShortCode récupère un lien à la corbeille en utilisant son shortCode.
+// Il renvoie gorm.ErrRecordNotFound si aucun lien supprimé n'a ce shortCode.
+func (r *GormLinkRepository) GetDeletedLinkByShortCode(shortCode string) (*models.Link, error) {
+	var link models.Link
+	result := r.db.Unscoped().Where("shortcode = ? AND deleted_at IS NOT NULL", shortCode).First(&link)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &link, nil
+}
+
+// GetDeletedLinksBefore récupère les liens mis à la corbeille avant la date donnée.
+func (r *GormLinkRepository) GetDeletedLinksBefore(before time.Time) ([]models.Link, error) {
+	var links []models.Link
+	result := r.db.Unscoped().Where("deleted_at IS NOT NULL AND deleted_at < ?", before).Find(&links)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return links, nil
+}
+
 // GetLinkByShortCode récupère un lien de la base de données en utilisant son shortCode.
 // Il renvoie gorm.ErrRecordNotFound si aucun lien n'est trouvé avec ce shortCode.
 func (r *GormLinkRepository) GetLinkByShortCode(shortCode string) (*models.Link, error) {
@@ -68,15 +250,14 @@ func (r *GormLinkRepository) GetLinkByShortCode(shortCode string) (*models.Link,
 	return &link, nil
 }
 
-// GetLinkByLongURL récupère un lien de la base de données en utilisant son URL longue.
-// Il renvoie gorm.ErrRecordNotFound si aucun lien n'est trouvé avec cette URL.
-func (r *GormLinkRepository) GetLinkByLongURL(longURL string) (*models.Link, error) {
-	var link models.Link
-	result := r.db.Where("long_url = ?", longURL).First(&link)
+// GetLinksByCanonicalURL récupère tous les liens (hors corbeille) ayant cette URL canonique, du plus ancien au plus récent.
+func (r *GormLinkRepository) GetLinksByCanonicalURL(canonicalURL string) ([]models.Link, error) {
+	var links []models.Link
+	result := r.db.Where("canonical_url = ?", canonicalURL).Order("id").Find(&links)
 	if result.Error != nil {
 		return nil, result.Error
 	}
-	return &link, nil
+	return links, nil
 }
 
 // GetAllLinks récupère tous les liens de la base de données.
@@ -91,6 +272,45 @@ func (r *GormLinkRepository) GetAllLinks() ([]models.Link, error) {
 	return links, nil
 }
 
+// GetLinksWithoutDomain récupère les liens (corbeille incluse) dont le domaine n'est pas renseigné,
+// typiquement ceux créés avant l'ajout de la colonne 'domain'.
+func (r *GormLinkRepository) GetLinksWithoutDomain() ([]models.Link, error) {
+	var links []models.Link
+	result := r.db.Unscoped().Where("domain IS NULL OR domain = ''").Find(&links)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return links, nil
+}
+
+// GetAllLinksWithTrash récupère tous les liens, y compris ceux à la corbeille.
+func (r *GormLinkRepository) GetAllLinksWithTrash() ([]models.Link, error) {
+	var links []models.Link
+	result := r.db.Unscoped().Order("id").Find(&links)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return links, nil
+}
+
+// SetLinkCanonicalURL renseigne l'URL canonique d'un lien (corbeille incluse).
+func (r *GormLinkRepository) SetLinkCanonicalURL(linkID uint, canonicalURL string) error {
+	result := r.db.Unscoped().Model(&models.Link{}).Where("id = ?", linkID).Update("canonical_url", canonicalURL)
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
+
+// SetLinkDomain renseigne le domaine d'un lien.
+func (r *GormLinkRepository) SetLinkDomain(linkID uint, domain string) error {
+	result := r.db.Unscoped().Model(&models.Link{}).Where("id = ?", linkID).Update("domain", domain)
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
+
 // CountClicksByLinkID compte le nombre total de clics pour un ID de lien donné.
 func (r *GormLinkRepository) CountClicksByLinkID(linkID uint) (int, error) {
 	var count int64 // GORM retourne un int64 pour les comptes
@@ -103,3 +323,79 @@ func (r *GormLinkRepository) CountClicksByLinkID(linkID uint) (int, error) {
 
 	return int(count), nil
 }
+
+// clickGroupColumns liste les colonnes de la table 'clicks' selon lesquelles CountClicksGroupedBy peut regrouper.
+// Le nom de colonne est inséré tel quel dans la requête : il doit faire partie de cette liste.
+var clickGroupColumns = map[string]bool{
+	"device":   true,
+	"country":  true,
+	"variant":  true,
+	"language": true,
+	"source":   true,
+}
+
+// CountClicksGroupedBy compte les clics d'un lien pour chaque valeur de la colonne column.
+// Les clics sans valeur (colonne vide) sont comptés sous la clé "".
+func (r *GormLinkRepository) CountClicksGroupedBy(linkID uint, column string) (map[string]int, error) {
+	if !clickGroupColumns[column] {
+		return nil, fmt.Errorf("unsupported click grouping column '%s'", column)
+	}
+	var rows []struct {
+		Value  string
+		Clicks int
+	}
+	result := r.db.Model(&models.Click{}).
+		Select("COALESCE("+column+", '') AS value, COUNT(*) AS clicks").
+		Where("link_id = ?", linkID).
+		Group("value").
+		Scan(&rows)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to count clicks by %s: %w", column, result.Error)
+	}
+	counts := make(map[string]int, len(rows))
+	for _, row := range rows {
+		counts[row.Value] += row.Clicks
+	}
+	return counts, nil
+}
+
+// ConsumeClick incrémente atomiquement le compteur de redirections d'un lien, uniquement
+// si sa limite (max_clicks) n'est pas encore atteinte.
+// La vérification et l'incrément sont faits dans une seule requête UPDATE conditionnelle,
+// ce qui garantit le respect de la limite même avec des requêtes concurrentes.
+// Il retourne false si la limite était déjà atteinte.
+func (r *GormLinkRepository) ConsumeClick(linkID uint) (bool, error) {
+	result := r.db.Model(&models.Link{}).
+		Where("id = ? AND (max_clicks = 0 OR click_count < max_clicks)", linkID).
+		UpdateColumn("click_count", gorm.Expr("click_count + 1"))
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// UpdateLongURL enregistre la nouvelle URL longue d'un lien (déjà renseignée sur link, avec son domaine
+// et son URL canonique) et insère la révision associée, dans une transaction.
+func (r *GormLinkRepository) UpdateLongURL(link *models.Link, revision *models.LinkRevision) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		updates := map[string]interface{}{
+			"long_url":      link.LongURL,
+			"domain":        link.Domain,
+			"canonical_url": link.CanonicalURL,
+		}
+		if err := tx.Model(link).Updates(updates).Error; err != nil {
+			return err
+		}
+		return tx.Create(revision).Error
+	})
+}
+
+// GetRevisionsByLinkID récupère l'historique des modifications d'un lien, du plus ancien au plus récent.
+func (r *GormLinkRepository) GetRevisionsByLinkID(linkID uint) ([]models.LinkRevision, error) {
+	var revisions []models.LinkRevision
+	result := r.db.Where("link_id = ?", linkID).Order("created_at ASC, id ASC").Find(&revisions)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return revisions, nil
+}
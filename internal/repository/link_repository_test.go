@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/axellelanca/urlshortener/internal/models"
+	"github.com/axellelanca/urlshortener/internal/testutil"
+)
+
+func TestConsumeClickConcurrentLimit(t *testing.T) {
+	const (
+		maxClicks = 5
+		visitors  = 40
+	)
+	db := testutil.NewDB(t)
+	linkRepo := NewLinkRepository(db)
+	clickRepo := NewClickRepository(db)
+
+	link := &models.Link{Shortcode: "once", LongURL: "https://example.com", MaxClicks: maxClicks, CreatedAt: time.Now()}
+	if err := linkRepo.CreateLink(link); err != nil {
+		t.Fatalf("CreateLink: %v", err)
+	}
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		successes int
+		errs      []error
+	)
+	start := make(chan struct{})
+	for i := 0; i < visitors; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			ok, err := linkRepo.ConsumeClick(link.ID)
+			if err == nil && ok {
+				err = clickRepo.CreateClick(&models.Click{LinkID: link.ID, Timestamp: time.Now()})
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, err)
+			} else if ok {
+				successes++
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	for _, err := range errs {
+		t.Errorf("clic concurrent: %v", err)
+	}
+	if successes != maxClicks {
+		t.Errorf("redirections autorisées = %d, attendu %d", successes, maxClicks)
+	}
+	clicks, err := clickRepo.CountClicksByLinkID(link.ID)
+	if err != nil {
+		t.Fatalf("CountClicksByLinkID: %v", err)
+	}
+	if clicks != maxClicks {
+		t.Errorf("clics enregistrés = %d, attendu %d", clicks, maxClicks)
+	}
+	stored, err := linkRepo.GetLinkByShortCode("once")
+	if err != nil {
+		t.Fatalf("GetLinkByShortCode: %v", err)
+	}
+	if stored.ClickCount != maxClicks {
+		t.Errorf("click_count = %d, attendu %d", stored.ClickCount, maxClicks)
+	}
+}
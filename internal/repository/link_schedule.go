@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/axellelanca/urlshortener/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ReplaceLinkSchedule remplace, dans une transaction, les changements de destination programmés d'un lien.
+// Une liste vide retire tous les changements programmés.
+func (r *GormLinkRepository) ReplaceLinkSchedule(linkID uint, schedule []models.LinkSchedule) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("link_id = ?", linkID).Delete(&models.LinkSchedule{}).Error; err != nil {
+			return err
+		}
+		if len(schedule) == 0 {
+			return nil
+		}
+		for i := range schedule {
+			schedule[i].LinkID = linkID
+		}
+		return translateError(tx.Create(&schedule).Error)
+	})
+}
+
+// GetLinkSchedule récupère les changements de destination programmés d'un lien, du plus proche au plus lointain.
+func (r *GormLinkRepository) GetLinkSchedule(linkID uint) ([]models.LinkSchedule, error) {
+	var schedule []models.LinkSchedule
+	result := r.db.Where("link_id = ?", linkID).Order("switch_at").Find(&schedule)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return schedule, nil
+}
+
+// ApplyLinkSchedule applique, dans une transaction, les changements programmés d'un lien échus à la date until :
+// ils sont supprimés, puis l'URL longue du lien (avec son domaine et son URL canonique) et la révision sont enregistrées.
+// applied vaut false si aucun changement n'était plus à appliquer (déjà appliqué par une requête concurrente) :
+// rien n'est alors modifié. until est comparée dans le fuseau local, celui des dates enregistrées.
+func (r *GormLinkRepository) ApplyLinkSchedule(link *models.Link, until time.Time, revision *models.LinkRevision) (bool, error) {
+	applied := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("link_id = ? AND switch_at <= ?", link.ID, until.Local()).Delete(&models.LinkSchedule{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		updates := map[string]interface{}{
+			"long_url":      link.LongURL,
+			"domain":        link.Domain,
+			"canonical_url": link.CanonicalURL,
+		}
+		// Sans Omit, GORM réenregistrerait link.Schedule, y compris les changements qui viennent d'être supprimés.
+		if err := tx.Model(link).Omit(clause.Associations).Updates(updates).Error; err != nil {
+			return err
+		}
+		applied = true
+		if revision.OldURL == revision.NewURL {
+			return nil
+		}
+		return tx.Create(revision).Error
+	})
+	return applied, err
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/axellelanca/urlshortener/internal/models"
+	"gorm.io/gorm"
+)
+
+// FindOrCreateTags retourne les tags portant les noms donnés (déjà normalisés), en créant ceux qui n'existent pas.
+// Une création concurrente du même tag (violation de l'index unique) est rattrapée par une relecture.
+func (r *GormLinkRepository) FindOrCreateTags(names []string) ([]models.Tag, error) {
+	tags := make([]models.Tag, 0, len(names))
+	for _, name := range names {
+		// Find plutôt que First : un tag absent est le cas courant, pas une erreur à journaliser.
+		var tag models.Tag
+		err := r.db.Where("name = ?", name).Limit(1).Find(&tag).Error
+		if err == nil && tag.ID == 0 {
+			tag = models.Tag{Name: name}
+			err = translateError(r.db.Create(&tag).Error)
+			if errors.Is(err, ErrDuplicateKey) {
+				tag = models.Tag{}
+				err = r.db.Where("name = ?", name).First(&tag).Error
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+// UpdateLinkMetadata enregistre le titre, la description et les notes d'un lien.
+// Si replaceTags est vrai, les tags du lien sont remplacés par link.Tags, dans la même transaction.
+func (r *GormLinkRepository) UpdateLinkMetadata(link *models.Link, replaceTags bool) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		updates := map[string]interface{}{
+			"title":       link.Title,
+			"description": link.Description,
+			"notes":       link.Notes,
+		}
+		if err := tx.Model(link).Updates(updates).Error; err != nil {
+			return err
+		}
+		if !replaceTags {
+			return nil
+		}
+		return tx.Model(link).Association("Tags").Replace(link.Tags)
+	})
+}
+
+// GetTagsByLinkIDs récupère les tags des liens donnés, triés par nom, indexés par ID de lien.
+func (r *GormLinkRepository) GetTagsByLinkIDs(linkIDs []uint) (map[uint][]models.Tag, error) {
+	tagsByLink := make(map[uint][]models.Tag, len(linkIDs))
+	if len(linkIDs) == 0 {
+		return tagsByLink, nil
+	}
+
+	var rows []struct {
+		LinkID uint
+		models.Tag
+	}
+	result := r.db.Table("link_tags").
+		Select("link_tags.link_id, tags.*").
+		Joins("JOIN tags ON tags.id = link_tags.tag_id").
+		Where("link_tags.link_id IN ?", linkIDs).
+		Order("tags.name").
+		Scan(&rows)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	for _, row := range rows {
+		tagsByLink[row.LinkID] = append(tagsByLink[row.LinkID], row.Tag)
+	}
+	return tagsByLink, nil
+}
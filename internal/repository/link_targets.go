@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"github.com/axellelanca/urlshortener/internal/models"
+	"gorm.io/gorm"
+)
+
+// ReplaceLinkTargets remplace, dans une transaction, les destinations alternatives d'un lien pour le critère kind.
+// Une liste vide retire toutes les destinations de ce critère.
+func (r *GormLinkRepository) ReplaceLinkTargets(linkID uint, kind string, targets []models.LinkTarget) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("link_id = ? AND kind = ?", linkID, kind).Delete(&models.LinkTarget{}).Error; err != nil {
+			return err
+		}
+		if len(targets) == 0 {
+			return nil
+		}
+		for i := range targets {
+			targets[i].LinkID = linkID
+			targets[i].Kind = kind
+		}
+		return translateError(tx.Create(&targets).Error)
+	})
+}
+
+// GetLinkTargets récupère les destinations alternatives d'un lien, triées par critère puis par clé.
+func (r *GormLinkRepository) GetLinkTargets(linkID uint) ([]models.LinkTarget, error) {
+	var targets []models.LinkTarget
+	result := r.db.Where("link_id = ?", linkID).Order("kind, target_key").Find(&targets)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return targets, nil
+}
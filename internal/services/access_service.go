@@ -0,0 +1,167 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/axellelanca/urlshortener/internal/models"
+)
+
+// maxPasswordLength est la longueur maximale acceptée par bcrypt.
+const maxPasswordLength = 72
+
+// Limitation des essais de mot de passe : après maxPasswordFailures échecs d'un même client sur un même lien,
+// ses essais suivants sont refusés jusqu'à passwordFailureWindow après le premier échec.
+const (
+	maxPasswordFailures   = 5
+	passwordFailureWindow = 15 * time.Minute
+	failurePurgeThreshold = 1024 // Nombre de compteurs au-delà duquel les fenêtres terminées sont purgées
+)
+
+// passwordFailures compte les échecs d'un client sur un lien depuis le début de la fenêtre.
+type passwordFailures struct {
+	count int
+	since time.Time
+}
+
+// AccessService gère l'accès aux liens protégés par mot de passe : vérification du mot de passe
+// (avec limitation des essais par lien et par client) et émission/validation des jetons de déverrouillage signés.
+type AccessService struct {
+	secret []byte        // Clé HMAC utilisée pour signer les jetons
+	ttl    time.Duration // Durée de validité d'un jeton de déverrouillage
+
+	mu       sync.Mutex
+	failures map[string]*passwordFailures // Échecs par lien et par client (voir failureKey)
+}
+
+// NewAccessService crée et retourne une nouvelle instance de AccessService.
+// Si secret est vide, une clé aléatoire est générée : les jetons émis ne survivent alors pas à un redémarrage.
+func NewAccessService(secret string, ttl time.Duration) (*AccessService, error) {
+	key := []byte(secret)
+	if len(key) == 0 {
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, fmt.Errorf("failed to generate access secret: %w", err)
+		}
+	}
+	return &AccessService{
+		secret:   key,
+		ttl:      ttl,
+		failures: make(map[string]*passwordFailures),
+	}, nil
+}
+
+// TTL retourne la durée de validité d'un jeton de déverrouillage.
+func (s *AccessService) TTL() time.Duration {
+	return s.ttl
+}
+
+// CheckPassword vérifie le mot de passe fourni par un client (son adresse IP) contre le hash stocké sur le lien.
+// Un lien non protégé est toujours accessible. Un client qui a épuisé ses essais (voir RetryAfter) est refusé
+// sans que le mot de passe soit comparé ; un mot de passe correct remet ses échecs à zéro.
+func (s *AccessService) CheckPassword(link *models.Link, password, client string, now time.Time) bool {
+	if !link.IsProtected() {
+		return true
+	}
+	if s.RetryAfter(link, client, now) > 0 {
+		return false
+	}
+	if bcrypt.CompareHashAndPassword([]byte(link.PasswordHash), []byte(password)) != nil {
+		s.recordFailure(link, client, now)
+		return false
+	}
+	s.mu.Lock()
+	delete(s.failures, failureKey(link, client))
+	s.mu.Unlock()
+	return true
+}
+
+// RetryAfter retourne le temps pendant lequel les essais de mot de passe d'un client sur un lien sont encore refusés,
+// ou 0 s'il peut essayer.
+func (s *AccessService) RetryAfter(link *models.Link, client string, now time.Time) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	failures, ok := s.failures[failureKey(link, client)]
+	if !ok || failures.count < maxPasswordFailures {
+		return 0
+	}
+	if wait := failures.since.Add(passwordFailureWindow).Sub(now); wait > 0 {
+		return wait
+	}
+	return 0
+}
+
+// recordFailure enregistre un échec de mot de passe, dans une nouvelle fenêtre si la précédente est terminée.
+// Les fenêtres terminées sont purgées quand le nombre de compteurs grandit, pour borner la mémoire.
+func (s *AccessService) recordFailure(link *models.Link, client string, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.failures) >= failurePurgeThreshold {
+		for key, failures := range s.failures {
+			if !now.Before(failures.since.Add(passwordFailureWindow)) {
+				delete(s.failures, key)
+			}
+		}
+	}
+	key := failureKey(link, client)
+	failures, ok := s.failures[key]
+	if !ok || !now.Before(failures.since.Add(passwordFailureWindow)) {
+		failures = &passwordFailures{since: now}
+		s.failures[key] = failures
+	}
+	failures.count++
+}
+
+// failureKey identifie un couple lien/client dans le compteur d'échecs.
+func failureKey(link *models.Link, client string) string {
+	return strconv.FormatUint(uint64(link.ID), 10) + "|" + client
+}
+
+// IssueToken émet un jeton de déverrouillage signé pour le lien, valable pendant la durée TTL.
+// Le jeton a la forme "<expiration unix>.<signature>".
+func (s *AccessService) IssueToken(link *models.Link, now time.Time) string {
+	expiry := strconv.FormatInt(now.Add(s.ttl).Unix(), 10)
+	return expiry + "." + s.sign(link, expiry)
+}
+
+// VerifyToken vérifie la signature et l'expiration d'un jeton de déverrouillage.
+// La signature inclut le hash du mot de passe : changer le mot de passe invalide les jetons existants.
+func (s *AccessService) VerifyToken(link *models.Link, token string, now time.Time) bool {
+	expiry, signature, found := strings.Cut(token, ".")
+	if !found {
+		return false
+	}
+	expiresAt, err := strconv.ParseInt(expiry, 10, 64)
+	if err != nil || now.Unix() >= expiresAt {
+		return false
+	}
+	return hmac.Equal([]byte(signature), []byte(s.sign(link, expiry)))
+}
+
+// sign calcule la signature HMAC-SHA256 d'un jeton pour un lien et une expiration donnés.
+func (s *AccessService) sign(link *models.Link, expiry string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	fmt.Fprintf(mac, "%d|%s|%s|%s", link.ID, link.Shortcode, link.PasswordHash, expiry)
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// hashPassword calcule le hash bcrypt d'un mot de passe de lien.
+func hashPassword(password string) (string, error) {
+	if len(password) > maxPasswordLength {
+		return "", fmt.Errorf("%w: %d caractères maximum", ErrInvalidPassword, maxPasswordLength)
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+	return string(hash), nil
+}
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/axellelanca/urlshortener/internal/models"
+)
+
+// protectedLink retourne un lien protégé par le mot de passe donné, tel qu'enregistré à la création.
+func protectedLink(t *testing.T, password string) *models.Link {
+	t.Helper()
+	hash, err := hashPassword(password)
+	if err != nil {
+		t.Fatalf("hashPassword: %v", err)
+	}
+	return &models.Link{ID: 1, Shortcode: "lock", PasswordHash: hash}
+}
+
+func TestCheckPasswordThrottle(t *testing.T) {
+	accessService, err := NewAccessService("test-secret", 15*time.Minute)
+	if err != nil {
+		t.Fatalf("NewAccessService: %v", err)
+	}
+	link := protectedLink(t, "s3cret-pass")
+	start := time.Now()
+
+	for i := 0; i < maxPasswordFailures; i++ {
+		if accessService.CheckPassword(link, "mauvais", "192.0.2.1", start) {
+			t.Fatalf("essai %d: mauvais mot de passe accepté", i+1)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		client string
+		at     time.Duration // Délai depuis le premier échec
+		want   bool
+	}{
+		{"bon mot de passe refusé une fois les essais épuisés", "192.0.2.1", time.Minute, false},
+		{"autre client non concerné", "192.0.2.2", time.Minute, true},
+		{"essais rétablis à la fin de la fenêtre", "192.0.2.1", passwordFailureWindow, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := start.Add(tt.at)
+			if got := accessService.CheckPassword(link, "s3cret-pass", tt.client, now); got != tt.want {
+				t.Errorf("CheckPassword = %t, attendu %t", got, tt.want)
+			}
+		})
+	}
+	if wait := accessService.RetryAfter(link, "192.0.2.1", start.Add(passwordFailureWindow)); wait != 0 {
+		t.Errorf("RetryAfter après un succès = %s, attendu 0", wait)
+	}
+}
+
+func TestVerifyToken(t *testing.T) {
+	accessService, err := NewAccessService("test-secret", 15*time.Minute)
+	if err != nil {
+		t.Fatalf("NewAccessService: %v", err)
+	}
+	otherService, err := NewAccessService("autre-secret", 15*time.Minute)
+	if err != nil {
+		t.Fatalf("NewAccessService: %v", err)
+	}
+	link := protectedLink(t, "s3cret-pass")
+	now := time.Now()
+	token := accessService.IssueToken(link, now)
+
+	otherLink := *link
+	otherLink.ID, otherLink.Shortcode = 2, "other"
+	newPassword := *link
+	newPassword.PasswordHash = protectedLink(t, "nouveau-pass").PasswordHash
+
+	tests := []struct {
+		name    string
+		service *AccessService
+		link    *models.Link
+		token   string
+		at      time.Time
+		want    bool
+	}{
+		{"jeton valide", accessService, link, token, now.Add(time.Minute), true},
+		{"jeton expiré", accessService, link, token, now.Add(15 * time.Minute), false},
+		{"autre lien", accessService, &otherLink, token, now, false},
+		{"mot de passe changé", accessService, &newPassword, token, now, false},
+		{"autre clé de signature", otherService, link, token, now, false},
+		{"expiration modifiée", accessService, link, "9999999999" + token[strings.Index(token, "."):], now, false},
+		{"jeton mal formé", accessService, link, "pas-un-jeton", now, false},
+		{"jeton vide", accessService, link, "", now, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.service.VerifyToken(tt.link, tt.token, tt.at); got != tt.want {
+				t.Errorf("VerifyToken = %t, attendu %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckPassword(t *testing.T) {
+	accessService, err := NewAccessService("", 15*time.Minute)
+	if err != nil {
+		t.Fatalf("NewAccessService: %v", err)
+	}
+	link := protectedLink(t, "s3cret-pass")
+	tests := []struct {
+		name     string
+		link     *models.Link
+		password string
+		want     bool
+	}{
+		{"bon mot de passe", link, "s3cret-pass", true},
+		{"mauvais mot de passe", link, "S3cret-pass", false},
+		{"mot de passe vide", link, "", false},
+		{"lien non protégé", &models.Link{ID: 2, Shortcode: "open"}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Un client par cas : les échecs ne s'accumulent pas d'un cas à l'autre.
+			if got := accessService.CheckPassword(tt.link, tt.password, tt.name, time.Now()); got != tt.want {
+				t.Errorf("CheckPassword(%q) = %t, attendu %t", tt.password, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHashPasswordTooLong(t *testing.T) {
+	if _, err := hashPassword(strings.Repeat("a", maxPasswordLength+1)); !errors.Is(err, ErrInvalidPassword) {
+		t.Errorf("hashPassword(%d caractères) = %v, attendu ErrInvalidPassword", maxPasswordLength+1, err)
+	}
+}
@@ -0,0 +1,265 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/axellelanca/urlshortener/internal/models"
+	"github.com/axellelanca/urlshortener/internal/repository"
+)
+
+// maxCampaignNameLength correspond à la taille de la colonne Name (size:100).
+const maxCampaignNameLength = 100
+
+// CampaignInput regroupe les champs d'une campagne, à la création comme à la modification.
+type CampaignInput struct {
+	Name        string
+	Description string
+	StartsAt    *time.Time // Début de la campagne (optionnel)
+	EndsAt      *time.Time // Fin de la campagne, exclue (optionnelle)
+}
+
+// validate vérifie le nom et la cohérence de la période de la campagne.
+func (in CampaignInput) validate() error {
+	if in.Name == "" {
+		return fmt.Errorf("%w: le nom est requis", ErrInvalidCampaign)
+	}
+	if utf8.RuneCountInString(in.Name) > maxCampaignNameLength {
+		return fmt.Errorf("%w: le nom dépasse %d caractères", ErrInvalidCampaign, maxCampaignNameLength)
+	}
+	if in.StartsAt != nil && in.EndsAt != nil && !in.EndsAt.After(*in.StartsAt) {
+		return fmt.Errorf("%w: la date de fin doit être postérieure à la date de début", ErrInvalidCampaign)
+	}
+	return nil
+}
+
+// CampaignLinkStats est le nombre de clics d'un lien de la campagne sur la période des statistiques.
+type CampaignLinkStats struct {
+	Link   models.Link
+	Clicks int
+}
+
+// CampaignStats agrège les clics des liens d'une campagne sur une période.
+type CampaignStats struct {
+	Campaign    *models.Campaign
+	Since       *time.Time // Début de la période (nil = depuis toujours)
+	Until       *time.Time // Fin de la période, exclue (nil = jusqu'à maintenant)
+	TotalClicks int
+	Links       []CampaignLinkStats // Par nombre de clics décroissant
+	Daily       []repository.DailyClickCount
+}
+
+// CampaignService fournit la logique métier des campagnes.
+// Les statistiques sont calculées par le ClickRepository, en une requête pour tous les liens de la campagne.
+type CampaignService struct {
+	campaignRepo repository.CampaignRepository
+	clickRepo    repository.ClickRepository
+}
+
+// NewCampaignService crée et retourne une nouvelle instance de CampaignService.
+func NewCampaignService(campaignRepo repository.CampaignRepository, clickRepo repository.ClickRepository) *CampaignService {
+	return &CampaignService{
+		campaignRepo: campaignRepo,
+		clickRepo:    clickRepo,
+	}
+}
+
+// CreateCampaign crée une nouvelle campagne, sans lien.
+func (s *CampaignService) CreateCampaign(input CampaignInput) (*models.Campaign, error) {
+	input.Name = strings.TrimSpace(input.Name)
+	if err := input.validate(); err != nil {
+		return nil, err
+	}
+	campaign := &models.Campaign{
+		Name:        input.Name,
+		Description: strings.TrimSpace(input.Description),
+		StartsAt:    input.StartsAt,
+		EndsAt:      input.EndsAt,
+	}
+	if err := s.campaignRepo.CreateCampaign(campaign); err != nil {
+		if errors.Is(err, repository.ErrDuplicateKey) {
+			return nil, ErrCampaignAlreadyExists
+		}
+		return nil, fmt.Errorf("failed to create campaign: %w", err)
+	}
+	return campaign, nil
+}
+
+// GetCampaign récupère une campagne et ses liens (hors corbeille).
+// Il renvoie gorm.ErrRecordNotFound si aucune campagne n'a cet ID.
+func (s *CampaignService) GetCampaign(id uint) (*models.Campaign, error) {
+	campaign, err := s.campaignRepo.GetCampaignByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if campaign.Links, err = s.campaignRepo.GetLinksByCampaignID(id); err != nil {
+		return nil, err
+	}
+	return campaign, nil
+}
+
+// ListCampaigns récupère toutes les campagnes avec leur nombre de liens.
+func (s *CampaignService) ListCampaigns() ([]repository.CampaignWithLinkCount, error) {
+	return s.campaignRepo.GetAllCampaigns()
+}
+
+// UpdateCampaign remplace le nom, la description et la période d'une campagne.
+func (s *CampaignService) UpdateCampaign(id uint, input CampaignInput) (*models.Campaign, error) {
+	input.Name = strings.TrimSpace(input.Name)
+	if err := input.validate(); err != nil {
+		return nil, err
+	}
+	campaign, err := s.campaignRepo.GetCampaignByID(id)
+	if err != nil {
+		return nil, err
+	}
+	campaign.Name = input.Name
+	campaign.Description = strings.TrimSpace(input.Description)
+	campaign.StartsAt = input.StartsAt
+	campaign.EndsAt = input.EndsAt
+	if err := s.campaignRepo.UpdateCampaign(campaign); err != nil {
+		if errors.Is(err, repository.ErrDuplicateKey) {
+			return nil, ErrCampaignAlreadyExists
+		}
+		return nil, fmt.Errorf("failed to update campaign: %w", err)
+	}
+	return campaign, nil
+}
+
+// DeleteCampaign supprime une campagne. Ses liens et leurs clics sont conservés.
+func (s *CampaignService) DeleteCampaign(id uint) (*models.Campaign, error) {
+	campaign, err := s.campaignRepo.GetCampaignByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.campaignRepo.DeleteCampaign(id); err != nil {
+		return nil, fmt.Errorf("failed to delete campaign: %w", err)
+	}
+	return campaign, nil
+}
+
+// AddLinks rattache des liens à une campagne. Un lien appartenant déjà à une autre campagne y est déplacé.
+// Si un code court n'existe pas, aucun lien n'est rattaché et ErrUnknownShortCodes est retourné.
+func (s *CampaignService) AddLinks(id uint, shortCodes []string) (*models.Campaign, error) {
+	campaign, err := s.campaignRepo.GetCampaignByID(id)
+	if err != nil {
+		return nil, err
+	}
+	linkIDs, err := s.resolveLinks(shortCodes)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.campaignRepo.SetLinksCampaign(linkIDs, &campaign.ID); err != nil {
+		return nil, fmt.Errorf("failed to add links to campaign: %w", err)
+	}
+	return s.GetCampaign(id)
+}
+
+// RemoveLinks détache des liens d'une campagne. Les liens appartenant à une autre campagne sont ignorés.
+func (s *CampaignService) RemoveLinks(id uint, shortCodes []string) (*models.Campaign, error) {
+	campaign, err := s.GetCampaign(id)
+	if err != nil {
+		return nil, err
+	}
+	remove := make(map[string]bool, len(shortCodes))
+	for _, code := range shortCodes {
+		remove[strings.TrimSpace(code)] = true
+	}
+	var linkIDs []uint
+	for _, link := range campaign.Links {
+		if remove[link.Shortcode] {
+			linkIDs = append(linkIDs, link.ID)
+		}
+	}
+	if err := s.campaignRepo.SetLinksCampaign(linkIDs, nil); err != nil {
+		return nil, fmt.Errorf("failed to remove links from campaign: %w", err)
+	}
+	return s.GetCampaign(id)
+}
+
+// resolveLinks retourne les IDs des liens ayant ces codes courts, ou ErrUnknownShortCodes si certains n'existent pas.
+func (s *CampaignService) resolveLinks(shortCodes []string) ([]uint, error) {
+	wanted := make(map[string]bool, len(shortCodes))
+	codes := make([]string, 0, len(shortCodes))
+	for _, code := range shortCodes {
+		code = strings.TrimSpace(code)
+		if code != "" && !wanted[code] {
+			wanted[code] = true
+			codes = append(codes, code)
+		}
+	}
+	if len(codes) == 0 {
+		return nil, fmt.Errorf("%w: aucun code court fourni", ErrUnknownShortCodes)
+	}
+
+	links, err := s.campaignRepo.GetLinksByShortCodes(codes)
+	if err != nil {
+		return nil, err
+	}
+	linkIDs := make([]uint, 0, len(links))
+	for _, link := range links {
+		delete(wanted, link.Shortcode)
+		linkIDs = append(linkIDs, link.ID)
+	}
+	if len(wanted) > 0 {
+		unknown := make([]string, 0, len(wanted))
+		for code := range wanted {
+			unknown = append(unknown, code)
+		}
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("%w: %s", ErrUnknownShortCodes, strings.Join(unknown, ", "))
+	}
+	return linkIDs, nil
+}
+
+// GetCampaignStats agrège les clics des liens de la campagne sur la période [since, until[.
+// Une borne nil est remplacée par la date correspondante de la campagne (StartsAt, EndsAt), si elle est définie.
+func (s *CampaignService) GetCampaignStats(id uint, since, until *time.Time) (*CampaignStats, error) {
+	campaign, err := s.GetCampaign(id)
+	if err != nil {
+		return nil, err
+	}
+	if since == nil {
+		since = campaign.StartsAt
+	}
+	if until == nil {
+		until = campaign.EndsAt
+	}
+	if since != nil && until != nil && !until.After(*since) {
+		return nil, fmt.Errorf("%w: la fin de la période doit être postérieure à son début", ErrInvalidCampaign)
+	}
+
+	linkIDs := make([]uint, len(campaign.Links))
+	for i, link := range campaign.Links {
+		linkIDs[i] = link.ID
+	}
+	counts, err := s.clickRepo.CountClicksByLinkIDs(linkIDs, since, until)
+	if err != nil {
+		return nil, err
+	}
+	daily, err := s.clickRepo.CountClicksByDay(linkIDs, since, until)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &CampaignStats{
+		Campaign: campaign,
+		Since:    since,
+		Until:    until,
+		Links:    make([]CampaignLinkStats, 0, len(campaign.Links)),
+		Daily:    daily,
+	}
+	for _, link := range campaign.Links {
+		clicks := counts[link.ID]
+		stats.TotalClicks += clicks
+		stats.Links = append(stats.Links, CampaignLinkStats{Link: link, Clicks: clicks})
+	}
+	sort.SliceStable(stats.Links, func(i, j int) bool {
+		return stats.Links[i].Clicks > stats.Links[j].Clicks
+	})
+	return stats, nil
+}
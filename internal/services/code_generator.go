@@ -0,0 +1,244 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Stratégies de génération des codes courts, sélectionnées par shortcode.strategy dans la configuration.
+const (
+	CodeStrategyRandom  = "random"  // Caractères aléatoires (comportement historique)
+	CodeStrategyCounter = "counter" // Encodage en base N de l'ID du lien
+	CodeStrategyHash    = "hash"    // Hash déterministe de l'URL longue
+	CodeStrategyWords   = "words"   // Syllabes prononçables (consonne + voyelle)
+)
+
+// Valeurs par défaut de la génération des codes courts.
+const (
+	defaultCodeLength    = 6
+	defaultWordsAlphabet = "bcdfghjklmnprstvzaeiou"
+	vowels               = "aeiouy"
+)
+
+// CodeRequest regroupe les informations disponibles pour générer un code court.
+type CodeRequest struct {
+	// LongURL est l'URL de destination du lien.
+	LongURL string
+	// LinkID est l'ID du lien, renseigné uniquement pour les générateurs dont NeedsLinkID retourne true.
+	LinkID uint
+	// Attempt vaut 0 au premier essai et augmente à chaque collision avec un code existant.
+	Attempt int
+	// ExtraLength est le nombre de caractères à ajouter à la longueur configurée, augmenté par le
+	// LinkService quand le taux de collision devient trop élevé. La longueur reste plafonnée à aliasMaxLength.
+	ExtraLength int
+}
+
+// codeLength retourne la longueur effective d'un code pour la requête.
+func (req CodeRequest) codeLength(length int) int {
+	length += req.ExtraLength
+	if length > aliasMaxLength {
+		return aliasMaxLength
+	}
+	return length
+}
+
+// CodeGenerator génère les codes courts des liens créés sans alias.
+type CodeGenerator interface {
+	// Generate retourne un code candidat ; son unicité est vérifiée par le LinkService.
+	Generate(req CodeRequest) (string, error)
+	// NeedsLinkID indique que le code dépend de l'ID du lien, qui n'est connu qu'après insertion.
+	NeedsLinkID() bool
+	// MaxExtraLength retourne l'allongement au-delà duquel ExtraLength n'a plus d'effet sur les codes générés.
+	MaxExtraLength() int
+}
+
+// NewCodeGenerator crée le générateur de la stratégie demandée.
+// Une longueur nulle ou un alphabet vide sélectionnent les valeurs par défaut de la stratégie.
+// L'alphabet est limité aux caractères autorisés dans les alias pour que tout code généré soit une URL valide.
+func NewCodeGenerator(strategy string, length int, alphabet string) (CodeGenerator, error) {
+	if length == 0 {
+		length = defaultCodeLength
+	}
+	if length < aliasMinLength || length > aliasMaxLength {
+		return nil, fmt.Errorf("%w: la longueur doit être comprise entre %d et %d", ErrInvalidCodeGenerator, aliasMinLength, aliasMaxLength)
+	}
+
+	if alphabet == "" {
+		alphabet = charset
+		if strategy == CodeStrategyWords {
+			alphabet = defaultWordsAlphabet
+		}
+	}
+	if err := validateAlphabet(alphabet); err != nil {
+		return nil, err
+	}
+
+	switch strategy {
+	case CodeStrategyRandom, "":
+		return &randomCodeGenerator{alphabet: alphabet, length: length}, nil
+	case CodeStrategyCounter:
+		return &counterCodeGenerator{alphabet: alphabet, length: length}, nil
+	case CodeStrategyHash:
+		return &hashCodeGenerator{alphabet: alphabet, length: length}, nil
+	case CodeStrategyWords:
+		var consonants, vowelSet strings.Builder
+		for _, r := range alphabet {
+			if strings.ContainsRune(vowels, r) {
+				vowelSet.WriteRune(r)
+			} else {
+				consonants.WriteRune(r)
+			}
+		}
+		if consonants.Len() == 0 || vowelSet.Len() == 0 {
+			return nil, fmt.Errorf("%w: la stratégie '%s' nécessite au moins une voyelle et une consonne", ErrInvalidCodeGenerator, strategy)
+		}
+		return &wordsCodeGenerator{consonants: consonants.String(), vowels: vowelSet.String(), length: length}, nil
+	default:
+		return nil, fmt.Errorf("%w: stratégie '%s' inconnue (random, counter, hash ou words)", ErrInvalidCodeGenerator, strategy)
+	}
+}
+
+// validateAlphabet vérifie qu'un alphabet contient au moins deux caractères distincts, tous autorisés dans un alias.
+func validateAlphabet(alphabet string) error {
+	seen := make(map[rune]bool, len(alphabet))
+	for _, r := range alphabet {
+		if !strings.ContainsRune(aliasCharset, r) {
+			return fmt.Errorf("%w: caractère '%c' non autorisé dans l'alphabet (lettres, chiffres, '-' et '_' uniquement)", ErrInvalidCodeGenerator, r)
+		}
+		if seen[r] {
+			return fmt.Errorf("%w: caractère '%c' présent plusieurs fois dans l'alphabet", ErrInvalidCodeGenerator, r)
+		}
+		seen[r] = true
+	}
+	if len(seen) < 2 {
+		return fmt.Errorf("%w: l'alphabet doit contenir au moins 2 caractères", ErrInvalidCodeGenerator)
+	}
+	return nil
+}
+
+// randomCodeGenerator tire chaque caractère au hasard dans l'alphabet (crypto/rand).
+type randomCodeGenerator struct {
+	alphabet string
+	length   int
+}
+
+func (g *randomCodeGenerator) Generate(req CodeRequest) (string, error) {
+	return randomString(g.alphabet, req.codeLength(g.length))
+}
+
+func (g *randomCodeGenerator) NeedsLinkID() bool { return false }
+
+func (g *randomCodeGenerator) MaxExtraLength() int { return aliasMaxLength - g.length }
+
+// counterCodeGenerator encode l'ID du lien en base len(alphabet), complété à gauche jusqu'à la longueur minimale.
+// Les codes sont courts, uniques par construction, mais prévisibles. Ils s'allongent d'eux-mêmes avec les IDs :
+// ExtraLength est ignoré. Avec un petit alphabet, ils peuvent dépasser aliasMaxLength : le LinkService les refuse alors.
+type counterCodeGenerator struct {
+	alphabet string
+	length   int
+}
+
+func (g *counterCodeGenerator) Generate(req CodeRequest) (string, error) {
+	if req.LinkID == 0 {
+		return "", fmt.Errorf("counter code generator requires the link ID")
+	}
+	code := encodeBase(new(big.Int).SetUint64(uint64(req.LinkID)), g.alphabet)
+	if pad := g.length - len(code); pad > 0 {
+		code = strings.Repeat(g.alphabet[:1], pad) + code
+	}
+	// Collision possible uniquement avec un alias personnalisé : on ajoute alors un suffixe.
+	if req.Attempt > 0 {
+		code += string(g.alphabet[req.Attempt%len(g.alphabet)])
+	}
+	return code, nil
+}
+
+func (g *counterCodeGenerator) NeedsLinkID() bool { return true }
+
+func (g *counterCodeGenerator) MaxExtraLength() int { return 0 }
+
+// hashCodeGenerator dérive le code du hash SHA-256 de l'URL longue : une même URL donne toujours le même code.
+type hashCodeGenerator struct {
+	alphabet string
+	length   int
+}
+
+func (g *hashCodeGenerator) Generate(req CodeRequest) (string, error) {
+	input := req.LongURL
+	if req.Attempt > 0 {
+		input += "#" + strconv.Itoa(req.Attempt)
+	}
+	sum := sha256.Sum256([]byte(input))
+	code := encodeBase(new(big.Int).SetBytes(sum[:]), g.alphabet)
+	return code[:req.codeLength(g.length)], nil
+}
+
+func (g *hashCodeGenerator) NeedsLinkID() bool { return false }
+
+func (g *hashCodeGenerator) MaxExtraLength() int { return aliasMaxLength - g.length }
+
+// wordsCodeGenerator produit des codes prononçables en alternant consonnes et voyelles (ex: "bakiro").
+type wordsCodeGenerator struct {
+	consonants string
+	vowels     string
+	length     int
+}
+
+func (g *wordsCodeGenerator) Generate(req CodeRequest) (string, error) {
+	var code strings.Builder
+	for i := 0; i < req.codeLength(g.length); i++ {
+		letters := g.consonants
+		if i%2 == 1 {
+			letters = g.vowels
+		}
+		c, err := randomString(letters, 1)
+		if err != nil {
+			return "", err
+		}
+		code.WriteString(c)
+	}
+	return code.String(), nil
+}
+
+func (g *wordsCodeGenerator) NeedsLinkID() bool { return false }
+
+func (g *wordsCodeGenerator) MaxExtraLength() int { return aliasMaxLength - g.length }
+
+// randomString génère une chaîne aléatoire de la longueur donnée à partir de l'alphabet.
+// Il utilise le package 'crypto/rand' pour éviter la prévisibilité.
+func randomString(alphabet string, length int) (string, error) {
+	code := make([]byte, length)
+	alphabetLength := big.NewInt(int64(len(alphabet)))
+
+	for i := 0; i < length; i++ {
+		randIndex, err := rand.Int(rand.Reader, alphabetLength)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate random number: %w", err)
+		}
+		code[i] = alphabet[int(randIndex.Int64())]
+	}
+	return string(code), nil
+}
+
+// encodeBase écrit n dans la base len(alphabet), chiffre de poids fort en premier.
+func encodeBase(n *big.Int, alphabet string) string {
+	if n.Sign() == 0 {
+		return alphabet[:1]
+	}
+	base := big.NewInt(int64(len(alphabet)))
+	value := new(big.Int).Set(n)
+	mod := new(big.Int)
+	var digits []byte
+	for value.Sign() > 0 {
+		value.DivMod(value, base, mod)
+		digits = append(digits, alphabet[mod.Int64()])
+	}
+	for i, j := 0, len(digits)-1; i < j; i, j = i+1, j-1 {
+		digits[i], digits[j] = digits[j], digits[i]
+	}
+	return string(digits)
+}
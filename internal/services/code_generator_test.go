@@ -0,0 +1,152 @@
+package services
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestNewCodeGenerator(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy string
+		length   int
+		alphabet string
+		wantErr  bool
+	}{
+		{"valeurs par défaut", "", 0, "", false},
+		{"random", CodeStrategyRandom, 8, "", false},
+		{"counter", CodeStrategyCounter, 4, "0123456789", false},
+		{"hash", CodeStrategyHash, 10, "", false},
+		{"words", CodeStrategyWords, 6, "", false},
+		{"stratégie inconnue", "uuid", 6, "", true},
+		{"longueur trop courte", CodeStrategyRandom, 2, "", true},
+		{"longueur trop longue", CodeStrategyRandom, 11, "", true},
+		{"caractère interdit", CodeStrategyRandom, 6, "abc/", true},
+		{"caractère répété", CodeStrategyRandom, 6, "abca", true},
+		{"alphabet d'un caractère", CodeStrategyRandom, 6, "a", true},
+		{"words sans voyelle", CodeStrategyWords, 6, "bcdf", true},
+		{"words sans consonne", CodeStrategyWords, 6, "aeiou", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewCodeGenerator(tt.strategy, tt.length, tt.alphabet)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewCodeGenerator = %v, erreur attendue: %t", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidCodeGenerator) {
+				t.Errorf("NewCodeGenerator = %v, attendu ErrInvalidCodeGenerator", err)
+			}
+		})
+	}
+}
+
+func TestCodeGeneratorGenerate(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy string
+		length   int
+		alphabet string
+		req      CodeRequest
+		wantLen  int
+		wantCode string // Code exact pour les stratégies déterministes, "" sinon
+	}{
+		{"random", CodeStrategyRandom, 6, "", CodeRequest{}, 6, ""},
+		{"random allongé", CodeStrategyRandom, 6, "", CodeRequest{ExtraLength: 2}, 8, ""},
+		{"random plafonné", CodeStrategyRandom, 8, "", CodeRequest{ExtraLength: 5}, aliasMaxLength, ""},
+		{"counter complété à gauche", CodeStrategyCounter, 4, "0123456789", CodeRequest{LinkID: 42}, 4, "0042"},
+		{"counter au-delà de la longueur", CodeStrategyCounter, 3, "0123456789", CodeRequest{LinkID: 12345}, 5, "12345"},
+		{"counter après collision", CodeStrategyCounter, 4, "0123456789", CodeRequest{LinkID: 42, Attempt: 3}, 5, "00423"},
+		{"counter ignore l'allongement", CodeStrategyCounter, 4, "0123456789", CodeRequest{LinkID: 42, ExtraLength: 2}, 4, "0042"},
+		{"hash", CodeStrategyHash, 6, "", CodeRequest{LongURL: "https://example.com"}, 6, ""},
+		{"words", CodeStrategyWords, 7, "", CodeRequest{}, 7, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gen, err := NewCodeGenerator(tt.strategy, tt.length, tt.alphabet)
+			if err != nil {
+				t.Fatalf("NewCodeGenerator: %v", err)
+			}
+			code, err := gen.Generate(tt.req)
+			if err != nil {
+				t.Fatalf("Generate: %v", err)
+			}
+			if len(code) != tt.wantLen {
+				t.Errorf("Generate = %q, longueur attendue %d", code, tt.wantLen)
+			}
+			if tt.wantCode != "" && code != tt.wantCode {
+				t.Errorf("Generate = %q, attendu %q", code, tt.wantCode)
+			}
+			if err := ValidateAlias(code); err != nil {
+				t.Errorf("code généré %q invalide comme URL: %v", code, err)
+			}
+		})
+	}
+}
+
+func TestHashCodeGeneratorIsDeterministic(t *testing.T) {
+	gen, err := NewCodeGenerator(CodeStrategyHash, 6, "")
+	if err != nil {
+		t.Fatalf("NewCodeGenerator: %v", err)
+	}
+	generate := func(req CodeRequest) string {
+		code, err := gen.Generate(req)
+		if err != nil {
+			t.Fatalf("Generate: %v", err)
+		}
+		return code
+	}
+
+	first := generate(CodeRequest{LongURL: "https://example.com/a"})
+	if again := generate(CodeRequest{LongURL: "https://example.com/a"}); again != first {
+		t.Errorf("même URL: %q puis %q, attendu le même code", first, again)
+	}
+	if other := generate(CodeRequest{LongURL: "https://example.com/b"}); other == first {
+		t.Errorf("URLs différentes: même code %q", first)
+	}
+	if retry := generate(CodeRequest{LongURL: "https://example.com/a", Attempt: 1}); retry == first {
+		t.Errorf("après collision: même code %q", first)
+	}
+	if longer := generate(CodeRequest{LongURL: "https://example.com/a", ExtraLength: 2}); !strings.HasPrefix(longer, first) {
+		t.Errorf("code allongé %q, attendu un prolongement de %q", longer, first)
+	}
+}
+
+func TestWordsCodeGeneratorAlternates(t *testing.T) {
+	gen, err := NewCodeGenerator(CodeStrategyWords, 8, "")
+	if err != nil {
+		t.Fatalf("NewCodeGenerator: %v", err)
+	}
+	for i := 0; i < 20; i++ {
+		code, err := gen.Generate(CodeRequest{})
+		if err != nil {
+			t.Fatalf("Generate: %v", err)
+		}
+		for j, r := range code {
+			if isVowel := strings.ContainsRune(vowels, r); isVowel != (j%2 == 1) {
+				t.Fatalf("Generate = %q : caractère %d (%c) mal placé", code, j, r)
+			}
+		}
+	}
+}
+
+func TestEncodeBase(t *testing.T) {
+	tests := []struct {
+		n        uint64
+		alphabet string
+		want     string
+	}{
+		{0, "0123456789", "0"},
+		{7, "01", "111"},
+		{255, "0123456789abcdef", "ff"},
+		{61, charset, "9"},
+		{62, charset, "ba"},
+	}
+	for _, tt := range tests {
+		got := encodeBase(new(big.Int).SetUint64(tt.n), tt.alphabet)
+		if got != tt.want {
+			t.Errorf("encodeBase(%d, %q) = %q, attendu %q", tt.n, tt.alphabet, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package services
+
+import "sync"
+
+// Paramètres de l'allongement automatique des codes générés.
+const (
+	collisionWindow        = 50  // Nombre d'allocations observées avant d'évaluer le taux de collision
+	collisionRateThreshold = 0.2 // Taux de collision (collisions / tentatives) au-delà duquel les codes s'allongent
+)
+
+// codeLengthTuner allonge les codes générés quand l'espace des codes se remplit.
+// Il mesure le taux de collision des insertions sur une fenêtre glissante ; au-delà du seuil,
+// un caractère est ajouté à tous les codes générés ensuite. Il est partagé par les goroutines du serveur.
+type codeLengthTuner struct {
+	mu         sync.Mutex
+	extra      int // Caractères ajoutés à la longueur configurée
+	attempts   int // Tentatives d'insertion dans la fenêtre courante
+	collisions int // Collisions dans la fenêtre courante
+}
+
+// extraLength retourne le nombre de caractères à ajouter aux codes générés.
+func (t *codeLengthTuner) extraLength() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.extra
+}
+
+// record enregistre le résultat d'une tentative d'insertion et allonge les codes si le taux de collision
+// de la fenêtre dépasse collisionRateThreshold, sans dépasser maxExtra (voir CodeGenerator.MaxExtraLength).
+func (t *codeLengthTuner) record(collided bool, maxExtra int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.attempts++
+	if collided {
+		t.collisions++
+	}
+	if t.attempts < collisionWindow {
+		return
+	}
+	if float64(t.collisions)/float64(t.attempts) > collisionRateThreshold && t.extra < maxExtra {
+		t.extra++
+	}
+	t.attempts, t.collisions = 0, 0
+}
+
+// grow force l'allongement des codes à au moins extra caractères supplémentaires,
+// quand une allocation a épuisé ses tentatives à la longueur courante.
+func (t *codeLengthTuner) grow(extra int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if extra > t.extra {
+		t.extra = extra
+		t.attempts, t.collisions = 0, 0
+	}
+}
@@ -0,0 +1,372 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm/schema"
+
+	"github.com/axellelanca/urlshortener/internal/repository"
+)
+
+// Format d'export des données. La version est incrémentée à chaque changement incompatible
+// de la structure du fichier (pas du schéma des tables, décrit dans le fichier lui-même).
+const (
+	DatasetFormatName    = "urlshortener-export"
+	DatasetFormatVersion = 1
+)
+
+// Encodages disponibles pour l'export.
+const (
+	DatasetFormatJSONL = "jsonl"
+	DatasetFormatCSV   = "csv"
+)
+
+// csvNull représente la valeur NULL dans un export CSV. Une chaîne commençant par '\' est préfixée
+// d'un '\' supplémentaire pour ne pas être confondue avec lui.
+const csvNull = `\N`
+
+// Types d'enregistrements d'un fichier d'export : un en-tête, puis pour chaque table
+// une déclaration (colonnes, nombre de lignes) suivie de ses lignes.
+const (
+	datasetRecordHeader = "format"
+	datasetRecordTable  = "table"
+	datasetRecordRow    = "row"
+)
+
+// datasetHeader est le premier enregistrement d'un fichier d'export.
+type datasetHeader struct {
+	Format     string    `json:"format"`
+	Version    int       `json:"version"`
+	ExportedAt time.Time `json:"exported_at"`
+}
+
+// datasetRecord est un enregistrement décodé d'un fichier d'export.
+// Les valeurs d'une ligne sont brutes (json.Number, string, bool ou nil) et converties selon la colonne cible.
+type datasetRecord struct {
+	kind    string
+	header  datasetHeader
+	table   string
+	columns []string
+	rows    int64
+	values  map[string]interface{}
+}
+
+// datasetWriter encode un export dans un format donné.
+type datasetWriter interface {
+	writeHeader(header datasetHeader) error
+	beginTable(table repository.TableInfo, rows int64) error
+	writeRow(table repository.TableInfo, values []interface{}) error
+	flush() error
+}
+
+// datasetReader décode un export enregistrement par enregistrement ; io.EOF marque la fin du fichier.
+type datasetReader interface {
+	next() (*datasetRecord, error)
+}
+
+// newDatasetWriter retourne l'encodeur correspondant au format demandé.
+func newDatasetWriter(w io.Writer, format string) (datasetWriter, error) {
+	switch format {
+	case DatasetFormatJSONL:
+		buf := bufio.NewWriter(w)
+		return &jsonlDatasetWriter{buf: buf, enc: json.NewEncoder(buf)}, nil
+	case DatasetFormatCSV:
+		return &csvDatasetWriter{w: csv.NewWriter(w)}, nil
+	default:
+		return nil, fmt.Errorf("%w: format '%s' inconnu (jsonl ou csv)", ErrInvalidDataset, format)
+	}
+}
+
+// IsDatasetDump indique si le flux commence par un en-tête d'export (JSON Lines ou CSV), sans le consommer.
+func IsDatasetDump(r *bufio.Reader) bool {
+	return detectDatasetFormat(r) != ""
+}
+
+// detectDatasetFormat retourne l'encodage d'un export d'après sa première ligne, ou "" si ce n'en est pas un.
+func detectDatasetFormat(r *bufio.Reader) string {
+	head, _ := r.Peek(512) // Un flux plus court que 512 octets retourne ce qui est disponible
+	line, _, _ := bytes.Cut(head, []byte("\n"))
+	line = bytes.TrimPrefix(line, []byte("\xef\xbb\xbf")) // BOM UTF-8
+	switch {
+	case bytes.HasPrefix(line, []byte("{")) && bytes.Contains(line, []byte(`"`+DatasetFormatName+`"`)):
+		return DatasetFormatJSONL
+	case bytes.HasPrefix(line, []byte(datasetRecordHeader+","+DatasetFormatName+",")):
+		return DatasetFormatCSV
+	default:
+		return ""
+	}
+}
+
+// newDatasetReader retourne le décodeur correspondant à l'encodage détecté.
+func newDatasetReader(r *bufio.Reader) (datasetReader, error) {
+	switch detectDatasetFormat(r) {
+	case DatasetFormatJSONL:
+		dec := json.NewDecoder(r)
+		dec.UseNumber()
+		return &jsonlDatasetReader{dec: dec}, nil
+	case DatasetFormatCSV:
+		reader := csv.NewReader(r)
+		reader.FieldsPerRecord = -1
+		return &csvDatasetReader{r: reader}, nil
+	default:
+		return nil, fmt.Errorf("%w: en-tête '%s' absent", ErrInvalidDataset, DatasetFormatName)
+	}
+}
+
+// --- JSON Lines ---
+
+// jsonlLine est la forme commune des lignes d'un export JSON Lines.
+type jsonlLine struct {
+	Format     string                 `json:"format,omitempty"`
+	Version    int                    `json:"version,omitempty"`
+	ExportedAt *time.Time             `json:"exported_at,omitempty"`
+	Table      string                 `json:"table,omitempty"`
+	Columns    []string               `json:"columns,omitempty"`
+	Rows       *int64                 `json:"rows,omitempty"`
+	Row        map[string]interface{} `json:"row,omitempty"`
+}
+
+type jsonlDatasetWriter struct {
+	buf *bufio.Writer
+	enc *json.Encoder
+}
+
+func (w *jsonlDatasetWriter) writeHeader(header datasetHeader) error {
+	return w.enc.Encode(header)
+}
+
+func (w *jsonlDatasetWriter) beginTable(table repository.TableInfo, rows int64) error {
+	return w.enc.Encode(jsonlLine{Table: table.Name, Columns: columnNames(table), Rows: &rows})
+}
+
+func (w *jsonlDatasetWriter) writeRow(table repository.TableInfo, values []interface{}) error {
+	row := make(map[string]interface{}, len(values))
+	for i, col := range table.Columns {
+		value := values[i]
+		if t, ok := value.(time.Time); ok {
+			value = t.Format(time.RFC3339Nano)
+		}
+		row[col.Name] = value // []byte est encodé en base64 par encoding/json
+	}
+	return w.enc.Encode(jsonlLine{Table: table.Name, Row: row})
+}
+
+func (w *jsonlDatasetWriter) flush() error {
+	return w.buf.Flush()
+}
+
+type jsonlDatasetReader struct {
+	dec *json.Decoder
+}
+
+func (r *jsonlDatasetReader) next() (*datasetRecord, error) {
+	var line jsonlLine
+	if err := r.dec.Decode(&line); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, io.EOF
+		}
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDataset, err)
+	}
+	switch {
+	case line.Format != "":
+		header := datasetHeader{Format: line.Format, Version: line.Version}
+		if line.ExportedAt != nil {
+			header.ExportedAt = *line.ExportedAt
+		}
+		return &datasetRecord{kind: datasetRecordHeader, header: header}, nil
+	case line.Row != nil:
+		return &datasetRecord{kind: datasetRecordRow, table: line.Table, values: line.Row}, nil
+	case line.Table != "" && line.Rows != nil:
+		return &datasetRecord{kind: datasetRecordTable, table: line.Table, columns: line.Columns, rows: *line.Rows}, nil
+	default:
+		return nil, fmt.Errorf("%w: ligne JSON non reconnue", ErrInvalidDataset)
+	}
+}
+
+// --- CSV ---
+//
+// Chaque enregistrement commence par son type :
+//   format,urlshortener-export,<version>,<exported_at>
+//   table,<nom>,<nombre de lignes>,<colonne 1>,<colonne 2>,...
+//   row,<valeur 1>,<valeur 2>,...
+
+type csvDatasetWriter struct {
+	w *csv.Writer
+}
+
+func (w *csvDatasetWriter) writeHeader(header datasetHeader) error {
+	return w.w.Write([]string{datasetRecordHeader, header.Format, strconv.Itoa(header.Version), header.ExportedAt.Format(time.RFC3339Nano)})
+}
+
+func (w *csvDatasetWriter) beginTable(table repository.TableInfo, rows int64) error {
+	record := []string{datasetRecordTable, table.Name, strconv.FormatInt(rows, 10)}
+	return w.w.Write(append(record, columnNames(table)...))
+}
+
+func (w *csvDatasetWriter) writeRow(_ repository.TableInfo, values []interface{}) error {
+	record := make([]string, 0, len(values)+1)
+	record = append(record, datasetRecordRow)
+	for _, value := range values {
+		record = append(record, formatCSVValue(value))
+	}
+	return w.w.Write(record)
+}
+
+func (w *csvDatasetWriter) flush() error {
+	w.w.Flush()
+	return w.w.Error()
+}
+
+// formatCSVValue convertit une valeur lue en base en champ CSV.
+func formatCSVValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return csvNull
+	case string:
+		if strings.HasPrefix(v, `\`) {
+			return `\` + v
+		}
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case []byte:
+		return base64.StdEncoding.EncodeToString(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+type csvDatasetReader struct {
+	r       *csv.Reader
+	columns []string
+}
+
+func (r *csvDatasetReader) next() (*datasetRecord, error) {
+	record, err := r.r.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, io.EOF
+		}
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDataset, err)
+	}
+	line, _ := r.r.FieldPos(0)
+
+	switch record[0] {
+	case datasetRecordHeader:
+		if len(record) < 3 {
+			return nil, fmt.Errorf("%w: ligne %d: en-tête incomplet", ErrInvalidDataset, line)
+		}
+		version, err := strconv.Atoi(record[2])
+		if err != nil {
+			return nil, fmt.Errorf("%w: ligne %d: version '%s' invalide", ErrInvalidDataset, line, record[2])
+		}
+		header := datasetHeader{Format: record[1], Version: version}
+		if len(record) > 3 {
+			header.ExportedAt, _ = time.Parse(time.RFC3339Nano, record[3])
+		}
+		return &datasetRecord{kind: datasetRecordHeader, header: header}, nil
+	case datasetRecordTable:
+		if len(record) < 4 {
+			return nil, fmt.Errorf("%w: ligne %d: déclaration de table incomplète", ErrInvalidDataset, line)
+		}
+		rows, err := strconv.ParseInt(record[2], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: ligne %d: nombre de lignes '%s' invalide", ErrInvalidDataset, line, record[2])
+		}
+		r.columns = record[3:]
+		return &datasetRecord{kind: datasetRecordTable, table: record[1], columns: r.columns, rows: rows}, nil
+	case datasetRecordRow:
+		if len(record)-1 != len(r.columns) {
+			return nil, fmt.Errorf("%w: ligne %d: %d valeurs pour %d colonnes", ErrInvalidDataset, line, len(record)-1, len(r.columns))
+		}
+		values := make(map[string]interface{}, len(r.columns))
+		for i, col := range r.columns {
+			values[col] = parseCSVValue(record[i+1])
+		}
+		return &datasetRecord{kind: datasetRecordRow, values: values}, nil
+	default:
+		return nil, fmt.Errorf("%w: ligne %d: type d'enregistrement '%s' inconnu", ErrInvalidDataset, line, record[0])
+	}
+}
+
+// parseCSVValue retourne la valeur brute d'un champ CSV (nil pour NULL).
+func parseCSVValue(field string) interface{} {
+	if field == csvNull {
+		return nil
+	}
+	return strings.TrimPrefix(field, `\`)
+}
+
+// --- Conversion des valeurs ---
+
+// decodeDatasetValue convertit une valeur brute de l'export vers le type de la colonne cible.
+func decodeDatasetValue(col repository.ColumnInfo, raw interface{}) (interface{}, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	text, isText := raw.(string)
+	if number, ok := raw.(json.Number); ok {
+		text, isText = number.String(), true
+	}
+
+	switch col.Type {
+	case schema.Bool:
+		if b, ok := raw.(bool); ok {
+			return b, nil
+		}
+		if isText {
+			return strconv.ParseBool(text)
+		}
+	case schema.Int, schema.Uint:
+		if isText {
+			return strconv.ParseInt(text, 10, 64)
+		}
+	case schema.Float:
+		if isText {
+			return strconv.ParseFloat(text, 64)
+		}
+	case schema.Time:
+		if isText {
+			t, err := time.Parse(time.RFC3339Nano, text)
+			if err != nil {
+				return nil, err
+			}
+			// Le driver SQLite stocke les dates dans le fuseau local, comme les dates créées par l'application.
+			return t.Local(), nil
+		}
+	case schema.Bytes:
+		if isText {
+			return base64.StdEncoding.DecodeString(text)
+		}
+	default:
+		if isText {
+			return text, nil
+		}
+	}
+	return nil, fmt.Errorf("valeur %v incompatible avec le type %s", raw, col.Type)
+}
+
+// columnNames retourne les noms des colonnes d'une table.
+func columnNames(table repository.TableInfo) []string {
+	names := make([]string, len(table.Columns))
+	for i, col := range table.Columns {
+		names[i] = col.Name
+	}
+	return names
+}
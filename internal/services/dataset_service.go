@@ -0,0 +1,239 @@
+package services
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/axellelanca/urlshortener/internal/repository"
+)
+
+// DatasetService exporte et importe l'intégralité des données (liens, clics, révisions et toute
+// table ajoutée à models.AllModels) pour la sauvegarde et la migration entre environnements.
+type DatasetService struct {
+	datasetRepo repository.DatasetRepository
+}
+
+// NewDatasetService crée et retourne une nouvelle instance de DatasetService.
+func NewDatasetService(datasetRepo repository.DatasetRepository) *DatasetService {
+	return &DatasetService{
+		datasetRepo: datasetRepo,
+	}
+}
+
+// DatasetTableCount est le nombre de lignes exportées ou importées pour une table.
+type DatasetTableCount struct {
+	Table string
+	Rows  int64
+}
+
+// DatasetSummary résume un export ou un import.
+type DatasetSummary struct {
+	Version int
+	Tables  []DatasetTableCount
+}
+
+// Export écrit toutes les tables dans w au format demandé (jsonl ou csv).
+// La lecture se fait dans une transaction pour obtenir un instantané cohérent de la base.
+func (s *DatasetService) Export(w io.Writer, format string, now time.Time) (*DatasetSummary, error) {
+	writer, err := newDatasetWriter(w, format)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &DatasetSummary{Version: DatasetFormatVersion}
+	err = s.datasetRepo.Transaction(func(txRepo repository.DatasetRepository) error {
+		tables, err := txRepo.Tables()
+		if err != nil {
+			return err
+		}
+		header := datasetHeader{Format: DatasetFormatName, Version: DatasetFormatVersion, ExportedAt: now}
+		if err := writer.writeHeader(header); err != nil {
+			return err
+		}
+		for _, table := range tables {
+			count, err := txRepo.CountRows(table.Name)
+			if err != nil {
+				return err
+			}
+			if err := writer.beginTable(table, count); err != nil {
+				return err
+			}
+			err = txRepo.EachRow(table, func(values []interface{}) error {
+				return writer.writeRow(table, values)
+			})
+			if err != nil {
+				return err
+			}
+			summary.Tables = append(summary.Tables, DatasetTableCount{Table: table.Name, Rows: count})
+		}
+		return writer.flush()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("export failed: %w", err)
+	}
+	return summary, nil
+}
+
+// Import restaure un export (jsonl ou csv, détecté automatiquement) en conservant les IDs et les codes courts.
+// Les tables importées doivent exister (migrate) et être vides. Tout est fait dans une seule transaction :
+// en cas d'erreur, y compris une clé étrangère (ex: Click.LinkID) ne référençant aucune ligne, rien n'est importé.
+func (s *DatasetService) Import(r io.Reader) (*DatasetSummary, error) {
+	reader, err := newDatasetReader(bufio.NewReader(r))
+	if err != nil {
+		return nil, err
+	}
+
+	record, err := reader.next()
+	if err != nil {
+		return nil, err
+	}
+	if record.kind != datasetRecordHeader || record.header.Format != DatasetFormatName {
+		return nil, fmt.Errorf("%w: le fichier doit commencer par l'en-tête '%s'", ErrInvalidDataset, DatasetFormatName)
+	}
+	if record.header.Version < 1 || record.header.Version > DatasetFormatVersion {
+		return nil, fmt.Errorf("%w: version %d (version prise en charge: %d)", ErrUnsupportedDatasetVersion, record.header.Version, DatasetFormatVersion)
+	}
+
+	summary := &DatasetSummary{Version: record.header.Version}
+	err = s.datasetRepo.Transaction(func(txRepo repository.DatasetRepository) error {
+		tables, err := txRepo.Tables()
+		if err != nil {
+			return err
+		}
+		tablesByName := make(map[string]repository.TableInfo, len(tables))
+		for _, table := range tables {
+			tablesByName[table.Name] = table
+		}
+
+		var current *datasetTableImport
+		imported := make(map[string]bool)
+		for {
+			record, err := reader.next()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return err
+			}
+
+			switch record.kind {
+			case datasetRecordTable:
+				if err := current.finish(summary); err != nil {
+					return err
+				}
+				table, ok := tablesByName[record.table]
+				if !ok {
+					return fmt.Errorf("%w: table '%s' inconnue de ce schéma (exécutez 'migrate' avec une version à jour)", ErrInvalidDataset, record.table)
+				}
+				if imported[table.Name] {
+					return fmt.Errorf("%w: table '%s' déclarée deux fois", ErrInvalidDataset, table.Name)
+				}
+				imported[table.Name] = true
+				if current, err = beginTableImport(txRepo, table, record); err != nil {
+					return err
+				}
+			case datasetRecordRow:
+				if current == nil || (record.table != "" && record.table != current.table.Name) {
+					return fmt.Errorf("%w: ligne hors de la déclaration de sa table", ErrInvalidDataset)
+				}
+				if err := current.insert(txRepo, record.values); err != nil {
+					return err
+				}
+			default:
+				return fmt.Errorf("%w: en-tête inattendu au milieu du fichier", ErrInvalidDataset)
+			}
+		}
+		if err := current.finish(summary); err != nil {
+			return err
+		}
+
+		return checkReferentialIntegrity(txRepo, tables)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("import failed: %w", err)
+	}
+	return summary, nil
+}
+
+// datasetTableImport suit l'import d'une table en cours.
+type datasetTableImport struct {
+	table    repository.TableInfo
+	columns  []repository.ColumnInfo // Colonnes de la table cible, dans l'ordre de l'export
+	expected int64
+	inserted int64
+}
+
+// beginTableImport vérifie qu'une table déclarée dans l'export peut être importée.
+// Les colonnes absentes de l'export (ajoutées depuis par une migration) prennent leur valeur par défaut.
+func beginTableImport(txRepo repository.DatasetRepository, table repository.TableInfo, record *datasetRecord) (*datasetTableImport, error) {
+	count, err := txRepo.CountRows(table.Name)
+	if err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, fmt.Errorf("%w: la table '%s' contient déjà %d ligne(s)", ErrDatasetTargetNotEmpty, table.Name, count)
+	}
+
+	current := &datasetTableImport{table: table, expected: record.rows}
+	for _, name := range record.columns {
+		col, ok := table.Column(name)
+		if !ok {
+			return nil, fmt.Errorf("%w: colonne '%s.%s' inconnue de ce schéma", ErrInvalidDataset, table.Name, name)
+		}
+		current.columns = append(current.columns, col)
+	}
+	return current, nil
+}
+
+// insert convertit et insère une ligne de l'export.
+func (t *datasetTableImport) insert(txRepo repository.DatasetRepository, values map[string]interface{}) error {
+	row := make(map[string]interface{}, len(t.columns))
+	for _, col := range t.columns {
+		raw, ok := values[col.Name]
+		if !ok {
+			return fmt.Errorf("%w: table '%s', ligne %d: colonne '%s' manquante", ErrInvalidDataset, t.table.Name, t.inserted+1, col.Name)
+		}
+		value, err := decodeDatasetValue(col, raw)
+		if err != nil {
+			return fmt.Errorf("%w: table '%s', ligne %d, colonne '%s': %v", ErrInvalidDataset, t.table.Name, t.inserted+1, col.Name, err)
+		}
+		row[col.Name] = value
+	}
+	if err := txRepo.InsertRow(t.table.Name, row); err != nil {
+		return err
+	}
+	t.inserted++
+	return nil
+}
+
+// finish vérifie que toutes les lignes annoncées ont été importées (fichier tronqué sinon).
+func (t *datasetTableImport) finish(summary *DatasetSummary) error {
+	if t == nil {
+		return nil
+	}
+	if t.inserted != t.expected {
+		return fmt.Errorf("%w: table '%s': %d ligne(s) annoncée(s), %d trouvée(s)", ErrInvalidDataset, t.table.Name, t.expected, t.inserted)
+	}
+	summary.Tables = append(summary.Tables, DatasetTableCount{Table: t.table.Name, Rows: t.inserted})
+	return nil
+}
+
+// checkReferentialIntegrity vérifie qu'aucune clé étrangère ne référence une ligne absente.
+// SQLite n'appliquant pas les contraintes de clés étrangères par défaut, la vérification est explicite.
+func checkReferentialIntegrity(txRepo repository.DatasetRepository, tables []repository.TableInfo) error {
+	for _, table := range tables {
+		for _, fk := range table.ForeignKeys {
+			orphans, err := txRepo.CountOrphans(table.Name, fk)
+			if err != nil {
+				return err
+			}
+			if orphans > 0 {
+				return fmt.Errorf("%w: %d ligne(s) de '%s' avec un %s absent de '%s'", ErrDatasetIntegrity, orphans, table.Name, fk.Column, fk.RefTable)
+			}
+		}
+	}
+	return nil
+}
@@ -7,6 +7,80 @@ var (
 	// ErrURLAlreadyExists est retourné quand une URL longue existe déjà dans la base
 	ErrURLAlreadyExists = errors.New("URL existante")
 
-	// ErrShortCodeCollision est retourné quand tous les codes courts générés sont déjà utilisés
+	// ErrShortCodeCollision est retourné quand l'espace des codes générés est épuisé : tous les codes de longueur
+	// maximale essayés sont déjà utilisés, ou le code dépasse la taille maximale d'un code court
 	ErrShortCodeCollision = errors.New("failed to generate unique short code after maximum retries")
+
+	// ErrInvalidAlias est retourné quand un alias personnalisé ne respecte pas la politique de codes courts
+	ErrInvalidAlias = errors.New("alias invalide")
+
+	// ErrAliasAlreadyExists est retourné quand l'alias demandé est déjà utilisé comme code court
+	ErrAliasAlreadyExists = errors.New("alias déjà utilisé")
+
+	// ErrInvalidExpiration est retourné quand les options d'expiration sont incohérentes
+	ErrInvalidExpiration = errors.New("expiration invalide")
+
+	// ErrInvalidClickLimit est retourné quand la limite de clics demandée est incohérente
+	ErrInvalidClickLimit = errors.New("limite de clics invalide")
+
+	// ErrClickLimitReached est retourné quand un lien limité a consommé toutes ses redirections
+	ErrClickLimitReached = errors.New("limite de clics atteinte")
+
+	// ErrInvalidPassword est retourné quand le mot de passe de protection d'un lien est invalide
+	ErrInvalidPassword = errors.New("mot de passe invalide")
+
+	// ErrRestoreWindowExpired est retourné quand un lien est resté à la corbeille au-delà de la durée de rétention
+	ErrRestoreWindowExpired = errors.New("délai de restauration dépassé")
+
+	// ErrInvalidListParams est retourné quand les paramètres de listage (tri, filtre, curseur) sont invalides
+	ErrInvalidListParams = errors.New("paramètres de liste invalides")
+
+	// ErrInvalidLongURL est retourné quand une URL longue n'est pas une URL http(s) valide
+	ErrInvalidLongURL = errors.New("URL longue invalide")
+
+	// ErrInvalidDataset est retourné quand un fichier d'export est illisible ou incohérent
+	ErrInvalidDataset = errors.New("fichier d'export invalide")
+
+	// ErrUnsupportedDatasetVersion est retourné quand la version d'un fichier d'export n'est pas prise en charge
+	ErrUnsupportedDatasetVersion = errors.New("version du fichier d'export non prise en charge")
+
+	// ErrDatasetTargetNotEmpty est retourné quand l'import viserait une table contenant déjà des données
+	ErrDatasetTargetNotEmpty = errors.New("la base cible n'est pas vide")
+
+	// ErrDatasetIntegrity est retourné quand des données importées référencent des lignes inexistantes
+	ErrDatasetIntegrity = errors.New("intégrité référentielle non respectée")
+
+	// ErrInvalidCodeGenerator est retourné quand la stratégie de génération des codes courts est mal configurée
+	ErrInvalidCodeGenerator = errors.New("générateur de codes courts invalide")
+
+	// ErrInvalidMetadata est retourné quand le titre, la description, les notes ou les tags d'un lien sont invalides
+	ErrInvalidMetadata = errors.New("métadonnées invalides")
+
+	// ErrInvalidUTM est retourné quand un paramètre ou un preset UTM est invalide
+	ErrInvalidUTM = errors.New("paramètres UTM invalides")
+
+	// ErrPathForwardingDisabled est retourné quand une redirection demande un chemin après le code court
+	// alors que le lien ne transmet pas le chemin
+	ErrPathForwardingDisabled = errors.New("le lien ne transmet pas de chemin")
+
+	// ErrInvalidRedirectStatus est retourné quand un code de redirection n'est pas 301, 302, 307 ou 308
+	ErrInvalidRedirectStatus = errors.New("code de redirection invalide")
+
+	// ErrInvalidTarget est retourné quand une destination alternative (par appareil...) est invalide
+	ErrInvalidTarget = errors.New("destination alternative invalide")
+
+	// ErrInvalidSchedule est retourné quand la date d'activation ou un changement de destination programmé est invalide
+	ErrInvalidSchedule = errors.New("programmation invalide")
+
+	// ErrInvalidQROptions est retourné quand une option d'un QR code (format, taille, marge, niveau, couleurs) est invalide
+	ErrInvalidQROptions = errors.New("options de QR code invalides")
+
+	// ErrInvalidCampaign est retourné quand le nom ou la période d'une campagne sont invalides
+	ErrInvalidCampaign = errors.New("campagne invalide")
+
+	// ErrCampaignAlreadyExists est retourné quand le nom d'une campagne est déjà utilisé
+	ErrCampaignAlreadyExists = errors.New("campagne déjà existante")
+
+	// ErrUnknownShortCodes est retourné quand des codes courts à rattacher à une campagne n'existent pas
+	ErrUnknownShortCodes = errors.New("codes courts introuvables")
 )
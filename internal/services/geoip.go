@@ -0,0 +1,66 @@
+package services
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/oschwald/geoip2-golang"
+)
+
+// CountryLocator détermine le pays d'une adresse IP, sous forme de code ISO 3166-1 alpha-2 en majuscules.
+// Un pays vide signifie que l'adresse n'est pas localisable (adresse privée, absente de la base...).
+type CountryLocator interface {
+	Country(ip net.IP) (string, error)
+}
+
+// GeoIPDatabase est un CountryLocator qui s'appuie sur une base MaxMind (.mmdb) hors ligne,
+// par exemple GeoLite2-Country ou GeoLite2-City. Aucun appel réseau n'est fait pendant la redirection.
+type GeoIPDatabase struct {
+	reader *geoip2.Reader
+}
+
+// OpenGeoIPDatabase ouvre la base GeoIP au chemin donné. Elle doit être fermée avec Close.
+func OpenGeoIPDatabase(path string) (*GeoIPDatabase, error) {
+	reader, err := geoip2.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open GeoIP database '%s': %w", path, err)
+	}
+	return &GeoIPDatabase{reader: reader}, nil
+}
+
+// Country retourne le code pays de l'adresse IP, ou "" si elle est absente de la base.
+func (d *GeoIPDatabase) Country(ip net.IP) (string, error) {
+	record, err := d.reader.Country(ip)
+	if err != nil {
+		return "", err
+	}
+	return strings.ToUpper(record.Country.IsoCode), nil
+}
+
+// Close libère la base GeoIP.
+func (d *GeoIPDatabase) Close() error {
+	return d.reader.Close()
+}
+
+// SetCountryLocator active la géolocalisation des visiteurs lors des redirections (voir OpenGeoIPDatabase).
+// Sans localisateur, le pays des visiteurs est inconnu et les destinations par pays ne sont jamais utilisées.
+func (s *LinkService) SetCountryLocator(locator CountryLocator) {
+	s.countryLocator = locator
+}
+
+// lookupCountry retourne le pays de l'adresse IP d'un visiteur, ou "" s'il ne peut pas être déterminé.
+func (s *LinkService) lookupCountry(ipAddress string) string {
+	if s.countryLocator == nil {
+		return ""
+	}
+	ip := net.ParseIP(ipAddress)
+	if ip == nil {
+		return ""
+	}
+	country, err := s.countryLocator.Country(ip)
+	if err != nil {
+		return ""
+	}
+	return country
+}
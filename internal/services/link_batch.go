@@ -0,0 +1,100 @@
+package services
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/axellelanca/urlshortener/internal/models"
+	"github.com/axellelanca/urlshortener/internal/repository"
+)
+
+// batchChunkSize est le nombre de liens créés par transaction lors d'une création en lot.
+// Il limite la durée pendant laquelle la base reste verrouillée en écriture.
+const batchChunkSize = 500
+
+// BatchLinkRequest est un élément d'une création de liens en lot.
+type BatchLinkRequest struct {
+	LongURL string
+	Options CreateLinkOptions
+}
+
+// BatchLinkResult est le résultat de la création d'un élément du lot.
+// Err est nil si le lien a été créé (ou retourné par la politique de doublons, Existing = true), sinon Link est nil.
+type BatchLinkResult struct {
+	Index    int
+	LongURL  string
+	Link     *models.Link
+	Existing bool
+	Err      error
+}
+
+// CreateLinksBatch crée plusieurs liens en suivant le même chemin que CreateLink.
+// Les liens sont créés par paquets de batchChunkSize dans une transaction chacun, et chaque élément
+// dans un savepoint : l'échec d'un élément (URL déjà existante, alias pris...) est reporté dans son
+// résultat sans interrompre le lot. Une erreur n'est retournée que si une transaction elle-même échoue.
+// Les métadonnées des destinations ne sont récupérées, en arrière-plan, qu'une fois la transaction validée.
+func (s *LinkService) CreateLinksBatch(requests []BatchLinkRequest) ([]BatchLinkResult, error) {
+	results := make([]BatchLinkResult, 0, len(requests))
+
+	for start := 0; start < len(requests); start += batchChunkSize {
+		end := start + batchChunkSize
+		if end > len(requests) {
+			end = len(requests)
+		}
+
+		var chunkResults []BatchLinkResult
+		err := s.linkRepo.Transaction(func(txRepo repository.LinkRepository) error {
+			chunkResults = make([]BatchLinkResult, 0, end-start)
+			for i := start; i < end; i++ {
+				req := requests[i]
+				result := BatchLinkResult{Index: i, LongURL: req.LongURL}
+
+				// Savepoint par élément : une erreur n'annule que cet élément.
+				err := txRepo.Transaction(func(itemRepo repository.LinkRepository) error {
+					link, created, err := s.withRepo(itemRepo).createLink(req.LongURL, req.Options)
+					result.Link = link
+					result.Existing = !created
+					return err
+				})
+				if err != nil {
+					result.Link = nil
+					result.Existing = false
+					result.Err = err
+				}
+				chunkResults = append(chunkResults, result)
+			}
+			return nil
+		})
+		if err != nil {
+			return results, fmt.Errorf("batch transaction failed at item %d: %w", start, err)
+		}
+		for _, result := range chunkResults {
+			if result.Link != nil && !result.Existing && s.shouldFetchDestination(requests[result.Index].Options) {
+				s.fetchDestinationAsync(result.Link.ID, result.Link.LongURL)
+			}
+		}
+		results = append(results, chunkResults...)
+	}
+
+	return results, nil
+}
+
+// withRepo retourne une copie du service utilisant le repository donné (typiquement transactionnel).
+func (s *LinkService) withRepo(linkRepo repository.LinkRepository) *LinkService {
+	clone := *s
+	clone.linkRepo = linkRepo
+	return &clone
+}
+
+// validateLongURL vérifie qu'une URL longue est une URL absolue http ou https : les autres schémas
+// (ftp:, javascript:, data:…) sont refusés à la création comme à la modification d'un lien.
+func validateLongURL(longURL string) error {
+	u, err := url.ParseRequestURI(longURL)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidLongURL, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: seules les URLs http(s) absolues sont acceptées", ErrInvalidLongURL)
+	}
+	return nil
+}
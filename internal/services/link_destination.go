@@ -0,0 +1,342 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"golang.org/x/net/html"
+
+	"github.com/axellelanca/urlshortener/internal/models"
+)
+
+// Limites de la récupération des métadonnées d'une destination.
+const (
+	defaultDestinationFetchTimeout = 10 * time.Second
+	maxDestinationBodySize         = 1 << 20 // Seul le début de la page est lu : le <head> s'y trouve
+	maxDestinationTitleLength      = 255     // Taille des colonnes de titre
+	maxDestinationTextLength       = 2000    // Description, URL de l'image et message d'erreur
+	maxDestinationRedirects        = 10      // Redirections suivies, comme le client HTTP par défaut
+)
+
+// destinationUserAgent est le User-Agent des requêtes de récupération des métadonnées.
+const destinationUserAgent = "urlshortener-metadata/1.0"
+
+// DestinationFetcher récupère les métadonnées de la destination d'un lien avec une requête GET :
+// URL finale après redirections, code HTTP, type de contenu, et pour une page HTML son <title>
+// et ses balises Open Graph (og:title, og:description, og:image).
+type DestinationFetcher struct {
+	client *http.Client
+}
+
+// NewDestinationFetcher crée un DestinationFetcher utilisant le client HTTP donné, qui suit les redirections
+// selon sa propre politique. Un client nil est remplacé par celui de newDestinationClient, avec un timeout de 10 secondes.
+func NewDestinationFetcher(client *http.Client) *DestinationFetcher {
+	if client == nil {
+		client = newDestinationClient(defaultDestinationFetchTimeout)
+	}
+	return &DestinationFetcher{client: client}
+}
+
+// errNonPublicDestination est retourné quand une destination (ou une redirection) mène à une adresse non publique.
+var errNonPublicDestination = errors.New("destination refusée : adresse non publique")
+
+// nonPublicPrefixes sont les plages d'adresses refusées en plus de celles reconnues par netip
+// (boucle locale, réseaux privés, lien local dont 169.254.169.254, multicast, adresse non spécifiée).
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),     // "Ce réseau"
+	netip.MustParsePrefix("100.64.0.0/10"), // NAT des opérateurs (RFC 6598)
+}
+
+// isPublicAddr indique si une destination peut être contactée à l'adresse addr.
+func isPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return false
+	}
+	for _, prefix := range nonPublicPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// checkDialAddress refuse les connexions vers une adresse non publique. Elle est appelée par le net.Dialer
+// après la résolution DNS, pour chaque connexion (redirections comprises) : un nom d'hôte qui se résout
+// vers le réseau interne est refusé, même si sa résolution change entre deux requêtes.
+func checkDialAddress(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errNonPublicDestination, address)
+	}
+	if !isPublicAddr(addrPort.Addr()) {
+		return fmt.Errorf("%w: %s", errNonPublicDestination, addrPort.Addr())
+	}
+	return nil
+}
+
+// checkDestinationRedirect vérifie chaque redirection avant de la suivre : schéma http(s), au plus
+// maxDestinationRedirects redirections, et hôte qui ne se résout vers aucune adresse non publique.
+func checkDestinationRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxDestinationRedirects {
+		return fmt.Errorf("plus de %d redirections", maxDestinationRedirects)
+	}
+	if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+		return fmt.Errorf("%w: schéma '%s'", errNonPublicDestination, req.URL.Scheme)
+	}
+	host := req.URL.Hostname()
+	if addr, err := netip.ParseAddr(host); err == nil {
+		if !isPublicAddr(addr) {
+			return fmt.Errorf("%w: %s", errNonPublicDestination, addr)
+		}
+		return nil
+	}
+	addrs, err := net.DefaultResolver.LookupNetIP(req.Context(), "ip", host)
+	if err != nil {
+		return err
+	}
+	for _, addr := range addrs {
+		if !isPublicAddr(addr) {
+			return fmt.Errorf("%w: %s (%s)", errNonPublicDestination, host, addr)
+		}
+	}
+	return nil
+}
+
+// newDestinationClient crée le client HTTP de récupération des métadonnées : les URLs longues sont fournies
+// par les utilisateurs, il refuse donc de contacter le réseau interne du serveur (voir checkDialAddress et
+// checkDestinationRedirect). Aucun proxy n'est utilisé : la vérification porte sur l'adresse réellement contactée.
+func newDestinationClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout, Control: checkDialAddress}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Timeout:       timeout,
+		Transport:     transport,
+		CheckRedirect: checkDestinationRedirect,
+	}
+}
+
+// Fetch récupère les métadonnées de la destination rawURL. Une destination injoignable n'est pas une erreur :
+// le problème est décrit dans le champ Error du résultat, qui est toujours horodaté.
+func (f *DestinationFetcher) Fetch(ctx context.Context, rawURL string) models.DestinationMetadata {
+	fetchedAt := time.Now()
+	metadata := models.DestinationMetadata{FetchedAt: &fetchedAt}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		metadata.Error = truncateRunes(err.Error(), maxDestinationTextLength)
+		return metadata
+	}
+	req.Header.Set("User-Agent", destinationUserAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml;q=0.9,*/*;q=0.8")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		metadata.Error = truncateRunes(err.Error(), maxDestinationTextLength)
+		return metadata
+	}
+	defer resp.Body.Close()
+
+	finalURL := resp.Request.URL
+	metadata.FinalURL = finalURL.String()
+	metadata.StatusCode = resp.StatusCode
+	if mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type")); err == nil {
+		metadata.ContentType = truncateRunes(mediaType, maxDestinationTitleLength)
+	}
+	if metadata.ContentType == "text/html" || metadata.ContentType == "application/xhtml+xml" {
+		parseHTMLMetadata(io.LimitReader(resp.Body, maxDestinationBodySize), finalURL, &metadata)
+	}
+	return metadata
+}
+
+// parseHTMLMetadata renseigne le titre et les balises Open Graph de metadata à partir du début d'une page HTML.
+// La lecture s'arrête à la fin du <head> ; les URLs relatives d'og:image sont résolues par rapport à base.
+func parseHTMLMetadata(body io.Reader, base *url.URL, metadata *models.DestinationMetadata) {
+	tokenizer := html.NewTokenizer(body)
+	inTitle := false
+	var title strings.Builder
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			metadata.Title = truncateRunes(collapseSpaces(title.String()), maxDestinationTitleLength)
+			return
+		case html.TextToken:
+			if inTitle {
+				title.Write(tokenizer.Text())
+			}
+		case html.EndTagToken:
+			name, _ := tokenizer.TagName()
+			switch string(name) {
+			case "title":
+				inTitle = false
+			case "head":
+				metadata.Title = truncateRunes(collapseSpaces(title.String()), maxDestinationTitleLength)
+				return
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, hasAttr := tokenizer.TagName()
+			switch string(name) {
+			case "title":
+				// Seul le premier <title> compte (un <svg> peut en contenir d'autres).
+				inTitle = title.Len() == 0
+			case "body":
+				metadata.Title = truncateRunes(collapseSpaces(title.String()), maxDestinationTitleLength)
+				return
+			case "meta":
+				if hasAttr {
+					applyOpenGraphTag(tokenizer, base, metadata)
+				}
+			}
+		}
+	}
+}
+
+// applyOpenGraphTag renseigne metadata à partir d'une balise <meta property="og:..." content="...">.
+// Certains sites utilisent name au lieu de property : les deux sont acceptés. La première valeur l'emporte.
+func applyOpenGraphTag(tokenizer *html.Tokenizer, base *url.URL, metadata *models.DestinationMetadata) {
+	var property, content string
+	for {
+		key, value, more := tokenizer.TagAttr()
+		switch string(key) {
+		case "property", "name":
+			if property == "" {
+				property = strings.ToLower(strings.TrimSpace(string(value)))
+			}
+		case "content":
+			content = collapseSpaces(string(value))
+		}
+		if !more {
+			break
+		}
+	}
+	if content == "" {
+		return
+	}
+	switch property {
+	case "og:title":
+		if metadata.OGTitle == "" {
+			metadata.OGTitle = truncateRunes(content, maxDestinationTitleLength)
+		}
+	case "og:description":
+		if metadata.OGDescription == "" {
+			metadata.OGDescription = truncateRunes(content, maxDestinationTextLength)
+		}
+	case "og:image":
+		if metadata.OGImage == "" {
+			if image, err := base.Parse(content); err == nil {
+				content = image.String()
+			}
+			metadata.OGImage = truncateRunes(content, maxDestinationTextLength)
+		}
+	}
+}
+
+// collapseSpaces remplace les suites d'espaces (retours à la ligne compris) par un seul espace.
+func collapseSpaces(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// truncateRunes tronque s à max caractères (et non octets), sans couper un caractère UTF-8.
+func truncateRunes(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max])
+}
+
+// SetDestinationFetcher remplace le client de récupération des métadonnées des destinations (voir NewDestinationFetcher).
+func (s *LinkService) SetDestinationFetcher(fetcher *DestinationFetcher) {
+	s.destinationFetcher = fetcher
+}
+
+// SetFetchDestinationOnCreate active la récupération des métadonnées de la destination à la création des liens,
+// pour les créations qui ne précisent pas CreateLinkOptions.FetchDestination.
+func (s *LinkService) SetFetchDestinationOnCreate(enabled bool) {
+	s.fetchDestinationOnCreate = enabled
+}
+
+// shouldFetchDestination indique si les métadonnées de la destination d'un lien créé avec opts doivent être récupérées.
+func (s *LinkService) shouldFetchDestination(opts CreateLinkOptions) bool {
+	if opts.FetchDestination != nil {
+		return *opts.FetchDestination
+	}
+	return s.fetchDestinationOnCreate
+}
+
+// fetchDestinationAsync récupère en arrière-plan les métadonnées de la destination d'un lien qui vient d'être créé.
+// Le lien retourné à l'appelant n'est pas modifié : les métadonnées ne sont visibles qu'après relecture.
+func (s *LinkService) fetchDestinationAsync(linkID uint, longURL string) {
+	s.destinationFetches.Add(1)
+	go func() {
+		defer s.destinationFetches.Done()
+		metadata := s.destinationFetcher.Fetch(context.Background(), longURL)
+		if err := s.linkRepo.UpdateLinkDestination(linkID, metadata); err != nil {
+			log.Printf("[DESTINATION] ERREUR lors de l'enregistrement des métadonnées du lien %d : %v", linkID, err)
+		}
+	}()
+}
+
+// WaitDestinationFetches attend la fin des récupérations de métadonnées lancées en arrière-plan,
+// par exemple avant la sortie d'une commande CLI.
+func (s *LinkService) WaitDestinationFetches() {
+	s.destinationFetches.Wait()
+}
+
+// RefreshDestination récupère à nouveau, immédiatement, les métadonnées de la destination d'un lien
+// (son URL longue) et les enregistre. Retourne le lien à jour.
+func (s *LinkService) RefreshDestination(ctx context.Context, shortCode string) (*models.Link, error) {
+	link, err := s.linkRepo.GetLinkByShortCode(shortCode)
+	if err != nil {
+		return nil, err
+	}
+	link.Destination = s.destinationFetcher.Fetch(ctx, link.LongURL)
+	if err := s.linkRepo.UpdateLinkDestination(link.ID, link.Destination); err != nil {
+		return nil, fmt.Errorf("failed to update link destination: %w", err)
+	}
+	return link, nil
+}
+
+// DestinationInfo est la représentation JSON des métadonnées de la destination d'un lien.
+type DestinationInfo struct {
+	FinalURL      string    `json:"final_url"`
+	StatusCode    int       `json:"status_code"`
+	ContentType   string    `json:"content_type"`
+	Title         string    `json:"title"`
+	OGTitle       string    `json:"og_title"`
+	OGDescription string    `json:"og_description"`
+	OGImage       string    `json:"og_image"`
+	Error         string    `json:"error,omitempty"`
+	FetchedAt     time.Time `json:"fetched_at"`
+}
+
+// LinkDestinationInfo retourne les métadonnées de la destination d'un lien, ou nil si elles n'ont jamais été récupérées.
+func LinkDestinationInfo(metadata models.DestinationMetadata) *DestinationInfo {
+	if metadata.FetchedAt == nil {
+		return nil
+	}
+	return &DestinationInfo{
+		FinalURL:      metadata.FinalURL,
+		StatusCode:    metadata.StatusCode,
+		ContentType:   metadata.ContentType,
+		Title:         metadata.Title,
+		OGTitle:       metadata.OGTitle,
+		OGDescription: metadata.OGDescription,
+		OGImage:       metadata.OGImage,
+		Error:         metadata.Error,
+		FetchedAt:     *metadata.FetchedAt,
+	}
+}
@@ -0,0 +1,215 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// destinationSite simule un site de destination. Les tests utilisent son client, sans restriction d'adresse :
+// le serveur de test écoute sur la boucle locale, refusée par le client par défaut.
+func destinationSite(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/article", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(`<!DOCTYPE html>
+<html><head>
+  <title>
+    Un   article
+  </title>
+  <meta property="og:title" content="Titre OG">
+  <meta name="og:description" content="Description  OG">
+  <meta property="og:image" content="/images/cover.png">
+  <meta property="og:title" content="Second titre ignoré">
+</head><body><title>Titre hors du head</title></body></html>`))
+	})
+	mux.HandleFunc("/blog/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><head><meta property="og:image" content="cover.png"></head></html>`))
+	})
+	mux.HandleFunc("/ancienne-adresse", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/blog/billet", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/api", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"title": "<title>pas du HTML</title>"}`))
+	})
+	mux.HandleFunc("/absente", http.NotFound)
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestDestinationFetcherFetch(t *testing.T) {
+	site := destinationSite(t)
+	fetcher := NewDestinationFetcher(site.Client())
+
+	tests := []struct {
+		name string
+		path string
+
+		wantFinalPath   string
+		wantStatus      int
+		wantContentType string
+		wantTitle       string
+		wantOGTitle     string
+		wantOGDesc      string
+		wantOGImage     string // Chemin, préfixé par l'URL du site
+	}{
+		{
+			name: "titre et balises Open Graph", path: "/article",
+			wantFinalPath: "/article", wantStatus: http.StatusOK, wantContentType: "text/html",
+			wantTitle: "Un article", wantOGTitle: "Titre OG", wantOGDesc: "Description OG", wantOGImage: "/images/cover.png",
+		},
+		{
+			name: "redirection suivie, image relative à l'URL finale", path: "/ancienne-adresse",
+			wantFinalPath: "/blog/billet", wantStatus: http.StatusOK, wantContentType: "text/html",
+			wantOGImage: "/blog/cover.png",
+		},
+		{
+			name: "contenu non HTML", path: "/api",
+			wantFinalPath: "/api", wantStatus: http.StatusOK, wantContentType: "application/json",
+		},
+		{
+			name: "page absente", path: "/absente",
+			wantFinalPath: "/absente", wantStatus: http.StatusNotFound, wantContentType: "text/plain",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metadata := fetcher.Fetch(context.Background(), site.URL+tt.path)
+			if metadata.Error != "" {
+				t.Fatalf("erreur inattendue: %s", metadata.Error)
+			}
+			if metadata.FetchedAt == nil {
+				t.Error("date de récupération absente")
+			}
+			wantOGImage := ""
+			if tt.wantOGImage != "" {
+				wantOGImage = site.URL + tt.wantOGImage
+			}
+			checks := []struct{ field, got, want string }{
+				{"FinalURL", metadata.FinalURL, site.URL + tt.wantFinalPath},
+				{"ContentType", metadata.ContentType, tt.wantContentType},
+				{"Title", metadata.Title, tt.wantTitle},
+				{"OGTitle", metadata.OGTitle, tt.wantOGTitle},
+				{"OGDescription", metadata.OGDescription, tt.wantOGDesc},
+				{"OGImage", metadata.OGImage, wantOGImage},
+			}
+			for _, check := range checks {
+				if check.got != check.want {
+					t.Errorf("%s = %q, attendu %q", check.field, check.got, check.want)
+				}
+			}
+			if metadata.StatusCode != tt.wantStatus {
+				t.Errorf("StatusCode = %d, attendu %d", metadata.StatusCode, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestDestinationFetcherUnreachable(t *testing.T) {
+	site := httptest.NewServer(http.NotFoundHandler())
+	client := site.Client()
+	rawURL := site.URL
+	site.Close()
+
+	metadata := NewDestinationFetcher(client).Fetch(context.Background(), rawURL+"/page")
+	if metadata.Error == "" {
+		t.Error("une destination injoignable doit être décrite dans Error")
+	}
+	if metadata.StatusCode != 0 || metadata.FinalURL != "" {
+		t.Errorf("StatusCode = %d, FinalURL = %q, attendus vides", metadata.StatusCode, metadata.FinalURL)
+	}
+	if metadata.FetchedAt == nil {
+		t.Error("date de récupération absente")
+	}
+}
+
+func TestDefaultDestinationClientRefusesNonPublicAddresses(t *testing.T) {
+	var requests atomic.Int32
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+	}))
+	defer site.Close()
+
+	for _, rawURL := range []string{site.URL + "/admin", strings.Replace(site.URL, "127.0.0.1", "localhost", 1) + "/admin"} {
+		metadata := NewDestinationFetcher(nil).Fetch(context.Background(), rawURL)
+		if !strings.Contains(metadata.Error, errNonPublicDestination.Error()) {
+			t.Errorf("Fetch(%s): Error = %q, attendu un refus", rawURL, metadata.Error)
+		}
+	}
+	if n := requests.Load(); n != 0 {
+		t.Errorf("%d requête(s) reçue(s) par le serveur local", n)
+	}
+}
+
+func TestCheckDestinationRedirect(t *testing.T) {
+	tests := []struct {
+		url       string
+		redirects int
+		wantErr   bool
+	}{
+		{"http://93.184.216.34/page", 1, false},
+		{"https://[2606:4700:4700::1111]/", 1, false},
+		{"http://127.0.0.1:8080/admin", 1, true},
+		{"http://localhost/admin", 1, true},
+		{"http://169.254.169.254/latest/meta-data/", 1, true},
+		{"http://10.0.0.5/", 1, true},
+		{"http://[::1]/", 1, true},
+		{"file:///etc/passwd", 1, true},
+		{"http://93.184.216.34/page", maxDestinationRedirects, true},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+		err := checkDestinationRedirect(req, make([]*http.Request, tt.redirects))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("checkDestinationRedirect(%s, %d redirections) = %v, erreur attendue: %t", tt.url, tt.redirects, err, tt.wantErr)
+		}
+	}
+}
+
+func TestIsPublicAddr(t *testing.T) {
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{"93.184.216.34", true},
+		{"8.8.8.8", true},
+		{"2606:4700:4700::1111", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"fc00::1", false},
+		{"0.0.0.0", false},
+		{"0.1.2.3", false},
+		{"100.64.0.1", false},
+		{"224.0.0.1", false},
+		{"::ffff:127.0.0.1", false},
+		{"::ffff:169.254.169.254", false},
+	}
+	for _, tt := range tests {
+		if got := isPublicAddr(netip.MustParseAddr(tt.addr)); got != tt.want {
+			t.Errorf("isPublicAddr(%s) = %t, attendu %t", tt.addr, got, tt.want)
+		}
+	}
+}
+
+func TestCheckDialAddress(t *testing.T) {
+	if err := checkDialAddress("tcp4", "93.184.216.34:443", nil); err != nil {
+		t.Errorf("adresse publique refusée: %v", err)
+	}
+	if err := checkDialAddress("tcp4", "169.254.169.254:80", nil); !errors.Is(err, errNonPublicDestination) {
+		t.Errorf("adresse de métadonnées acceptée: %v", err)
+	}
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/axellelanca/urlshortener/internal/models"
+)
+
+// maxLanguageTagLength est la longueur maximale d'une étiquette de langue (taille de la colonne target_key).
+const maxLanguageTagLength = 50
+
+// maxAcceptLanguageRanges est le nombre maximum de langues d'un en-tête Accept-Language prises en compte.
+const maxAcceptLanguageRanges = 20
+
+// normalizeLanguageTag normalise une étiquette de langue BCP 47 (ex: "fr", "en-US", "pt_BR") : minuscules, '-' comme séparateur.
+func normalizeLanguageTag(tag string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(tag)), "_", "-")
+}
+
+// isLanguageTag indique si tag est une étiquette de langue normalisée : une langue de 2 à 8 lettres,
+// suivie éventuellement de sous-étiquettes de 1 à 8 lettres ou chiffres séparées par '-' (ex: "fr", "en-us", "zh-hant-tw").
+func isLanguageTag(tag string) bool {
+	if tag == "" || len(tag) > maxLanguageTagLength {
+		return false
+	}
+	for i, subtag := range strings.Split(tag, "-") {
+		if len(subtag) > 8 || (i == 0 && len(subtag) < 2) || subtag == "" {
+			return false
+		}
+		for _, r := range subtag {
+			isLetter := r >= 'a' && r <= 'z'
+			if !isLetter && (i == 0 || r < '0' || r > '9') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// languageRange est une langue acceptée par le visiteur, avec son poids (q-value) de l'en-tête Accept-Language.
+type languageRange struct {
+	tag     string
+	quality float64
+}
+
+// parseAcceptLanguage décode un en-tête Accept-Language (ex: "fr-CH, fr;q=0.9, en;q=0.8, *;q=0.5")
+// en langues triées par poids décroissant, l'ordre de l'en-tête départageant les poids égaux.
+// Les langues de poids 0 (refusées) sont conservées ; les entrées mal formées sont ignorées.
+func parseAcceptLanguage(header string) []languageRange {
+	ranges := make([]languageRange, 0, 4)
+	for _, part := range strings.Split(header, ",") {
+		if len(ranges) == maxAcceptLanguageRanges {
+			break
+		}
+		tag, params, _ := strings.Cut(part, ";")
+		tag = normalizeLanguageTag(tag)
+		if tag != "*" && !isLanguageTag(tag) {
+			continue
+		}
+		quality, ok := 1.0, true
+		for _, param := range strings.Split(params, ";") {
+			name, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if strings.EqualFold(strings.TrimSpace(name), "q") {
+				q, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+				quality, ok = q, err == nil && q >= 0 && q <= 1
+			}
+		}
+		if ok {
+			ranges = append(ranges, languageRange{tag: tag, quality: quality})
+		}
+	}
+	sort.SliceStable(ranges, func(i, j int) bool { return ranges[i].quality > ranges[j].quality })
+	return ranges
+}
+
+// matchLanguage choisit la destination par langue d'un lien selon l'en-tête Accept-Language du visiteur.
+// Les langues sont essayées par poids décroissant ; pour chacune, la destination de même étiquette est retenue,
+// sinon celle d'une étiquette plus générale ("fr" pour "fr-ch"), sinon la première d'une étiquette plus précise
+// ("en-us" pour "en"). Une langue refusée (q=0) n'est jamais retenue, et "*" (toute langue) laisse la destination
+// par défaut. Retourne nil si aucune destination ne convient.
+func matchLanguage(targets []models.LinkTarget, acceptLanguage string) *models.LinkTarget {
+	if acceptLanguage == "" {
+		return nil
+	}
+	ranges := parseAcceptLanguage(acceptLanguage)
+	refused := make(map[string]bool)
+	for _, r := range ranges {
+		if r.quality == 0 {
+			refused[r.tag] = true
+		}
+	}
+	accept := func(tag string) *models.LinkTarget {
+		if refused[tag] {
+			return nil
+		}
+		return findTarget(targets, models.TargetKindLanguage, tag)
+	}
+
+	for _, r := range ranges {
+		if r.quality == 0 || r.tag == "*" {
+			break
+		}
+		for tag := r.tag; tag != ""; tag = truncateLanguageTag(tag) {
+			if target := accept(tag); target != nil {
+				return target
+			}
+		}
+		// Les destinations sont triées par clé : la première étiquette plus précise est déterministe.
+		for i := range targets {
+			if targets[i].Kind == models.TargetKindLanguage && strings.HasPrefix(targets[i].Key, r.tag+"-") && !refused[targets[i].Key] {
+				return &targets[i]
+			}
+		}
+	}
+	return nil
+}
+
+// truncateLanguageTag retire la dernière sous-étiquette d'une étiquette de langue ("zh-hant-tw" -> "zh-hant"),
+// ainsi qu'une sous-étiquette d'un seul caractère qui la précéderait (extension). Retourne "" pour une langue seule.
+func truncateLanguageTag(tag string) string {
+	i := strings.LastIndex(tag, "-")
+	if i < 0 {
+		return ""
+	}
+	tag = tag[:i]
+	if j := strings.LastIndex(tag, "-"); j >= 0 && len(tag)-j == 2 {
+		tag = tag[:j]
+	}
+	return tag
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/axellelanca/urlshortener/internal/models"
+)
+
+func TestParseAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   []languageRange
+	}{
+		{"vide", "", []languageRange{}},
+		{
+			"poids explicites et implicites",
+			"fr-CH, fr;q=0.9, en;q=0.8, *;q=0.5",
+			[]languageRange{{"fr-ch", 1}, {"fr", 0.9}, {"en", 0.8}, {"*", 0.5}},
+		},
+		{"tri par poids décroissant", "en;q=0.5, de", []languageRange{{"de", 1}, {"en", 0.5}}},
+		{"ordre de l'en-tête à poids égal", "de, fr, it;q=1", []languageRange{{"de", 1}, {"fr", 1}, {"it", 1}}},
+		{"langue refusée conservée", "fr, de;q=0", []languageRange{{"fr", 1}, {"de", 0}}},
+		{"normalisation", " EN_us ;Q=0.7", []languageRange{{"en-us", 0.7}}},
+		{"entrées mal formées ignorées", "fr;q=2, de;q=abc, x, en-, it;q=-1, es", []languageRange{{"es", 1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseAcceptLanguage(tt.header); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseAcceptLanguage(%q) = %v, attendu %v", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchLanguage(t *testing.T) {
+	// Triées par clé, comme les destinations chargées depuis la base.
+	targets := []models.LinkTarget{
+		{Kind: models.TargetKindLanguage, Key: "de", URL: "https://site.com/de"},
+		{Kind: models.TargetKindLanguage, Key: "en-gb", URL: "https://site.com/en-gb"},
+		{Kind: models.TargetKindLanguage, Key: "en-us", URL: "https://site.com/en-us"},
+		{Kind: models.TargetKindLanguage, Key: "fr", URL: "https://site.com/fr"},
+		{Kind: models.TargetKindDevice, Key: "ios", URL: "https://apps.apple.com/app"},
+	}
+	tests := []struct {
+		name   string
+		header string
+		want   string // Clé de la destination retenue, "" si aucune
+	}{
+		{"sans en-tête", "", ""},
+		{"étiquette exacte", "de", "de"},
+		{"étiquette plus générale", "fr-CH", "fr"},
+		{"étiquette plus générale sur plusieurs niveaux", "fr-Latn-CH", "fr"},
+		{"première étiquette plus précise", "en", "en-gb"},
+		{"étiquette exacte prioritaire", "en-US", "en-us"},
+		{"langue du plus grand poids", "de;q=0.5, fr;q=0.9", "fr"},
+		{"langue suivante si aucune destination", "es, it;q=0.9, de;q=0.8", "de"},
+		{"langue refusée", "fr;q=0, de", "de"},
+		{"langue générale refusée", "fr-CH, fr;q=0", ""},
+		{"étiquette plus précise refusée", "en, en-gb;q=0", "en-us"},
+		{"toute langue", "*", ""},
+		{"toute langue avant une langue connue", "es, *;q=0.5, fr;q=0.1", ""},
+		{"aucune langue connue", "es, it", ""},
+		{"destination d'un autre critère ignorée", "ios", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ""
+			if target := matchLanguage(targets, tt.header); target != nil {
+				got = target.Key
+			}
+			if got != tt.want {
+				t.Errorf("matchLanguage(%q) = %q, attendu %q", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTruncateLanguageTag(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want string
+	}{
+		{"fr", ""},
+		{"fr-ch", "fr"},
+		{"zh-hant-tw", "zh-hant"},
+		{"en-a-bbb", "en"},
+		{"de-ch-1996", "de-ch"},
+	}
+	for _, tt := range tests {
+		if got := truncateLanguageTag(tt.tag); got != tt.want {
+			t.Errorf("truncateLanguageTag(%q) = %q, attendu %q", tt.tag, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package services
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/axellelanca/urlshortener/internal/models"
+	"github.com/axellelanca/urlshortener/internal/repository"
+)
+
+// Bornes de la taille d'une page de liens.
+const (
+	defaultListLimit = 20
+	maxListLimit     = 100
+)
+
+// ListLinksParams regroupe les paramètres de listage des liens, tels que reçus de l'API ou de la CLI.
+type ListLinksParams struct {
+	Domain        string     // Sous-chaîne recherchée dans le domaine de l'URL longue
+	CreatedAfter  *time.Time // Liens créés à partir de cette date
+	CreatedBefore *time.Time // Liens créés avant cette date
+	Status        string     // active, disabled, expired, exhausted ou deleted (vide = tous hors corbeille)
+	Tags          []string   // Tags que les liens doivent tous porter
+	Sort          string     // created_at (défaut) ou clicks
+	Order         string     // desc (défaut) ou asc
+	Limit         int        // Taille de la page (défaut 20, max 100)
+	Cursor        string     // Curseur opaque retourné par la page précédente
+}
+
+// LinkPage est une page de résultats de ListLinks.
+// NextCursor est vide lorsqu'il n'y a plus de résultats.
+type LinkPage struct {
+	Links      []repository.LinkWithClicks
+	NextCursor string
+}
+
+// validStatuses liste les statuts acceptés comme filtre.
+var validStatuses = map[string]bool{
+	models.LinkStatusActive:    true,
+	models.LinkStatusDisabled:  true,
+	models.LinkStatusExpired:   true,
+	models.LinkStatusScheduled: true,
+	models.LinkStatusExhausted: true,
+	models.LinkStatusDeleted:   true,
+}
+
+// ListLinks retourne une page de liens filtrée et triée, avec le curseur de la page suivante.
+func (s *LinkService) ListLinks(params ListLinksParams) (*LinkPage, error) {
+	filter := repository.LinkListFilter{
+		Domain:        strings.ToLower(params.Domain),
+		CreatedAfter:  params.CreatedAfter,
+		CreatedBefore: params.CreatedBefore,
+		Status:        params.Status,
+		SortBy:        repository.SortByCreatedAt,
+		Desc:          true,
+		Limit:         params.Limit,
+		Now:           time.Now(),
+	}
+
+	tags, err := NormalizeTags(params.Tags)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidListParams, err)
+	}
+	filter.Tags = tags
+
+	if params.Status != "" && !validStatuses[params.Status] {
+		return nil, fmt.Errorf("%w: statut '%s' inconnu", ErrInvalidListParams, params.Status)
+	}
+	switch params.Sort {
+	case "", repository.SortByCreatedAt:
+	case repository.SortByClicks:
+		filter.SortBy = repository.SortByClicks
+	default:
+		return nil, fmt.Errorf("%w: tri '%s' inconnu (created_at ou clicks)", ErrInvalidListParams, params.Sort)
+	}
+	switch params.Order {
+	case "", "desc":
+	case "asc":
+		filter.Desc = false
+	default:
+		return nil, fmt.Errorf("%w: ordre '%s' inconnu (asc ou desc)", ErrInvalidListParams, params.Order)
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultListLimit
+	}
+	if filter.Limit > maxListLimit {
+		filter.Limit = maxListLimit
+	}
+	if params.Cursor != "" {
+		cursor, err := decodeCursor(params.Cursor, filter.SortBy)
+		if err != nil {
+			return nil, err
+		}
+		filter.After = cursor
+	}
+
+	// Un élément de plus que demandé permet de savoir s'il existe une page suivante.
+	pageSize := filter.Limit
+	filter.Limit++
+	links, err := s.linkRepo.ListLinks(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &LinkPage{Links: links}
+	if len(links) > pageSize {
+		page.Links = links[:pageSize]
+		page.NextCursor = encodeCursor(page.Links[pageSize-1], filter.SortBy)
+	}
+	if err := s.loadTags(page.Links); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// encodeCursor construit le curseur opaque "<tri>|<valeur>|<id>" du dernier élément d'une page.
+func encodeCursor(link repository.LinkWithClicks, sortBy string) string {
+	value := link.CreatedAt.UnixNano()
+	if sortBy == repository.SortByClicks {
+		value = int64(link.TotalClicks)
+	}
+	raw := fmt.Sprintf("%s|%d|%d", sortBy, value, link.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeCursor décode un curseur et vérifie qu'il a été émis pour le même tri.
+func decodeCursor(cursor, sortBy string) (*repository.LinkCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: curseur illisible", ErrInvalidListParams)
+	}
+	parts := strings.Split(string(raw), "|")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: curseur illisible", ErrInvalidListParams)
+	}
+	if parts[0] != sortBy {
+		return nil, fmt.Errorf("%w: le curseur a été émis pour un autre tri", ErrInvalidListParams)
+	}
+	value, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: curseur illisible", ErrInvalidListParams)
+	}
+	id, err := strconv.ParseUint(parts[2], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: curseur illisible", ErrInvalidListParams)
+	}
+	return &repository.LinkCursor{Value: value, ID: uint(id)}, nil
+}
+
+// ParseDateFilter interprète une date de filtre au format RFC3339 ou YYYY-MM-DD (minuit, heure locale).
+// La date est retournée en heure locale, comme les dates de création stockées par GORM avec SQLite,
+// afin que les comparaisons en base restent cohérentes.
+func ParseDateFilter(value string) (*time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		t = t.Local()
+		return &t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return nil, fmt.Errorf("%w: date '%s' invalide (RFC3339 ou YYYY-MM-DD)", ErrInvalidListParams, value)
+	}
+	return &t, nil
+}
+
+// BackfillDomains renseigne le domaine des liens créés avant l'ajout de la colonne 'domain'.
+// Il retourne le nombre de liens mis à jour.
+func (s *LinkService) BackfillDomains() (int, error) {
+	links, err := s.linkRepo.GetLinksWithoutDomain()
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, link := range links {
+		domain := extractDomain(link.LongURL)
+		if domain == "" {
+			continue
+		}
+		if err := s.linkRepo.SetLinkDomain(link.ID, domain); err != nil {
+			return count, fmt.Errorf("failed to set domain for link '%s': %w", link.Shortcode, err)
+		}
+		count++
+	}
+	return count, nil
+}
+
+// BackfillCanonicalURLs (re)calcule l'URL canonique de tous les liens, corbeille incluse : pour les liens
+// créés avant l'ajout de la colonne 'canonical_url', et après un changement de links.strip_tracking_params.
+// Il retourne le nombre de liens mis à jour.
+func (s *LinkService) BackfillCanonicalURLs() (int, error) {
+	links, err := s.linkRepo.GetAllLinksWithTrash()
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, link := range links {
+		canonicalURL, err := s.urlPolicy.Canonicalize(link.LongURL)
+		if err != nil || canonicalURL == link.CanonicalURL {
+			continue
+		}
+		if err := s.linkRepo.SetLinkCanonicalURL(link.ID, canonicalURL); err != nil {
+			return count, fmt.Errorf("failed to set canonical URL for link '%s': %w", link.Shortcode, err)
+		}
+		count++
+	}
+	return count, nil
+}
+
+// extractDomain retourne l'hôte (sans port, en minuscules) d'une URL, ou une chaîne vide si elle est invalide.
+func extractDomain(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Hostname())
+}
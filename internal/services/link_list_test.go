@@ -0,0 +1,51 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/axellelanca/urlshortener/internal/repository"
+	"github.com/axellelanca/urlshortener/internal/testutil"
+)
+
+func TestListLinksStatusOutsideUTC(t *testing.T) {
+	setLocalZone(t, time.FixedZone("UTC+2", 2*60*60))
+	linkService := NewLinkService(repository.NewLinkRepository(testutil.NewDB(t)))
+
+	// Dates reçues en UTC, comme celles d'un JSON avec le suffixe Z : en texte, "…T10:00+00:00" précède
+	// "…T11:00+02:00" alors que la première date est la plus tardive.
+	inOneHour := time.Now().UTC().Add(time.Hour)
+	links := map[string]CreateLinkOptions{
+		"expire":  {Alias: "expire", ExpiresAt: &inOneHour},
+		"planned": {Alias: "planned", ActivatesAt: &inOneHour},
+	}
+	for alias, opts := range links {
+		if _, _, err := linkService.CreateLink("https://example.com/"+alias, opts); err != nil {
+			t.Fatalf("CreateLink(%s): %v", alias, err)
+		}
+	}
+
+	tests := []struct {
+		status string
+		want   []string
+	}{
+		{"active", []string{"expire"}},
+		{"expired", nil},
+		{"scheduled", []string{"planned"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			page, err := linkService.ListLinks(ListLinksParams{Status: tt.status})
+			if err != nil {
+				t.Fatalf("ListLinks: %v", err)
+			}
+			var got []string
+			for _, link := range page.Links {
+				got = append(got, link.Shortcode)
+			}
+			if len(got) != len(tt.want) || (len(got) > 0 && got[0] != tt.want[0]) {
+				t.Errorf("liens %s = %v, attendu %v", tt.status, got, tt.want)
+			}
+		})
+	}
+}
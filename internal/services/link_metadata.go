@@ -0,0 +1,172 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/axellelanca/urlshortener/internal/models"
+	"github.com/axellelanca/urlshortener/internal/repository"
+)
+
+// Bornes des métadonnées d'un lien.
+const (
+	maxTitleLength       = 255
+	maxDescriptionLength = 1000
+	maxNotesLength       = 5000
+	maxTagLength         = 50
+	maxTagsPerLink       = 20
+)
+
+// tagCharset liste les caractères autorisés dans un tag, en plus des lettres et chiffres minuscules.
+const tagCharset = "-_.:"
+
+// LinkMetadataUpdate décrit une modification partielle des métadonnées d'un lien :
+// seuls les champs non nil sont modifiés. Tags remplace l'ensemble des tags (une liste vide les retire tous).
+type LinkMetadataUpdate struct {
+	Title       *string
+	Description *string
+	Notes       *string
+	Tags        *[]string
+}
+
+// IsEmpty indique si la modification ne porte sur aucun champ.
+func (u LinkMetadataUpdate) IsEmpty() bool {
+	return u.Title == nil && u.Description == nil && u.Notes == nil && u.Tags == nil
+}
+
+// NormalizeTags met les tags en minuscules, retire les espaces et les doublons, puis les trie.
+// Chaque tag doit faire au plus maxTagLength caractères parmi les lettres, chiffres et tagCharset.
+func NormalizeTags(names []string) ([]string, error) {
+	seen := make(map[string]bool, len(names))
+	tags := make([]string, 0, len(names))
+	for _, name := range names {
+		tag := strings.ToLower(strings.TrimSpace(name))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if utf8.RuneCountInString(tag) > maxTagLength {
+			return nil, fmt.Errorf("%w: le tag '%s' dépasse %d caractères", ErrInvalidMetadata, tag, maxTagLength)
+		}
+		for _, r := range tag {
+			if !(r >= 'a' && r <= 'z') && !(r >= '0' && r <= '9') && !strings.ContainsRune(tagCharset, r) {
+				return nil, fmt.Errorf("%w: caractère '%c' non autorisé dans le tag '%s' (lettres, chiffres et '%s' uniquement)",
+					ErrInvalidMetadata, r, tag, tagCharset)
+			}
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+	if len(tags) > maxTagsPerLink {
+		return nil, fmt.Errorf("%w: %d tags au maximum par lien", ErrInvalidMetadata, maxTagsPerLink)
+	}
+	sort.Strings(tags)
+	return tags, nil
+}
+
+// validateMetadata vérifie la longueur du titre, de la description et des notes.
+func validateMetadata(title, description, notes string) error {
+	if utf8.RuneCountInString(title) > maxTitleLength {
+		return fmt.Errorf("%w: le titre dépasse %d caractères", ErrInvalidMetadata, maxTitleLength)
+	}
+	if utf8.RuneCountInString(description) > maxDescriptionLength {
+		return fmt.Errorf("%w: la description dépasse %d caractères", ErrInvalidMetadata, maxDescriptionLength)
+	}
+	if utf8.RuneCountInString(notes) > maxNotesLength {
+		return fmt.Errorf("%w: les notes dépassent %d caractères", ErrInvalidMetadata, maxNotesLength)
+	}
+	return nil
+}
+
+// resolveTags normalise les noms de tags et retourne les tags correspondants, créés au besoin.
+func (s *LinkService) resolveTags(names []string) ([]models.Tag, error) {
+	names, err := NormalizeTags(names)
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return nil, nil
+	}
+	tags, err := s.linkRepo.FindOrCreateTags(names)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve tags: %w", err)
+	}
+	return tags, nil
+}
+
+// UpdateLinkMetadata modifie le titre, la description, les notes et/ou les tags d'un lien.
+func (s *LinkService) UpdateLinkMetadata(shortCode string, update LinkMetadataUpdate) (*models.Link, error) {
+	link, err := s.GetLinkInfo(shortCode)
+	if err != nil {
+		return nil, err
+	}
+	if update.Title != nil {
+		link.Title = strings.TrimSpace(*update.Title)
+	}
+	if update.Description != nil {
+		link.Description = strings.TrimSpace(*update.Description)
+	}
+	if update.Notes != nil {
+		link.Notes = strings.TrimSpace(*update.Notes)
+	}
+	if err := validateMetadata(link.Title, link.Description, link.Notes); err != nil {
+		return nil, err
+	}
+	if update.Tags != nil {
+		if link.Tags, err = s.resolveTags(*update.Tags); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.linkRepo.UpdateLinkMetadata(link, update.Tags != nil); err != nil {
+		return nil, fmt.Errorf("failed to update link metadata: %w", err)
+	}
+	return link, nil
+}
+
+// GetLinkInfo récupère un lien via son code court, avec ses tags, ses destinations alternatives
+// et ses changements de destination programmés (ceux déjà échus sont appliqués).
+func (s *LinkService) GetLinkInfo(shortCode string) (*models.Link, error) {
+	link, err := s.linkRepo.GetLinkByShortCode(shortCode)
+	if err != nil {
+		return nil, err
+	}
+	tagsByLink, err := s.linkRepo.GetTagsByLinkIDs([]uint{link.ID})
+	if err != nil {
+		return nil, err
+	}
+	link.Tags = tagsByLink[link.ID]
+	if err := s.applyDueSchedule(link, time.Now()); err != nil {
+		return nil, err
+	}
+	if err := s.loadTargets(link); err != nil {
+		return nil, err
+	}
+	return link, nil
+}
+
+// loadTags renseigne les tags d'une liste de liens en une seule requête.
+func (s *LinkService) loadTags(links []repository.LinkWithClicks) error {
+	ids := make([]uint, len(links))
+	for i := range links {
+		ids[i] = links[i].ID
+	}
+	tagsByLink, err := s.linkRepo.GetTagsByLinkIDs(ids)
+	if err != nil {
+		return err
+	}
+	for i := range links {
+		links[i].Tags = tagsByLink[links[i].ID]
+	}
+	return nil
+}
+
+// TagNames retourne les noms des tags d'un lien.
+func TagNames(tags []models.Tag) []string {
+	names := make([]string, len(tags))
+	for i, tag := range tags {
+		names[i] = tag.Name
+	}
+	return names
+}
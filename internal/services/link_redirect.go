@@ -0,0 +1,295 @@
+package services
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/axellelanca/urlshortener/internal/models"
+)
+
+// redirectStatuses liste les codes HTTP de redirection acceptés, par lien ou dans la configuration.
+var redirectStatuses = map[int]bool{
+	http.StatusMovedPermanently:  true, // 301
+	http.StatusFound:             true, // 302
+	http.StatusTemporaryRedirect: true, // 307
+	http.StatusPermanentRedirect: true, // 308
+}
+
+// Valeurs par défaut de la politique de redirection (section redirect de la configuration).
+// La mise en cache des redirections permanentes est désactivée par défaut : un lien peut toujours être modifié,
+// désactivé ou supprimé, et un navigateur qui a mis sa redirection en cache ne le saurait pas.
+const (
+	defaultRedirectStatus      = http.StatusFound
+	defaultRedirectCacheMaxAge = 0
+)
+
+// ValidateRedirectStatus vérifie qu'un code de redirection fait partie de 301, 302, 307 et 308.
+func ValidateRedirectStatus(status int) error {
+	if !redirectStatuses[status] {
+		return fmt.Errorf("%w: %d (301, 302, 307 ou 308)", ErrInvalidRedirectStatus, status)
+	}
+	return nil
+}
+
+// isPermanentRedirect indique si le code de redirection est permanent (301 ou 308).
+func isPermanentRedirect(status int) bool {
+	return status == http.StatusMovedPermanently || status == http.StatusPermanentRedirect
+}
+
+// RedirectPolicy décrit la politique de redirection par défaut : le code HTTP utilisé pour les liens
+// qui n'en définissent pas, et la durée de mise en cache des redirections permanentes (0 = jamais mises en cache).
+type RedirectPolicy struct {
+	Status      int
+	CacheMaxAge time.Duration
+}
+
+// NewRedirectPolicy construit la politique de redirection à partir de la configuration.
+// Un code nul vaut 302 ; une durée de cache négative est refusée.
+func NewRedirectPolicy(status, cacheMaxAgeSeconds int) (RedirectPolicy, error) {
+	if status == 0 {
+		status = defaultRedirectStatus
+	}
+	if err := ValidateRedirectStatus(status); err != nil {
+		return RedirectPolicy{}, err
+	}
+	if cacheMaxAgeSeconds < 0 {
+		return RedirectPolicy{}, fmt.Errorf("%w: cache_max_age doit être positif", ErrInvalidRedirectStatus)
+	}
+	return RedirectPolicy{Status: status, CacheMaxAge: time.Duration(cacheMaxAgeSeconds) * time.Second}, nil
+}
+
+// SetRedirectPolicy remplace la politique de redirection par défaut (voir NewRedirectPolicy).
+func (s *LinkService) SetRedirectPolicy(policy RedirectPolicy) {
+	s.redirectPolicy = policy
+}
+
+// RedirectStatus retourne le code HTTP de redirection du lien : le sien s'il en définit un, sinon celui de la configuration.
+func (s *LinkService) RedirectStatus(link *models.Link) int {
+	if link.RedirectStatus != 0 {
+		return link.RedirectStatus
+	}
+	return s.redirectPolicy.Status
+}
+
+// RedirectCacheControl retourne l'en-tête Cache-Control d'une redirection du lien avec le code status.
+// La mise en cache est optionnelle (CacheMaxAge positif), et seule une redirection permanente d'un lien sans
+// expiration, limite de clics, mot de passe, destination alternative ni changement de destination programmé peut
+// être mise en cache : pour les autres, le navigateur doit repasser par le serveur à chaque clic (comptage des clics,
+// contrôle d'accès, changement de destination, ciblage, négociation de la langue ou tirage de la variante A/B).
+// L'en-tête est toujours envoyé : sans lui, les navigateurs mettent les redirections permanentes en cache d'eux-mêmes.
+func (s *LinkService) RedirectCacheControl(link *models.Link, status int) string {
+	cacheable := isPermanentRedirect(status) && s.redirectPolicy.CacheMaxAge > 0 &&
+		link.ExpiresAt == nil && !link.HasClickLimit() && !link.IsProtected() &&
+		len(link.Targets) == 0 && len(link.Schedule) == 0
+	if !cacheable {
+		return "no-store"
+	}
+	return fmt.Sprintf("public, max-age=%d", int(s.redirectPolicy.CacheMaxAge.Seconds()))
+}
+
+// RedirectRequest décrit la requête de redirection : la partie qui peut être transmise à la destination
+// (chemin demandé après le code court et query string brute) et les informations utilisées pour le ciblage.
+type RedirectRequest struct {
+	// Path est le suffixe de chemin après le code court (ex: "/docs/install"), vide s'il n'y en a pas.
+	Path string
+	// RawQuery est la query string de la requête, sans le '?'.
+	RawQuery string
+	// UserAgent est le User-Agent du visiteur, qui détermine son type d'appareil.
+	UserAgent string
+	// ClientIP est l'adresse IP du visiteur, qui détermine son pays si une base GeoIP est configurée.
+	ClientIP string
+	// AcceptLanguage est l'en-tête Accept-Language du visiteur, qui choisit la destination par langue.
+	AcceptLanguage string
+	// Source est la source du clic (voir ClickSource), enregistrée avec lui : "qr" pour le scan d'un QR code.
+	Source string
+	// Variant est la variante A/B déjà assignée au visiteur (cookie), prise en compte si le lien a StickyVariant.
+	Variant string
+}
+
+// Destination est le résultat de la résolution d'une redirection : l'URL cible
+// et les caractéristiques du visiteur qui l'ont choisie, enregistrées avec le clic.
+type Destination struct {
+	URL      string
+	Device   string // Type d'appareil détecté (ios, android, desktop)
+	Country  string // Pays du visiteur (code ISO 3166-1 alpha-2), vide s'il est inconnu
+	Variant  string // Variante A/B tirée, vide si la destination n'est pas celle d'une variante
+	Language string // Langue négociée, vide si la destination n'est pas celle d'une langue
+	Source   string // Source du clic ("qr" pour le scan d'un QR code), vide pour un clic direct
+}
+
+// LinkRedirectUpdate décrit une modification partielle des options de redirection d'un lien :
+// seuls les champs non nil sont modifiés. RedirectStatus à 0 rétablit le code de la configuration.
+type LinkRedirectUpdate struct {
+	ForwardQuery   *bool
+	ForwardPath    *bool
+	Preview        *bool
+	StickyVariant  *bool
+	RedirectStatus *int
+}
+
+// IsEmpty indique si la modification ne porte sur aucun champ.
+func (u LinkRedirectUpdate) IsEmpty() bool {
+	return u.ForwardQuery == nil && u.ForwardPath == nil && u.Preview == nil && u.StickyVariant == nil && u.RedirectStatus == nil
+}
+
+// UpdateLinkRedirect modifie les options de redirection d'un lien (transmission de la query string
+// et du chemin, page d'aperçu, variante A/B persistante, code HTTP de redirection).
+func (s *LinkService) UpdateLinkRedirect(shortCode string, update LinkRedirectUpdate) (*models.Link, error) {
+	link, err := s.linkRepo.GetLinkByShortCode(shortCode)
+	if err != nil {
+		return nil, err
+	}
+	if update.ForwardQuery != nil {
+		link.ForwardQuery = *update.ForwardQuery
+	}
+	if update.ForwardPath != nil {
+		link.ForwardPath = *update.ForwardPath
+	}
+	if update.Preview != nil {
+		link.Preview = *update.Preview
+	}
+	if update.StickyVariant != nil {
+		link.StickyVariant = *update.StickyVariant
+	}
+	if update.RedirectStatus != nil {
+		if *update.RedirectStatus != 0 {
+			if err := ValidateRedirectStatus(*update.RedirectStatus); err != nil {
+				return nil, err
+			}
+		}
+		link.RedirectStatus = *update.RedirectStatus
+	}
+	if err := s.linkRepo.UpdateLinkRedirect(link); err != nil {
+		return nil, fmt.Errorf("failed to update link redirect options: %w", err)
+	}
+	return link, nil
+}
+
+// ResolveDestination calcule l'URL vers laquelle rediriger une requête sur le lien.
+// Les changements de destination programmés échus sont d'abord appliqués à l'URL longue (voir applyDueSchedule).
+// La destination de base est celle du type d'appareil du visiteur si le lien en définit une, sinon celle de
+// son pays, sinon celle de sa langue (voir matchLanguage), sinon celle d'une variante A/B tirée au sort
+// (voir pickVariant), sinon l'URL longue : le ciblage par appareil est prioritaire sur le ciblage par pays,
+// lui-même prioritaire sur la langue, elle-même prioritaire sur le test A/B.
+// Si le lien transmet le chemin (ForwardPath), le suffixe de req.Path est ajouté au chemin de l'URL longue ;
+// sinon, une requête avec un suffixe renvoie ErrPathForwardingDisabled.
+// Si le lien transmet la query string (ForwardQuery), les paramètres de la requête sont ajoutés à ceux de
+// la destination, qui restent prioritaires : un paramètre déjà présent dans la destination n'est pas remplacé.
+// Le marqueur src=qr des QR codes n'est jamais transmis.
+func (s *LinkService) ResolveDestination(link *models.Link, req RedirectRequest) (Destination, error) {
+	suffix := cleanPathSuffix(req.Path)
+	if suffix != "" && !link.ForwardPath {
+		return Destination{}, fmt.Errorf("%w: '%s'", ErrPathForwardingDisabled, req.Path)
+	}
+	if err := s.applyDueSchedule(link, time.Now()); err != nil {
+		return Destination{}, err
+	}
+	if err := s.loadTargets(link); err != nil {
+		return Destination{}, err
+	}
+
+	result := Destination{
+		URL:     link.LongURL,
+		Device:  DetectDevice(req.UserAgent),
+		Country: s.lookupCountry(req.ClientIP),
+		Source:  req.Source,
+	}
+	if target := findTarget(link.Targets, models.TargetKindDevice, result.Device); target != nil {
+		result.URL = target.URL
+	} else if target := findTarget(link.Targets, models.TargetKindCountry, result.Country); target != nil {
+		result.URL = target.URL
+	} else if target := matchLanguage(link.Targets, req.AcceptLanguage); target != nil {
+		result.URL = target.URL
+		result.Language = target.Key
+	} else {
+		assigned := ""
+		if link.StickyVariant {
+			assigned = req.Variant
+		}
+		if target := pickVariant(link.Targets, assigned); target != nil {
+			result.URL = target.URL
+			result.Variant = target.Key
+		}
+	}
+
+	rawQuery := withoutClickSource(req.RawQuery)
+	forwardQuery := link.ForwardQuery && rawQuery != ""
+	if suffix == "" && !forwardQuery {
+		return result, nil
+	}
+	destination, err := url.Parse(result.URL)
+	if err != nil {
+		return Destination{}, fmt.Errorf("%w: %v", ErrInvalidLongURL, err)
+	}
+	if suffix != "" {
+		destination.Path = strings.TrimSuffix(destination.Path, "/") + suffix
+		destination.RawPath = ""
+	}
+	if forwardQuery {
+		destination.RawQuery = mergeRawQuery(destination.RawQuery, rawQuery)
+	}
+	result.URL = destination.String()
+	return result, nil
+}
+
+// cleanPathSuffix normalise le suffixe de chemin d'une requête de redirection.
+// Les segments "." et ".." sont résolus sans pouvoir remonter au-dessus du chemin de l'URL longue,
+// et le slash final éventuel est conservé. Un suffixe vide ou réduit à "/" donne "".
+func cleanPathSuffix(suffix string) string {
+	if suffix == "" {
+		return ""
+	}
+	cleaned := path.Clean("/" + suffix)
+	if cleaned == "/" {
+		return ""
+	}
+	if strings.HasSuffix(suffix, "/") {
+		cleaned += "/"
+	}
+	return cleaned
+}
+
+// mergeRawQuery ajoute à la query string base les paramètres de extra dont le nom n'y figure pas déjà.
+// L'encodage et l'ordre des paramètres sont conservés ; les paramètres mal encodés de extra sont ignorés.
+func mergeRawQuery(base, extra string) string {
+	existing := make(map[string]bool)
+	for _, part := range strings.Split(base, "&") {
+		if name, ok := queryParamName(part); ok {
+			existing[name] = true
+		}
+	}
+
+	parts := make([]string, 0, 4)
+	if base != "" {
+		parts = append(parts, base)
+	}
+	for _, part := range strings.Split(extra, "&") {
+		name, ok := queryParamName(part)
+		if !ok || existing[name] {
+			continue
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, "&")
+}
+
+// queryParamName retourne le nom décodé d'un paramètre "nom=valeur" d'une query string brute.
+// ok vaut false pour une partie vide ou mal encodée.
+func queryParamName(part string) (string, bool) {
+	if part == "" {
+		return "", false
+	}
+	name, value, _ := strings.Cut(part, "=")
+	name, err := url.QueryUnescape(name)
+	if err != nil || name == "" {
+		return "", false
+	}
+	if _, err := url.QueryUnescape(value); err != nil {
+		return "", false
+	}
+	return name, true
+}
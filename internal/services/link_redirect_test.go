@@ -0,0 +1,129 @@
+package services
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/axellelanca/urlshortener/internal/models"
+)
+
+func TestRedirectCacheControl(t *testing.T) {
+	inOneHour := time.Now().Add(time.Hour)
+	tests := []struct {
+		name        string
+		cacheMaxAge time.Duration
+		link        models.Link
+		status      int
+		want        string
+	}{
+		{"cache désactivé par défaut", defaultRedirectCacheMaxAge, models.Link{}, http.StatusMovedPermanently, "no-store"},
+		{"cache activé, 301", time.Hour, models.Link{}, http.StatusMovedPermanently, "public, max-age=3600"},
+		{"cache activé, 308", time.Hour, models.Link{}, http.StatusPermanentRedirect, "public, max-age=3600"},
+		{"redirection temporaire", time.Hour, models.Link{}, http.StatusFound, "no-store"},
+		{"lien expirable", time.Hour, models.Link{ExpiresAt: &inOneHour}, http.StatusMovedPermanently, "no-store"},
+		{"lien limité", time.Hour, models.Link{MaxClicks: 10}, http.StatusMovedPermanently, "no-store"},
+		{"lien protégé", time.Hour, models.Link{PasswordHash: "hash"}, http.StatusMovedPermanently, "no-store"},
+		{"lien ciblé", time.Hour, models.Link{Targets: []models.LinkTarget{{Kind: "device"}}}, http.StatusMovedPermanently, "no-store"},
+		{"lien programmé", time.Hour, models.Link{Schedule: []models.LinkSchedule{{URL: "https://example.com"}}}, http.StatusMovedPermanently, "no-store"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			linkService := NewLinkService(nil)
+			linkService.SetRedirectPolicy(RedirectPolicy{Status: defaultRedirectStatus, CacheMaxAge: tt.cacheMaxAge})
+			if got := linkService.RedirectCacheControl(&tt.link, tt.status); got != tt.want {
+				t.Errorf("RedirectCacheControl = %q, attendu %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCleanPathSuffix(t *testing.T) {
+	tests := []struct {
+		suffix string
+		want   string
+	}{
+		{"", ""},
+		{"/", ""},
+		{"/docs/install", "/docs/install"},
+		{"/docs/", "/docs/"},
+		{"//docs//install", "/docs/install"},
+		{"/docs/./install", "/docs/install"},
+		{"/docs/../install", "/install"},
+		{"/../../etc/passwd", "/etc/passwd"},
+		{"/..", ""},
+	}
+	for _, tt := range tests {
+		if got := cleanPathSuffix(tt.suffix); got != tt.want {
+			t.Errorf("cleanPathSuffix(%q) = %q, attendu %q", tt.suffix, got, tt.want)
+		}
+	}
+}
+
+func TestMergeRawQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		base  string
+		extra string
+		want  string
+	}{
+		{"base vide", "", "a=1&b=2", "a=1&b=2"},
+		{"ajout après la base", "ref=site", "a=1", "ref=site&a=1"},
+		{"la destination reste prioritaire", "a=1&b=2", "b=3&c=4", "a=1&b=2&c=4"},
+		{"noms comparés décodés", "utm%5Fsource=site", "utm_source=mail", "utm%5Fsource=site"},
+		{"encodage et ordre conservés", "z=%2F", "y=a%20b&x=1", "z=%2F&y=a%20b&x=1"},
+		{"paramètres vides ou mal encodés ignorés", "a=1", "&b=%zz&=2&c=3&", "a=1&c=3"},
+		{"paramètre sans valeur", "", "debug", "debug"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mergeRawQuery(tt.base, tt.extra); got != tt.want {
+				t.Errorf("mergeRawQuery(%q, %q) = %q, attendu %q", tt.base, tt.extra, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveDestinationForwarding(t *testing.T) {
+	tests := []struct {
+		name         string
+		longURL      string
+		forwardQuery bool
+		forwardPath  bool
+		req          RedirectRequest
+		want         string
+		wantErr      error
+	}{
+		{"sans transmission", "https://example.com/docs?v=2", false, false,
+			RedirectRequest{RawQuery: "a=1"}, "https://example.com/docs?v=2", nil},
+		{"query transmise", "https://example.com/docs?v=2", true, false,
+			RedirectRequest{RawQuery: "v=3&a=1"}, "https://example.com/docs?v=2&a=1", nil},
+		{"marqueur QR jamais transmis", "https://example.com/", true, false,
+			RedirectRequest{RawQuery: "src=qr&a=1", Source: ClickSourceQR}, "https://example.com/?a=1", nil},
+		{"seul le marqueur QR", "https://example.com/", true, false,
+			RedirectRequest{RawQuery: "src=qr", Source: ClickSourceQR}, "https://example.com/", nil},
+		{"chemin transmis", "https://example.com/docs/", false, true,
+			RedirectRequest{Path: "/install/linux"}, "https://example.com/docs/install/linux", nil},
+		{"chemin et query transmis", "https://example.com/docs?v=2#intro", true, true,
+			RedirectRequest{Path: "/../install", RawQuery: "a=1"}, "https://example.com/docs/install?v=2&a=1#intro", nil},
+		{"chemin refusé", "https://example.com/docs", true, false,
+			RedirectRequest{Path: "/install"}, "", ErrPathForwardingDisabled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Destinations et programmation déjà chargées (vides) : aucun accès à la base.
+			link := &models.Link{
+				LongURL: tt.longURL, ForwardQuery: tt.forwardQuery, ForwardPath: tt.forwardPath,
+				Targets: []models.LinkTarget{}, Schedule: []models.LinkSchedule{},
+			}
+			got, err := NewLinkService(nil).ResolveDestination(link, tt.req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ResolveDestination = %v, attendu %v", err, tt.wantErr)
+			}
+			if got.URL != tt.want {
+				t.Errorf("ResolveDestination = %q, attendu %q", got.URL, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,217 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/axellelanca/urlshortener/internal/models"
+)
+
+// scheduleActor est l'auteur des révisions créées par l'application d'un changement de destination programmé.
+const scheduleActor = "schedule"
+
+// ScheduledURL est un changement de destination programmé : à partir de At, le lien redirige vers URL.
+type ScheduledURL struct {
+	At  time.Time `json:"at"`
+	URL string    `json:"url"`
+}
+
+// normalizeSchedule valide les changements de destination programmés d'un lien et retourne les LinkSchedule
+// correspondants, du plus proche au plus lointain. Chaque date doit être dans le futur et unique.
+func normalizeSchedule(entries []ScheduledURL, now time.Time) ([]models.LinkSchedule, error) {
+	schedule := make([]models.LinkSchedule, 0, len(entries))
+	seen := make(map[int64]bool, len(entries))
+	for _, entry := range entries {
+		if !entry.At.After(now) {
+			return nil, fmt.Errorf("%w: le changement de destination du %s doit être dans le futur", ErrInvalidSchedule, entry.At.Format(time.RFC3339))
+		}
+		if seen[entry.At.UnixNano()] {
+			return nil, fmt.Errorf("%w: plusieurs changements de destination le %s", ErrInvalidSchedule, entry.At.Format(time.RFC3339))
+		}
+		targetURL := strings.TrimSpace(entry.URL)
+		if err := validateLongURL(targetURL); err != nil {
+			return nil, fmt.Errorf("%w: destination du %s: %v", ErrInvalidSchedule, entry.At.Format(time.RFC3339), err)
+		}
+		seen[entry.At.UnixNano()] = true
+		schedule = append(schedule, models.LinkSchedule{SwitchAt: entry.At.Local(), URL: targetURL})
+	}
+	sort.Slice(schedule, func(i, j int) bool { return schedule[i].SwitchAt.Before(schedule[j].SwitchAt) })
+	return schedule, nil
+}
+
+// validateActivation vérifie la cohérence de la date d'activation d'un lien, de son URL d'attente
+// et de sa date d'expiration. Une date d'activation nouvellement définie doit être dans le futur.
+func validateActivation(activatesAt, expiresAt *time.Time, pendingURL string, now time.Time) error {
+	if activatesAt != nil && !activatesAt.After(now) {
+		return fmt.Errorf("%w: la date d'activation doit être dans le futur", ErrInvalidSchedule)
+	}
+	if activatesAt != nil && expiresAt != nil && !activatesAt.Before(*expiresAt) {
+		return fmt.Errorf("%w: la date d'activation doit précéder la date d'expiration", ErrInvalidSchedule)
+	}
+	if pendingURL != "" {
+		if err := validateLongURL(pendingURL); err != nil {
+			return fmt.Errorf("%w: pending_url: %v", ErrInvalidSchedule, err)
+		}
+	}
+	return nil
+}
+
+// SetPlaceholderURL définit l'URL d'attente par défaut (schedule.placeholder_url), vers laquelle sont redirigés
+// les visiteurs d'un lien pas encore activé qui n'en définit pas. Vide = le lien renvoie une erreur jusqu'à son activation.
+func (s *LinkService) SetPlaceholderURL(placeholderURL string) error {
+	if placeholderURL != "" {
+		if err := validateLongURL(placeholderURL); err != nil {
+			return fmt.Errorf("%w: placeholder_url: %v", ErrInvalidSchedule, err)
+		}
+	}
+	s.placeholderURL = placeholderURL
+	return nil
+}
+
+// PendingURL retourne l'URL vers laquelle rediriger les visiteurs d'un lien pas encore activé :
+// la sienne, sinon celle de la configuration, sinon "".
+func (s *LinkService) PendingURL(link *models.Link) string {
+	if link.PendingURL != "" {
+		return link.PendingURL
+	}
+	return s.placeholderURL
+}
+
+// LinkScheduleUpdate décrit une modification partielle de la programmation d'un lien : seuls les champs non nil sont modifiés.
+// Une date d'activation à la valeur zéro retire la date (le lien est actif immédiatement) ;
+// un Schedule vide retire tous les changements de destination programmés.
+type LinkScheduleUpdate struct {
+	ActivatesAt *time.Time
+	PendingURL  *string
+	Schedule    *[]ScheduledURL
+}
+
+// IsEmpty indique si la modification ne porte sur aucun champ.
+func (u LinkScheduleUpdate) IsEmpty() bool {
+	return u.ActivatesAt == nil && u.PendingURL == nil && u.Schedule == nil
+}
+
+// resolve calcule la date d'activation, l'URL d'attente et les changements programmés d'un lien après la modification.
+// Seule une date d'activation modifiée doit être dans le futur : changer l'URL d'attente d'un lien déjà activé reste possible.
+func (u LinkScheduleUpdate) resolve(link *models.Link, now time.Time) (*time.Time, string, []models.LinkSchedule, error) {
+	activatesAt, pendingURL := link.ActivatesAt, link.PendingURL
+	var newActivation *time.Time
+	if u.ActivatesAt != nil {
+		activatesAt = nil
+		if !u.ActivatesAt.IsZero() {
+			activatesAt = localTime(u.ActivatesAt)
+			newActivation = activatesAt
+		}
+	}
+	if u.PendingURL != nil {
+		pendingURL = strings.TrimSpace(*u.PendingURL)
+	}
+	if err := validateActivation(newActivation, link.ExpiresAt, pendingURL, now); err != nil {
+		return nil, "", nil, err
+	}
+	var schedule []models.LinkSchedule
+	if u.Schedule != nil {
+		var err error
+		if schedule, err = normalizeSchedule(*u.Schedule, now); err != nil {
+			return nil, "", nil, err
+		}
+	}
+	return activatesAt, pendingURL, schedule, nil
+}
+
+// ValidateScheduleUpdate vérifie la modification de la programmation d'un lien sans l'enregistrer.
+func ValidateScheduleUpdate(link *models.Link, update LinkScheduleUpdate) error {
+	_, _, _, err := update.resolve(link, time.Now())
+	return err
+}
+
+// UpdateLinkSchedule modifie la date d'activation, l'URL d'attente et/ou les changements de destination programmés d'un lien.
+func (s *LinkService) UpdateLinkSchedule(shortCode string, update LinkScheduleUpdate) (*models.Link, error) {
+	link, err := s.linkRepo.GetLinkByShortCode(shortCode)
+	if err != nil {
+		return nil, err
+	}
+	activatesAt, pendingURL, schedule, err := update.resolve(link, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if update.ActivatesAt != nil || update.PendingURL != nil {
+		link.ActivatesAt, link.PendingURL = activatesAt, pendingURL
+		if err := s.linkRepo.UpdateLinkActivation(link); err != nil {
+			return nil, fmt.Errorf("failed to update link activation: %w", err)
+		}
+	}
+	if update.Schedule != nil {
+		if err := s.linkRepo.ReplaceLinkSchedule(link.ID, schedule); err != nil {
+			return nil, fmt.Errorf("failed to update link schedule: %w", err)
+		}
+	}
+	if link.Schedule, err = s.linkRepo.GetLinkSchedule(link.ID); err != nil {
+		return nil, err
+	}
+	return link, nil
+}
+
+// LinkScheduleEntries retourne les changements de destination programmés d'un lien, du plus proche au plus lointain.
+func LinkScheduleEntries(schedule []models.LinkSchedule) []ScheduledURL {
+	entries := make([]ScheduledURL, 0, len(schedule))
+	for _, entry := range schedule {
+		entries = append(entries, ScheduledURL{At: entry.SwitchAt, URL: entry.URL})
+	}
+	return entries
+}
+
+// loadSchedule renseigne les changements de destination programmés d'un lien s'ils ne l'ont pas encore été.
+func (s *LinkService) loadSchedule(link *models.Link) error {
+	if link.Schedule != nil {
+		return nil
+	}
+	schedule, err := s.linkRepo.GetLinkSchedule(link.ID)
+	if err != nil {
+		return fmt.Errorf("failed to load link schedule: %w", err)
+	}
+	link.Schedule = append(make([]models.LinkSchedule, 0, len(schedule)), schedule...)
+	return nil
+}
+
+// applyDueSchedule applique les changements de destination programmés du lien échus à l'instant now :
+// l'URL longue devient celle du dernier changement échu, avec une révision d'auteur "schedule".
+// Le changement n'est pas soumis à la politique de doublons : une redirection ne peut pas le refuser.
+func (s *LinkService) applyDueSchedule(link *models.Link, now time.Time) error {
+	if err := s.loadSchedule(link); err != nil {
+		return err
+	}
+	due := 0
+	for due < len(link.Schedule) && !link.Schedule[due].SwitchAt.After(now) {
+		due++
+	}
+	if due == 0 {
+		return nil
+	}
+
+	newURL := link.Schedule[due-1].URL
+	canonicalURL, err := s.urlPolicy.Canonicalize(newURL)
+	if err != nil {
+		return err
+	}
+	revision := &models.LinkRevision{
+		LinkID:    link.ID,
+		OldURL:    link.LongURL,
+		NewURL:    newURL,
+		Actor:     scheduleActor,
+		CreatedAt: now,
+	}
+	updated := *link
+	updated.LongURL = newURL
+	updated.Domain = extractDomain(newURL)
+	updated.CanonicalURL = canonicalURL
+	if _, err := s.linkRepo.ApplyLinkSchedule(&updated, now, revision); err != nil {
+		return fmt.Errorf("failed to apply link schedule: %w", err)
+	}
+	// Si une requête concurrente a déjà appliqué le changement, le résultat est le même.
+	link.LongURL, link.Domain, link.CanonicalURL = updated.LongURL, updated.Domain, updated.CanonicalURL
+	link.Schedule = link.Schedule[due:]
+	return nil
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/axellelanca/urlshortener/internal/repository"
+	"github.com/axellelanca/urlshortener/internal/testutil"
+)
+
+// setLocalZone remplace le fuseau local le temps d'un test. Les dates étant comparées sous forme de texte par
+// SQLite, un serveur hors UTC révèle les dates enregistrées avec un décalage différent de celui des requêtes.
+func setLocalZone(t *testing.T, zone *time.Location) {
+	t.Helper()
+	previous := time.Local
+	time.Local = zone
+	t.Cleanup(func() { time.Local = previous })
+}
+
+func TestApplyDueScheduleOutsideUTC(t *testing.T) {
+	tests := []struct {
+		name string
+		zone *time.Location
+	}{
+		{"UTC+2", time.FixedZone("UTC+2", 2*60*60)},
+		{"UTC-5", time.FixedZone("UTC-5", -5*60*60)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setLocalZone(t, tt.zone)
+			linkRepo := repository.NewLinkRepository(testutil.NewDB(t))
+			linkService := NewLinkService(linkRepo)
+
+			// Dates reçues en UTC, comme celles d'un JSON avec le suffixe Z.
+			now := time.Now().UTC()
+			link, _, err := linkService.CreateLink("https://event.site.com/inscription", CreateLinkOptions{
+				Schedule: []ScheduledURL{
+					{At: now.Add(time.Hour), URL: "https://event.site.com/live"},
+					{At: now.Add(3 * time.Hour), URL: "https://event.site.com/replay"},
+				},
+			})
+			if err != nil {
+				t.Fatalf("CreateLink: %v", err)
+			}
+
+			if err := linkService.applyDueSchedule(link, time.Now().Add(2*time.Hour)); err != nil {
+				t.Fatalf("applyDueSchedule: %v", err)
+			}
+			if link.LongURL != "https://event.site.com/live" {
+				t.Errorf("URL longue = %q, attendu le changement échu", link.LongURL)
+			}
+			remaining, err := linkRepo.GetLinkSchedule(link.ID)
+			if err != nil {
+				t.Fatalf("GetLinkSchedule: %v", err)
+			}
+			if len(remaining) != 1 || remaining[0].URL != "https://event.site.com/replay" {
+				t.Errorf("changements restants = %v, attendu seulement le changement à venir", remaining)
+			}
+		})
+	}
+}
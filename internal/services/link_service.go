@@ -1,15 +1,16 @@
 package services
 
 import (
-	"crypto/rand"
 	"errors"
 	"fmt"
 	"log"
-	"math/big"
+	"strings"
+	"sync"
 	"time"
 
 	"gorm.io/gorm" // Nécessaire pour la gestion spécifique de gorm.ErrRecordNotFound
 
+	"github.com/axellelanca/urlshortener/internal/config"
 	"github.com/axellelanca/urlshortener/internal/models"
 	"github.com/axellelanca/urlshortener/internal/repository" // Importe le package repository
 )
@@ -17,20 +18,239 @@ import (
 // Définition du jeu de caractères pour la génération des codes courts.
 const charset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
 
+// Politique des alias personnalisés (vanity URLs).
+// La longueur maximale correspond à la taille de la colonne Shortcode (size:10).
+const (
+	aliasCharset   = charset + "-_"
+	aliasMinLength = 3
+	aliasMaxLength = 10
+)
+
+// reservedAliases liste les codes qui entreraient en conflit avec les routes du serveur.
+var reservedAliases = map[string]bool{
+	"api":    true,
+	"health": true,
+}
+
+// CreateLinkOptions regroupe les paramètres optionnels de création d'un lien.
+type CreateLinkOptions struct {
+	// Alias est le code court personnalisé souhaité. Vide = code généré aléatoirement.
+	Alias string
+	// ExpiresAt est la date d'expiration absolue du lien (optionnelle).
+	ExpiresAt *time.Time
+	// TTL est la durée de vie relative du lien à partir de sa création (optionnelle).
+	// Ne peut pas être combinée avec ExpiresAt.
+	TTL time.Duration
+	// ExpiredURL est l'URL de repli utilisée une fois le lien expiré (optionnelle).
+	ExpiredURL string
+	// MaxClicks est le nombre maximum de redirections autorisées (0 = illimité).
+	MaxClicks int
+	// OneTime crée un lien à usage unique ("burn after first click"), équivalent à MaxClicks = 1.
+	OneTime bool
+	// Password protège le lien par un mot de passe (stocké hashé). Vide = lien public.
+	Password string
+	// Title, Description et Notes décrivent l'usage du lien (optionnels).
+	Title       string
+	Description string
+	Notes       string
+	// Tags sont les étiquettes du lien, normalisées par NormalizeTags (optionnels).
+	Tags []string
+	// UTMPreset est le nom d'un preset UTM de la configuration (utm.presets), optionnel.
+	UTMPreset string
+	// UTM sont les paramètres UTM ajoutés à l'URL longue ; ils ont priorité sur ceux du preset.
+	UTM UTMParams
+	// ForwardQuery transmet la query string des requêtes de redirection à la destination.
+	ForwardQuery bool
+	// ForwardPath transmet le chemin demandé après le code court à la destination.
+	ForwardPath bool
+	// DeviceURLs sont les destinations alternatives par type d'appareil (ios, android, desktop), optionnelles.
+	DeviceURLs map[string]string
+	// CountryURLs sont les destinations alternatives par pays (code ISO 3166-1 alpha-2), optionnelles.
+	CountryURLs map[string]string
+	// LanguageURLs sont les destinations alternatives par langue (étiquette BCP 47, ex: fr, en-US), optionnelles.
+	LanguageURLs map[string]string
+	// Variants sont les destinations d'un test A/B, tirées au sort selon leur poids (optionnelles).
+	Variants []Variant
+	// StickyVariant conserve la variante tirée pour un visiteur (cookie) au lieu d'un tirage à chaque clic.
+	StickyVariant bool
+	// ActivatesAt est la date d'activation du lien (optionnelle) : avant, la redirection renvoie vers PendingURL.
+	ActivatesAt *time.Time
+	// PendingURL est l'URL d'attente utilisée avant l'activation (optionnelle, schedule.placeholder_url par défaut).
+	PendingURL string
+	// Schedule sont les changements de destination programmés (optionnels).
+	Schedule []ScheduledURL
+	// Preview affiche systématiquement la page d'aperçu avant la redirection.
+	Preview bool
+	// RedirectStatus est le code HTTP de redirection du lien (301, 302, 307 ou 308). 0 = code de la configuration.
+	RedirectStatus int
+	// FetchDestination récupère en arrière-plan les métadonnées de la destination après la création.
+	// nil = comportement de la configuration (destination.fetch_on_create).
+	FetchDestination *bool
+}
+
+// minLinkTTL est la durée de vie relative minimale d'un lien : un ttl plus court créerait un lien
+// expiré avant même d'avoir pu être partagé.
+const minLinkTTL = time.Minute
+
+// localTime retourne t dans le fuseau local, ou nil. Le driver SQLite enregistre les dates sous forme de texte
+// avec leur décalage horaire et les compare telles quelles : les dates fournies par l'utilisateur (souvent en UTC)
+// sont donc enregistrées dans le fuseau local, comme toutes celles créées par l'application.
+func localTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	local := t.Local()
+	return &local
+}
+
+// resolveExpiration calcule la date d'expiration effective à partir des options.
+// Elle retourne nil si le lien ne doit jamais expirer.
+func (o CreateLinkOptions) resolveExpiration(now time.Time) (*time.Time, error) {
+	if o.ExpiresAt != nil && o.TTL != 0 {
+		return nil, fmt.Errorf("%w: expires_at et ttl ne peuvent pas être utilisés ensemble", ErrInvalidExpiration)
+	}
+	if o.TTL < 0 {
+		return nil, fmt.Errorf("%w: le ttl doit être positif", ErrInvalidExpiration)
+	}
+	if o.TTL > 0 && o.TTL < minLinkTTL {
+		return nil, fmt.Errorf("%w: le ttl doit être d'au moins %s", ErrInvalidExpiration, minLinkTTL)
+	}
+	if o.TTL > 0 {
+		expiresAt := now.Add(o.TTL)
+		return &expiresAt, nil
+	}
+	if o.ExpiresAt != nil && !o.ExpiresAt.After(now) {
+		return nil, fmt.Errorf("%w: la date d'expiration doit être dans le futur", ErrInvalidExpiration)
+	}
+	if o.ExpiredURL != "" && o.ExpiresAt == nil {
+		return nil, fmt.Errorf("%w: expired_url nécessite expires_at ou ttl", ErrInvalidExpiration)
+	}
+	return localTime(o.ExpiresAt), nil
+}
+
+// resolveMaxClicks calcule la limite de redirections effective à partir des options.
+func (o CreateLinkOptions) resolveMaxClicks() (int, error) {
+	if o.MaxClicks < 0 {
+		return 0, fmt.Errorf("%w: max_clicks doit être positif", ErrInvalidClickLimit)
+	}
+	if o.OneTime {
+		if o.MaxClicks > 1 {
+			return 0, fmt.Errorf("%w: un lien à usage unique ne peut pas avoir max_clicks > 1", ErrInvalidClickLimit)
+		}
+		return 1, nil
+	}
+	return o.MaxClicks, nil
+}
+
+// resolveTargets valide les destinations alternatives des options, tous critères confondus.
+func (o CreateLinkOptions) resolveTargets() ([]models.LinkTarget, error) {
+	var targets []models.LinkTarget
+	for _, kind := range []struct {
+		name string
+		urls map[string]string
+	}{
+		{models.TargetKindDevice, o.DeviceURLs},
+		{models.TargetKindCountry, o.CountryURLs},
+		{models.TargetKindLanguage, o.LanguageURLs},
+	} {
+		kindTargets, err := normalizeTargets(kind.name, kind.urls)
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, kindTargets...)
+	}
+	variantTargets, err := normalizeVariants(o.Variants)
+	if err != nil {
+		return nil, err
+	}
+	return append(targets, variantTargets...), nil
+}
+
 // Done Créer la struct
 // LinkService est une structure qui g fournit des méthodes pour la logique métier des liens.
 // Elle détient linkRepo qui est une référence vers une interface LinkRepository.
 // IMPORTANT : Le champ doit être du type de l'interface (non-pointeur).
 
 type LinkService struct {
-	linkRepo repository.LinkRepository
+	linkRepo  repository.LinkRepository
+	codeGen   CodeGenerator    // Génération des codes courts sans alias
+	tuner     *codeLengthTuner // Allongement des codes générés selon le taux de collision
+	urlPolicy URLPolicy        // Mise sous forme canonique des URLs et politique de doublons
+
+	utmPresets     map[string]UTMParams // Presets UTM nommés, par nom en minuscules
+	redirectPolicy RedirectPolicy       // Code de redirection par défaut et mise en cache des redirections permanentes
+	countryLocator CountryLocator       // Géolocalisation des visiteurs (nil si aucune base GeoIP n'est configurée)
+	placeholderURL string               // URL d'attente par défaut des liens pas encore activés
+
+	destinationFetcher       *DestinationFetcher // Récupération des métadonnées des destinations
+	fetchDestinationOnCreate bool                // Récupérer les métadonnées à la création par défaut
+	destinationFetches       *sync.WaitGroup     // Récupérations en arrière-plan en cours (partagé avec les copies de withRepo)
 }
 
 // NewLinkService crée et retourne une nouvelle instance de LinkService.
+// Les codes courts sont générés aléatoirement (6 caractères) tant que SetCodeGenerator n'est pas appelé,
+// les doublons sont refusés tant que SetURLPolicy n'est pas appelé, et les redirections sont en 302
+// tant que SetRedirectPolicy n'est pas appelé. Les métadonnées des destinations ne sont récupérées
+// qu'à la demande tant que SetFetchDestinationOnCreate n'est pas appelé.
 func NewLinkService(linkRepo repository.LinkRepository) *LinkService {
 	return &LinkService{
-		linkRepo: linkRepo,
+		linkRepo:  linkRepo,
+		codeGen:   &randomCodeGenerator{alphabet: charset, length: defaultCodeLength},
+		tuner:     &codeLengthTuner{},
+		urlPolicy: URLPolicy{DuplicatePolicy: DuplicatePolicyReject, TrackingParams: defaultTrackingParams},
+
+		redirectPolicy: RedirectPolicy{Status: defaultRedirectStatus, CacheMaxAge: defaultRedirectCacheMaxAge},
+
+		destinationFetcher: NewDestinationFetcher(nil),
+		destinationFetches: &sync.WaitGroup{},
+	}
+}
+
+// NewLinkServiceFromConfig crée un LinkService configuré selon les sections shortcode, links, utm, redirect, schedule
+// et destination de la configuration.
+func NewLinkServiceFromConfig(linkRepo repository.LinkRepository, cfg *config.Config) (*LinkService, error) {
+	codeGen, err := NewCodeGenerator(cfg.ShortCode.Strategy, cfg.ShortCode.Length, cfg.ShortCode.Alphabet)
+	if err != nil {
+		return nil, fmt.Errorf("configuration shortcode invalide: %w", err)
+	}
+	urlPolicy, err := NewURLPolicy(cfg.Links.DuplicatePolicy, cfg.Links.StripTrackingParams, cfg.Links.TrackingParams)
+	if err != nil {
+		return nil, fmt.Errorf("configuration links invalide: %w", err)
+	}
+	redirectPolicy, err := NewRedirectPolicy(cfg.Redirect.Status, cfg.Redirect.CacheMaxAge)
+	if err != nil {
+		return nil, fmt.Errorf("configuration redirect invalide: %w", err)
+	}
+	linkService := NewLinkService(linkRepo)
+	linkService.SetCodeGenerator(codeGen)
+	linkService.SetURLPolicy(urlPolicy)
+	utmPresets := make(map[string]UTMParams, len(cfg.UTM.Presets))
+	for name, preset := range cfg.UTM.Presets {
+		utmPresets[name] = UTMParams(preset)
+	}
+	linkService.SetUTMPresets(utmPresets)
+	linkService.SetRedirectPolicy(redirectPolicy)
+	if err := linkService.SetPlaceholderURL(cfg.Schedule.PlaceholderURL); err != nil {
+		return nil, fmt.Errorf("configuration schedule invalide: %w", err)
+	}
+	if cfg.Destination.TimeoutSeconds < 0 {
+		return nil, errors.New("configuration destination invalide: timeout_seconds doit être positif")
 	}
+	if cfg.Destination.TimeoutSeconds > 0 {
+		linkService.SetDestinationFetcher(NewDestinationFetcher(newDestinationClient(time.Duration(cfg.Destination.TimeoutSeconds) * time.Second)))
+	}
+	linkService.SetFetchDestinationOnCreate(cfg.Destination.FetchOnCreate)
+	return linkService, nil
+}
+
+// SetCodeGenerator remplace la stratégie de génération des codes courts (voir NewCodeGenerator).
+func (s *LinkService) SetCodeGenerator(codeGen CodeGenerator) {
+	s.codeGen = codeGen
+}
+
+// SetURLPolicy remplace la politique de détection des doublons (voir NewURLPolicy).
+func (s *LinkService) SetURLPolicy(urlPolicy URLPolicy) {
+	s.urlPolicy = urlPolicy
 }
 
 // Done Créer la méthode GenerateShortCode
@@ -39,98 +259,476 @@ func NewLinkService(linkRepo repository.LinkRepository) *LinkService {
 // Il utilise le package 'crypto/rand' pour éviter la prévisibilité.
 // Je vous laisse chercher un peu :) C'est faisable en une petite dizaine de ligne
 func (s *LinkService) GenerateShortCode(length int) (string, error) {
-	code := make([]byte, length)
-	charsetLength := big.NewInt(int64(len(charset)))
+	return randomString(charset, length)
+}
 
-	for i := 0; i < length; i++ {
-		randIndex, err := rand.Int(rand.Reader, charsetLength)
-		if err != nil {
-			return "", fmt.Errorf("failed to generate random number: %w", err)
+// ValidateAlias vérifie qu'un alias respecte la politique de codes courts :
+// longueur comprise entre aliasMinLength et aliasMaxLength, caractères de aliasCharset uniquement
+// et pas de mot réservé.
+func ValidateAlias(alias string) error {
+	if len(alias) < aliasMinLength || len(alias) > aliasMaxLength {
+		return fmt.Errorf("%w: la longueur doit être comprise entre %d et %d caractères", ErrInvalidAlias, aliasMinLength, aliasMaxLength)
+	}
+	for _, r := range alias {
+		if !strings.ContainsRune(aliasCharset, r) {
+			return fmt.Errorf("%w: caractère '%c' non autorisé (lettres, chiffres, '-' et '_' uniquement)", ErrInvalidAlias, r)
 		}
-		code[i] = charset[int(randIndex.Int64())]
 	}
-	return string(code), nil
+	if reservedAliases[strings.ToLower(alias)] {
+		return fmt.Errorf("%w: '%s' est un mot réservé", ErrInvalidAlias, alias)
+	}
+	return nil
 }
 
 // CreateLink crée un nouveau lien raccourci.
-// Il génère un code court unique, puis persiste le lien dans la base de données.
-func (s *LinkService) CreateLink(longURL string) (*models.Link, error) {
+// Les paramètres UTM de opts (preset puis valeurs explicites) sont d'abord ajoutés à l'URL longue.
+// Il utilise l'alias fourni dans opts s'il y en a un, sinon il génère un code court unique,
+// puis persiste le lien dans la base de données.
+// Si l'URL (sous forme canonique) a déjà un lien, la politique de doublons s'applique : ErrURLAlreadyExists,
+// ou lien existant retourné avec created = false (les options sont alors ignorées), ou nouveau lien.
+// Si la récupération des métadonnées de la destination est demandée (opts.FetchDestination, sinon la configuration),
+// elle est lancée en arrière-plan une fois le lien créé (voir RefreshDestination pour une récupération immédiate).
+func (s *LinkService) CreateLink(longURL string, opts CreateLinkOptions) (*models.Link, bool, error) {
+	link, created, err := s.createLink(longURL, opts)
+	if err != nil || !created {
+		return link, created, err
+	}
+	if s.shouldFetchDestination(opts) {
+		s.fetchDestinationAsync(link.ID, link.LongURL)
+	}
+	return link, true, nil
+}
+
+// createLink crée le lien décrit par CreateLink, sans récupérer les métadonnées de sa destination.
+func (s *LinkService) createLink(longURL string, opts CreateLinkOptions) (*models.Link, bool, error) {
+	utm, err := s.resolveUTM(opts.UTMPreset, opts.UTM)
+	if err != nil {
+		return nil, false, err
+	}
+	if longURL, err = ApplyUTM(longURL, utm); err != nil {
+		return nil, false, err
+	}
+	if err := validateLongURL(longURL); err != nil {
+		return nil, false, err
+	}
+
+	canonicalURL, err := s.urlPolicy.Canonicalize(longURL)
+	if err != nil {
+		return nil, false, err
+	}
+
 	// Vérifier si l'URL longue existe déjà
-	existingLink, err := s.linkRepo.GetLinkByLongURL(longURL)
-	if err == nil && existingLink != nil {
+	existingLink, err := s.findDuplicate(canonicalURL, 0)
+	if err != nil {
+		return nil, false, err
+	}
+	if existingLink != nil {
+		if s.urlPolicy.DuplicatePolicy == DuplicatePolicyReturnExisting {
+			return existingLink, false, nil
+		}
 		// URL déjà existante, retourner une erreur
-		return nil, ErrURLAlreadyExists
+		return nil, false, ErrURLAlreadyExists
+	}
+
+	now := time.Now()
+	expiresAt, err := opts.resolveExpiration(now)
+	if err != nil {
+		return nil, false, err
+	}
+	maxClicks, err := opts.resolveMaxClicks()
+	if err != nil {
+		return nil, false, err
+	}
+	if opts.PendingURL != "" && opts.ActivatesAt == nil {
+		return nil, false, fmt.Errorf("%w: pending_url nécessite activates_at", ErrInvalidSchedule)
+	}
+	if err := validateActivation(opts.ActivatesAt, expiresAt, opts.PendingURL, now); err != nil {
+		return nil, false, err
+	}
+	schedule, err := normalizeSchedule(opts.Schedule, now)
+	if err != nil {
+		return nil, false, err
+	}
+	var passwordHash string
+	if opts.Password != "" {
+		if passwordHash, err = hashPassword(opts.Password); err != nil {
+			return nil, false, err
+		}
+	}
+	title, description, notes := strings.TrimSpace(opts.Title), strings.TrimSpace(opts.Description), strings.TrimSpace(opts.Notes)
+	if err := validateMetadata(title, description, notes); err != nil {
+		return nil, false, err
+	}
+	tags, err := s.resolveTags(opts.Tags)
+	if err != nil {
+		return nil, false, err
+	}
+	if opts.RedirectStatus != 0 {
+		if err := ValidateRedirectStatus(opts.RedirectStatus); err != nil {
+			return nil, false, err
+		}
+	}
+	targets, err := opts.resolveTargets()
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Done Crée une nouvelle instance du modèle Link.
+	link := &models.Link{
+		LongURL:      longURL,
+		Domain:       extractDomain(longURL),
+		CanonicalURL: canonicalURL,
+		Title:        title,
+		Description:  description,
+		Notes:        notes,
+		Tags:         tags,
+		Targets:      targets,
+		Schedule:     schedule,
+		CreatedAt:    now,
+		ExpiresAt:    expiresAt,
+		ExpiredURL:   opts.ExpiredURL,
+		ActivatesAt:  localTime(opts.ActivatesAt),
+		PendingURL:   opts.PendingURL,
+		MaxClicks:    maxClicks,
+		ForwardQuery: opts.ForwardQuery,
+		ForwardPath:  opts.ForwardPath,
+		Preview:      opts.Preview,
+
+		StickyVariant:  opts.StickyVariant,
+		RedirectStatus: opts.RedirectStatus,
+
+		PasswordHash: passwordHash,
+	}
+
+	// Un alias est réservé par son insertion même : l'index unique sur Shortcode tranche
+	// entre deux créations concurrentes, et couvre aussi les liens à la corbeille.
+	if opts.Alias != "" {
+		if err := ValidateAlias(opts.Alias); err != nil {
+			return nil, false, err
+		}
+		link.Shortcode = opts.Alias
+		if err := s.linkRepo.CreateLink(link); err != nil {
+			if errors.Is(err, repository.ErrDuplicateKey) {
+				return nil, false, ErrAliasAlreadyExists
+			}
+			return nil, false, fmt.Errorf("failed to create link in database: %w", err)
+		}
+		return link, true, nil
+	}
+
+	// Un code dérivé de l'ID du lien (stratégie counter) n'est calculable qu'après insertion.
+	if s.codeGen.NeedsLinkID() {
+		if err := s.createLinkWithIDCode(link); err != nil {
+			return nil, false, err
+		}
+		return link, true, nil
+	}
+
+	// Done Persiste le nouveau lien dans la base de données via le repository (CreateLink)
+	err = s.allocateShortCode(CodeRequest{LongURL: longURL}, func(txRepo repository.LinkRepository, code string) error {
+		link.Shortcode = code
+		return txRepo.CreateLink(link)
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Done Retourne le lien créé
+	return link, true, nil
+}
+
+// findDuplicate retourne le lien existant (autre que exceptID) ayant cette URL canonique,
+// ou nil si aucun n'existe ou si la politique de doublons autorise toujours un nouveau lien.
+func (s *LinkService) findDuplicate(canonicalURL string, exceptID uint) (*models.Link, error) {
+	if s.urlPolicy.DuplicatePolicy == DuplicatePolicyAlwaysNew {
+		return nil, nil
 	}
-	// Si erreur différente de "not found", la retourner
-	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+	links, err := s.linkRepo.GetLinksByCanonicalURL(canonicalURL)
+	if err != nil {
 		return nil, fmt.Errorf("database error checking URL existence: %w", err)
 	}
+	for i := range links {
+		if links[i].ID != exceptID {
+			return &links[i], nil
+		}
+	}
+	return nil, nil
+}
 
-	// Done 1: Implémenter la logique de retry pour générer un code court unique.
-	// Essayez de générer un code, vérifiez s'il existe déjà en base, et retentez si une collision est trouvée.
-	// Limitez le nombre de tentatives pour éviter une boucle infinie.
+// ShortURL retourne l'URL courte complète d'un code, construite avec l'URL publique du serveur (server.base_url).
+func ShortURL(baseURL, shortCode string) string {
+	return strings.TrimSuffix(baseURL, "/") + "/" + shortCode
+}
+
+// LookupLinks retourne l'URL canonique de rawURL et tous les liens (hors corbeille) qui y mènent.
+func (s *LinkService) LookupLinks(rawURL string) (string, []models.Link, error) {
+	canonicalURL, err := s.urlPolicy.Canonicalize(rawURL)
+	if err != nil {
+		return "", nil, err
+	}
+	links, err := s.linkRepo.GetLinksByCanonicalURL(canonicalURL)
+	if err != nil {
+		return "", nil, err
+	}
+	return canonicalURL, links, nil
+}
 
-	// Done Créer une variable shortcode pour stocker le shortcode créé
-	var shortCode string
+// createLinkWithIDCode insère le lien avec un code provisoire, puis le remplace par le code dérivé de son ID.
+// Les deux opérations sont faites dans une transaction : le code provisoire n'est jamais visible.
+func (s *LinkService) createLinkWithIDCode(link *models.Link) error {
+	pending, err := randomString(charset, aliasMaxLength-1)
+	if err != nil {
+		return err
+	}
+	return s.linkRepo.Transaction(func(txRepo repository.LinkRepository) error {
+		// '~' est hors de l'alphabet des alias : le code provisoire ne peut entrer en conflit avec aucun code.
+		link.Shortcode = "~" + pending
+		if err := txRepo.CreateLink(link); err != nil {
+			return fmt.Errorf("failed to create link in database: %w", err)
+		}
+		req := CodeRequest{LongURL: link.LongURL, LinkID: link.ID}
+		return s.withRepo(txRepo).allocateShortCode(req, func(attemptRepo repository.LinkRepository, code string) error {
+			if err := attemptRepo.SetLinkShortcode(link.ID, code); err != nil {
+				return err
+			}
+			link.Shortcode = code
+			return nil
+		})
+	})
+}
 
+// allocateShortCode génère des codes avec le CodeGenerator configuré et tente de les enregistrer avec store,
+// jusqu'à ce que l'un d'eux ne viole pas l'index unique sur Shortcode. Il n'y a pas de vérification préalable :
+// deux créations concurrentes ne peuvent pas obtenir le même code.
+// Après maxRetries collisions à une longueur donnée, les codes sont allongés d'un caractère ; ErrShortCodeCollision
+// n'est retourné que si la longueur maximale du générateur est elle-même saturée, ou si un code généré
+// dépasse aliasMaxLength (taille de la colonne shortcode).
+func (s *LinkService) allocateShortCode(req CodeRequest, store func(attemptRepo repository.LinkRepository, code string) error) error {
 	// Done Définir un nombre maximum (5) de tentative pour trouver un code unique  (maxRetries)
 	maxRetries := 5
 
-	for i := 0; i < maxRetries; i++ {
-		// Done : Génère un code de 6 caractères (GenerateShortCode)
-		code, err := s.GenerateShortCode(6)
+	maxExtra := s.codeGen.MaxExtraLength()
+	req.ExtraLength = min(s.tuner.extraLength(), maxExtra)
+	for retries := 0; ; retries++ {
+		if retries == maxRetries {
+			if req.ExtraLength >= maxExtra {
+				// Done : Si après toutes les tentatives, aucun code unique n'a été trouvé... Errors.New
+				return fmt.Errorf("%w: %d collisions à la longueur maximale des codes générés", ErrShortCodeCollision, maxRetries)
+			}
+			req.ExtraLength++
+			s.tuner.grow(req.ExtraLength)
+			log.Printf("Too many short code collisions, generated codes grow to %d extra character(s)", req.ExtraLength)
+			retries = 0
+		}
+
+		code, err := s.codeGen.Generate(req)
 		if err != nil {
-			return nil, fmt.Errorf("failed to generate short code: %w", err)
+			return fmt.Errorf("failed to generate short code: %w", err)
+		}
+		req.Attempt++
+		if len(code) > aliasMaxLength {
+			// Code compteur d'un ID trop grand pour l'alphabet : il ne tiendrait pas dans la colonne shortcode.
+			return fmt.Errorf("%w: le code '%s' dépasse %d caractères", ErrShortCodeCollision, code, aliasMaxLength)
+		}
+
+		// Un code généré ne doit pas non plus masquer une route du serveur.
+		if reservedAliases[strings.ToLower(code)] {
+			continue
 		}
 
-		// Done : Vérifie si le code généré existe déjà en base de données (GetLinkbyShortCode)
-		// On ignore la première valeur
-		_, err = s.linkRepo.GetLinkByShortCode(code)
+		// Chaque tentative a sa propre transaction (savepoint si l'appel est déjà transactionnel) :
+		// certaines bases invalident la transaction entière après une violation de contrainte.
+		err = s.linkRepo.Transaction(func(attemptRepo repository.LinkRepository) error {
+			return store(attemptRepo, code)
+		})
+		if errors.Is(err, repository.ErrDuplicateKey) {
+			s.tuner.record(true, maxExtra)
+			// Le code existe déjà : collision, la boucle continuera pour générer un nouveau code.
+			log.Printf("Short code '%s' already exists, retrying generation (%d/%d)...", code, retries+1, maxRetries)
+			continue
+		}
 		if err != nil {
-			// Si l'erreur est 'record not found' de GORM, cela signifie que le code est unique.
-			if errors.Is(err, gorm.ErrRecordNotFound) {
-				shortCode = code // Le code est unique, on peut l'utiliser
-				break            // Sort de la boucle de retry
-			}
-			// Si c'est une autre erreur de base de données, retourne l'erreur.
-			return nil, fmt.Errorf("database error checking short code uniqueness: %w", err)
+			return fmt.Errorf("failed to create link in database: %w", err)
 		}
+		s.tuner.record(false, maxExtra)
+		return nil
+	}
+}
+
+// GetLinkByShortCode récupère un lien via son code court.
+// Il délègue l'opération de recherche au repository.
+func (s *LinkService) GetLinkByShortCode(shortCode string) (*models.Link, error) {
+	// Done : Récupérer un lien par son code court en utilisant s.linkRepo.GetLinkByShortCode.
+	// Retourner le lien trouvé ou une erreur si non trouvé/problème DB.
+	link, err := s.linkRepo.GetLinkByShortCode(shortCode)
+	if err != nil {
+		return nil, err
+	}
+	return link, nil
+}
 
-		// Si aucune erreur (le code a été trouvé), cela signifie une collision.
-		log.Printf("Short code '%s' already exists, retrying generation (%d/%d)...", code, i+1, maxRetries)
-		// La boucle continuera pour générer un nouveau code.
+// ConsumeClick réserve une redirection pour un lien limité en nombre de clics.
+// Pour un lien sans limite, elle ne fait rien. Sinon, le compteur est incrémenté
+// atomiquement en base et ErrClickLimitReached est retourné si la limite est déjà atteinte.
+// Elle doit être appelée dans le chemin de redirection, avant l'envoi du ClickEvent asynchrone.
+func (s *LinkService) ConsumeClick(link *models.Link) error {
+	if !link.HasClickLimit() {
+		return nil
 	}
+	ok, err := s.linkRepo.ConsumeClick(link.ID)
+	if err != nil {
+		return fmt.Errorf("failed to consume click for link %d: %w", link.ID, err)
+	}
+	if !ok {
+		return ErrClickLimitReached
+	}
+	link.ClickCount++
+	return nil
+}
 
-	// Done : Si après toutes les tentatives, aucun code unique n'a été trouvé... Errors.New
-	if shortCode == "" {
-		return nil, ErrShortCodeCollision
+// UpdateLongURL modifie l'URL longue d'un lien existant et conserve l'ancienne valeur
+// dans l'historique des révisions. actor identifie l'auteur de la modification.
+// Si la nouvelle URL est identique à l'actuelle, le lien est retourné sans créer de révision.
+func (s *LinkService) UpdateLongURL(shortCode, newURL, actor string) (*models.Link, error) {
+	link, err := s.linkRepo.GetLinkByShortCode(shortCode)
+	if err != nil {
+		return nil, err
+	}
+	if link.LongURL == newURL {
+		return link, nil
+	}
+	if err := validateLongURL(newURL); err != nil {
+		return nil, err
 	}
 
-	// Done Crée une nouvelle instance du modèle Link.
-	link := &models.Link{
-		Shortcode: shortCode,
-		LongURL:   longURL,
+	// Même règle qu'à la création : sauf politique always_new, une URL longue ne peut appartenir qu'à un seul lien.
+	canonicalURL, err := s.urlPolicy.Canonicalize(newURL)
+	if err != nil {
+		return nil, err
+	}
+	existingLink, err := s.findDuplicate(canonicalURL, link.ID)
+	if err != nil {
+		return nil, err
+	}
+	if existingLink != nil {
+		return nil, ErrURLAlreadyExists
+	}
+
+	revision := &models.LinkRevision{
+		LinkID:    link.ID,
+		OldURL:    link.LongURL,
+		NewURL:    newURL,
+		Actor:     actor,
 		CreatedAt: time.Now(),
 	}
+	link.LongURL = newURL
+	link.Domain = extractDomain(newURL)
+	link.CanonicalURL = canonicalURL
+	if err := s.linkRepo.UpdateLongURL(link, revision); err != nil {
+		return nil, fmt.Errorf("failed to update link in database: %w", err)
+	}
+	return link, nil
+}
 
-	// Done Persiste le nouveau lien dans la base de données via le repository (CreateLink)
-	if err := s.linkRepo.CreateLink(link); err != nil {
-		return nil, fmt.Errorf("failed to create link in database: %w", err)
+// GetLinkHistory récupère un lien et l'historique des modifications de son URL longue.
+func (s *LinkService) GetLinkHistory(shortCode string) (*models.Link, []models.LinkRevision, error) {
+	link, err := s.linkRepo.GetLinkByShortCode(shortCode)
+	if err != nil {
+		return nil, nil, err
+	}
+	revisions, err := s.linkRepo.GetRevisionsByLinkID(link.ID)
+	if err != nil {
+		return nil, nil, err
 	}
+	return link, revisions, nil
+}
 
-	// Done Retourne le lien créé
+// GetDeletedLinkByShortCode récupère un lien à la corbeille via son code court.
+func (s *LinkService) GetDeletedLinkByShortCode(shortCode string) (*models.Link, error) {
+	return s.linkRepo.GetDeletedLinkByShortCode(shortCode)
+}
+
+// DeleteLink met un lien à la corbeille. Il reste restaurable pendant la durée de rétention.
+func (s *LinkService) DeleteLink(shortCode string) (*models.Link, error) {
+	link, err := s.linkRepo.GetLinkByShortCode(shortCode)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.linkRepo.DeleteLink(link.ID); err != nil {
+		return nil, fmt.Errorf("failed to delete link: %w", err)
+	}
 	return link, nil
 }
 
-// GetLinkByShortCode récupère un lien via son code court.
-// Il délègue l'opération de recherche au repository.
-func (s *LinkService) GetLinkByShortCode(shortCode string) (*models.Link, error) {
-	// Done : Récupérer un lien par son code court en utilisant s.linkRepo.GetLinkByShortCode.
-	// Retourner le lien trouvé ou une erreur si non trouvé/problème DB.
+// PurgeLink supprime définitivement un lien, qu'il soit actif ou à la corbeille,
+// ainsi que ses clics et son historique.
+func (s *LinkService) PurgeLink(shortCode string) (*models.Link, error) {
+	link, err := s.linkRepo.GetLinkByShortCode(shortCode)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		link, err = s.linkRepo.GetDeletedLinkByShortCode(shortCode)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := s.linkRepo.PurgeLink(link.ID); err != nil {
+		return nil, fmt.Errorf("failed to purge link: %w", err)
+	}
+	return link, nil
+}
+
+// PurgeExpiredTrash supprime définitivement les liens restés à la corbeille plus longtemps que retention.
+// Il retourne le nombre de liens purgés.
+func (s *LinkService) PurgeExpiredTrash(retention time.Duration) (int, error) {
+	links, err := s.linkRepo.GetDeletedLinksBefore(time.Now().Add(-retention))
+	if err != nil {
+		return 0, err
+	}
+	for i, link := range links {
+		if err := s.linkRepo.PurgeLink(link.ID); err != nil {
+			return i, fmt.Errorf("failed to purge link '%s': %w", link.Shortcode, err)
+		}
+	}
+	return len(links), nil
+}
+
+// RestoreLink sort un lien de la corbeille s'il y est depuis moins de retention.
+// Il renvoie gorm.ErrRecordNotFound si aucun lien à la corbeille n'a ce code.
+func (s *LinkService) RestoreLink(shortCode string, retention time.Duration) (*models.Link, error) {
+	link, err := s.linkRepo.GetDeletedLinkByShortCode(shortCode)
+	if err != nil {
+		return nil, err
+	}
+	if time.Since(link.DeletedAt.Time) > retention {
+		return nil, ErrRestoreWindowExpired
+	}
+
+	// Un autre lien a pu être créé pour la même URL pendant que celui-ci était à la corbeille.
+	existingLink, err := s.findDuplicate(link.CanonicalURL, link.ID)
+	if err != nil {
+		return nil, err
+	}
+	if existingLink != nil {
+		return nil, ErrURLAlreadyExists
+	}
+
+	if err := s.linkRepo.RestoreLink(link.ID); err != nil {
+		return nil, fmt.Errorf("failed to restore link: %w", err)
+	}
+	link.DeletedAt = gorm.DeletedAt{}
+	return link, nil
+}
+
+// SetLinkDisabled désactive (disabled = true) ou réactive un lien.
+// Un lien désactivé ne redirige plus mais conserve ses statistiques.
+func (s *LinkService) SetLinkDisabled(shortCode string, disabled bool) (*models.Link, error) {
 	link, err := s.linkRepo.GetLinkByShortCode(shortCode)
 	if err != nil {
 		return nil, err
 	}
+	if err := s.linkRepo.SetLinkDisabled(link.ID, disabled); err != nil {
+		return nil, fmt.Errorf("failed to update link status: %w", err)
+	}
+	link.Disabled = disabled
 	return link, nil
 }
 
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/axellelanca/urlshortener/internal/repository"
+	"github.com/axellelanca/urlshortener/internal/testutil"
+)
+
+func TestLongURLMustBeHTTP(t *testing.T) {
+	linkService := NewLinkService(repository.NewLinkRepository(testutil.NewDB(t)))
+	if _, _, err := linkService.CreateLink("https://example.com/page", CreateLinkOptions{Alias: "page"}); err != nil {
+		t.Fatalf("CreateLink: %v", err)
+	}
+
+	tests := []struct {
+		longURL string
+		wantErr bool
+	}{
+		{"https://example.com/autre", false},
+		{"http://example.com/", false},
+		{"ftp://example.com/fichier", true},
+		{"javascript://example.com/%0Aalert(1)", true},
+		{"mailto:contact@example.com", true},
+		{"/chemin/relatif", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.longURL, func(t *testing.T) {
+			_, _, err := linkService.CreateLink(tt.longURL, CreateLinkOptions{})
+			if got := errors.Is(err, ErrInvalidLongURL); got != tt.wantErr {
+				t.Errorf("CreateLink: erreur %v, URL refusée attendue: %t", err, tt.wantErr)
+			}
+			_, err = linkService.UpdateLongURL("page", tt.longURL, "test")
+			if got := errors.Is(err, ErrInvalidLongURL); got != tt.wantErr {
+				t.Errorf("UpdateLongURL: erreur %v, URL refusée attendue: %t", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateAlias(t *testing.T) {
+	tests := []struct {
+		alias   string
+		wantErr bool
+	}{
+		{"promo", false},
+		{"Promo-2024", false},
+		{"a_b", false},
+		{"abcdefghij", false},
+		{"ab", true},
+		{"abcdefghijk", true},
+		{"promo!", true},
+		{"été", true},
+		{"pro mo", true},
+		{"a/b", true},
+		{"api", true},
+		{"HEALTH", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.alias, func(t *testing.T) {
+			err := ValidateAlias(tt.alias)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateAlias(%q) = %v, erreur attendue: %t", tt.alias, err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidAlias) {
+				t.Errorf("ValidateAlias(%q) = %v, attendu ErrInvalidAlias", tt.alias, err)
+			}
+		})
+	}
+}
+
+func TestCreateLinkWithAlias(t *testing.T) {
+	linkService := NewLinkService(repository.NewLinkRepository(testutil.NewDB(t)))
+	link, _, err := linkService.CreateLink("https://example.com/promo", CreateLinkOptions{Alias: "promo"})
+	if err != nil {
+		t.Fatalf("CreateLink: %v", err)
+	}
+	if link.Shortcode != "promo" {
+		t.Errorf("code court = %q, attendu l'alias", link.Shortcode)
+	}
+	// Un alias mis à la corbeille reste pris : il pourrait encore être restauré.
+	if _, err := linkService.DeleteLink("promo"); err != nil {
+		t.Fatalf("DeleteLink: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		alias string
+		want  error
+	}{
+		{"alias déjà pris", "promo", ErrAliasAlreadyExists},
+		{"alias invalide", "ab", ErrInvalidAlias},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := linkService.CreateLink("https://example.com/autre", CreateLinkOptions{Alias: tt.alias})
+			if !errors.Is(err, tt.want) {
+				t.Errorf("CreateLink(alias %q) = %v, attendu %v", tt.alias, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/axellelanca/urlshortener/internal/models"
+	"github.com/axellelanca/urlshortener/internal/repository"
+)
+
+// maxTopLinks est le nombre de liens les plus cliqués retournés par GetStatsSummary.
+const maxTopLinks = 10
+
+// unknownClickValue est la clé sous laquelle ClickBreakdown compte les clics dont la caractéristique est inconnue
+// (par exemple le pays d'un clic enregistré sans base GeoIP).
+const unknownClickValue = "unknown"
+
+// ClickBreakdown est la répartition des clics d'un lien selon les caractéristiques des visiteurs.
+type ClickBreakdown struct {
+	ByDevice  map[string]int // Clics par type d'appareil (ios, android, desktop)
+	ByCountry map[string]int // Clics par pays (code ISO 3166-1 alpha-2)
+	ByVariant map[string]int // Clics par variante A/B, hors clics redirigés en dehors d'un test A/B
+	// Clics par langue négociée, hors clics redirigés vers une destination qui n'est pas celle d'une langue
+	ByLanguage map[string]int
+	BySource   map[string]int // Clics par source : "direct" ou "qr" (scan d'un QR code)
+}
+
+// clickSourceDirect est la clé sous laquelle ClickBreakdown.BySource compte les clics sans source (clics directs).
+const clickSourceDirect = "direct"
+
+// GetClickBreakdown calcule la répartition des clics d'un lien par type d'appareil, par pays, par variante A/B,
+// par langue et par source.
+func (s *LinkService) GetClickBreakdown(link *models.Link) (*ClickBreakdown, error) {
+	byDevice, err := s.countClicksBy(link, "device")
+	if err != nil {
+		return nil, err
+	}
+	byCountry, err := s.countClicksBy(link, "country")
+	if err != nil {
+		return nil, err
+	}
+	byVariant, err := s.countClicksBy(link, "variant")
+	if err != nil {
+		return nil, err
+	}
+	delete(byVariant, unknownClickValue)
+	byLanguage, err := s.countClicksBy(link, "language")
+	if err != nil {
+		return nil, err
+	}
+	delete(byLanguage, unknownClickValue)
+	bySource, err := s.countClicksBy(link, "source")
+	if err != nil {
+		return nil, err
+	}
+	if direct, ok := bySource[unknownClickValue]; ok {
+		delete(bySource, unknownClickValue)
+		bySource[clickSourceDirect] = direct
+	}
+	return &ClickBreakdown{ByDevice: byDevice, ByCountry: byCountry, ByVariant: byVariant, ByLanguage: byLanguage, BySource: bySource}, nil
+}
+
+// countClicksBy compte les clics d'un lien par valeur de la colonne column ; les clics sans valeur sont comptés sous "unknown".
+func (s *LinkService) countClicksBy(link *models.Link, column string) (map[string]int, error) {
+	counts, err := s.linkRepo.CountClicksGroupedBy(link.ID, column)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute click breakdown: %w", err)
+	}
+	if unknown, ok := counts[""]; ok {
+		delete(counts, "")
+		counts[unknownClickValue] += unknown
+	}
+	return counts, nil
+}
+
+// LinkStatsSummary agrège les statistiques d'un ensemble de liens (par exemple tous les liens d'un tag).
+type LinkStatsSummary struct {
+	TotalLinks  int
+	TotalClicks int
+	TopLinks    []repository.LinkWithClicks // Liens les plus cliqués, par nombre de clics décroissant
+}
+
+// GetStatsSummary agrège le nombre de liens et de clics des liens correspondant aux filtres de params
+// (domaine, dates, statut, tags). Le tri, l'ordre, la limite et le curseur de params sont ignorés.
+func (s *LinkService) GetStatsSummary(params ListLinksParams) (*LinkStatsSummary, error) {
+	summary := &LinkStatsSummary{}
+	err := s.eachListedLink(params, func(link repository.LinkWithClicks) {
+		summary.TotalLinks++
+		summary.TotalClicks += link.TotalClicks
+		if len(summary.TopLinks) < maxTopLinks {
+			summary.TopLinks = append(summary.TopLinks, link)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+// eachListedLink appelle fn pour chaque lien correspondant aux filtres de params, par nombre de clics décroissant,
+// en parcourant toutes les pages de ListLinks. Le tri, l'ordre, la limite et le curseur de params sont ignorés.
+func (s *LinkService) eachListedLink(params ListLinksParams, fn func(link repository.LinkWithClicks)) error {
+	params.Sort = repository.SortByClicks
+	params.Order = "desc"
+	params.Limit = maxListLimit
+	params.Cursor = ""
+
+	for {
+		page, err := s.ListLinks(params)
+		if err != nil {
+			return err
+		}
+		for _, link := range page.Links {
+			fn(link)
+		}
+		if page.NextCursor == "" {
+			return nil
+		}
+		params.Cursor = page.NextCursor
+	}
+}
@@ -0,0 +1,177 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/axellelanca/urlshortener/internal/models"
+)
+
+// Types d'appareil reconnus par DetectDevice, clés des destinations alternatives de type device.
+const (
+	DeviceIOS     = "ios"
+	DeviceAndroid = "android"
+	DeviceDesktop = "desktop"
+)
+
+var deviceTypes = map[string]bool{DeviceIOS: true, DeviceAndroid: true, DeviceDesktop: true}
+
+// DetectDevice déduit le type d'appareil d'un User-Agent : ios (iPhone, iPad, iPod), android, sinon desktop.
+func DetectDevice(userAgent string) string {
+	ua := strings.ToLower(userAgent)
+	switch {
+	case strings.Contains(ua, "iphone") || strings.Contains(ua, "ipad") || strings.Contains(ua, "ipod"):
+		return DeviceIOS
+	case strings.Contains(ua, "android"):
+		return DeviceAndroid
+	default:
+		return DeviceDesktop
+	}
+}
+
+// normalizeTargetKey valide et normalise la clé d'une destination alternative selon son critère.
+func normalizeTargetKey(kind, key string) (string, error) {
+	key = strings.TrimSpace(key)
+	switch kind {
+	case models.TargetKindDevice:
+		key = strings.ToLower(key)
+		if !deviceTypes[key] {
+			return "", fmt.Errorf("%w: type d'appareil '%s' inconnu (ios, android ou desktop)", ErrInvalidTarget, key)
+		}
+		return key, nil
+	case models.TargetKindCountry:
+		key = strings.ToUpper(key)
+		if !isCountryCode(key) {
+			return "", fmt.Errorf("%w: code pays '%s' invalide (code ISO 3166-1 alpha-2, ex: FR)", ErrInvalidTarget, key)
+		}
+		return key, nil
+	case models.TargetKindVariant:
+		key = strings.ToLower(key)
+		if !isVariantID(key) {
+			return "", fmt.Errorf("%w: identifiant de variante '%s' invalide (1 à %d lettres, chiffres, '-' ou '_')", ErrInvalidTarget, key, maxVariantIDLength)
+		}
+		return key, nil
+	case models.TargetKindLanguage:
+		key = normalizeLanguageTag(key)
+		if !isLanguageTag(key) {
+			return "", fmt.Errorf("%w: langue '%s' invalide (étiquette BCP 47, ex: fr, en-US)", ErrInvalidTarget, key)
+		}
+		return key, nil
+	default:
+		return "", fmt.Errorf("%w: critère '%s' inconnu", ErrInvalidTarget, kind)
+	}
+}
+
+// isCountryCode indique si code est un code pays ISO 3166-1 alpha-2 en majuscules (deux lettres A-Z).
+func isCountryCode(code string) bool {
+	if len(code) != 2 {
+		return false
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// maxVariantIDLength est la longueur maximale d'un identifiant de variante (taille de la colonne target_key).
+const maxVariantIDLength = 50
+
+// isVariantID indique si id est un identifiant de variante valide : lettres minuscules, chiffres, '-' et '_'.
+func isVariantID(id string) bool {
+	if id == "" || len(id) > maxVariantIDLength {
+		return false
+	}
+	for _, r := range id {
+		if !(r >= 'a' && r <= 'z') && !(r >= '0' && r <= '9') && r != '-' && r != '_' {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeTargets valide les destinations alternatives d'un critère, indexées par clé,
+// et retourne les LinkTarget correspondants triés par clé. Chaque URL doit être une URL http(s) absolue.
+func normalizeTargets(kind string, urls map[string]string) ([]models.LinkTarget, error) {
+	targets := make([]models.LinkTarget, 0, len(urls))
+	seen := make(map[string]bool, len(urls))
+	for rawKey, targetURL := range urls {
+		key, err := normalizeTargetKey(kind, rawKey)
+		if err != nil {
+			return nil, err
+		}
+		if seen[key] {
+			return nil, fmt.Errorf("%w: destination '%s' définie plusieurs fois", ErrInvalidTarget, key)
+		}
+		targetURL = strings.TrimSpace(targetURL)
+		if err := validateLongURL(targetURL); err != nil {
+			return nil, fmt.Errorf("%w: destination '%s': %v", ErrInvalidTarget, key, err)
+		}
+		seen[key] = true
+		targets = append(targets, models.LinkTarget{Kind: kind, Key: key, URL: targetURL})
+	}
+	sort.Slice(targets, func(i, j int) bool { return targets[i].Key < targets[j].Key })
+	return targets, nil
+}
+
+// ValidateTargets vérifie les destinations alternatives d'un critère sans les enregistrer.
+func ValidateTargets(kind string, urls map[string]string) error {
+	_, err := normalizeTargets(kind, urls)
+	return err
+}
+
+// SetLinkTargets remplace les destinations alternatives d'un lien pour le critère kind.
+// Une map vide retire toutes les destinations de ce critère.
+func (s *LinkService) SetLinkTargets(shortCode, kind string, urls map[string]string) (*models.Link, error) {
+	targets, err := normalizeTargets(kind, urls)
+	if err != nil {
+		return nil, err
+	}
+	link, err := s.linkRepo.GetLinkByShortCode(shortCode)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.linkRepo.ReplaceLinkTargets(link.ID, kind, targets); err != nil {
+		return nil, fmt.Errorf("failed to update link targets: %w", err)
+	}
+	if link.Targets, err = s.linkRepo.GetLinkTargets(link.ID); err != nil {
+		return nil, err
+	}
+	return link, nil
+}
+
+// loadTargets renseigne les destinations alternatives d'un lien si elles ne l'ont pas encore été.
+func (s *LinkService) loadTargets(link *models.Link) error {
+	if link.Targets != nil {
+		return nil
+	}
+	targets, err := s.linkRepo.GetLinkTargets(link.ID)
+	if err != nil {
+		return fmt.Errorf("failed to load link targets: %w", err)
+	}
+	link.Targets = append(make([]models.LinkTarget, 0, len(targets)), targets...)
+	return nil
+}
+
+// findTarget retourne la destination alternative du critère kind ayant la clé key, ou nil.
+func findTarget(targets []models.LinkTarget, kind, key string) *models.LinkTarget {
+	for i := range targets {
+		if targets[i].Kind == kind && targets[i].Key == key {
+			return &targets[i]
+		}
+	}
+	return nil
+}
+
+// TargetURLs retourne les destinations alternatives du critère kind, indexées par clé.
+func TargetURLs(targets []models.LinkTarget, kind string) map[string]string {
+	urls := make(map[string]string)
+	for _, target := range targets {
+		if target.Kind == kind {
+			urls[target.Key] = target.URL
+		}
+	}
+	return urls
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"github.com/axellelanca/urlshortener/internal/models"
+	"github.com/axellelanca/urlshortener/internal/repository"
+)
+
+// LinkUpdate regroupe toutes les modifications d'un lien demandées en une fois (PATCH de l'API, commande update).
+// Les champs vides ou nil ne sont pas modifiés.
+type LinkUpdate struct {
+	LongURL string // Nouvelle URL longue, conservée dans l'historique des révisions
+	Actor   string // Auteur de la modification de l'URL longue
+
+	Metadata LinkMetadataUpdate
+	Redirect LinkRedirectUpdate
+	Targets  map[string]map[string]string // Destinations alternatives à remplacer, par critère
+	Variants *[]Variant
+	Schedule LinkScheduleUpdate
+}
+
+// IsEmpty indique si la modification ne porte sur aucun champ.
+func (u LinkUpdate) IsEmpty() bool {
+	return u.LongURL == "" && u.Metadata.IsEmpty() && u.Redirect.IsEmpty() && len(u.Targets) == 0 &&
+		u.Variants == nil && u.Schedule.IsEmpty()
+}
+
+// UpdateLink applique toutes les modifications de update au lien, dans une seule transaction :
+// si l'une d'elles échoue (URL longue déjà utilisée par un autre lien, valeur invalide...), aucune n'est enregistrée.
+// Retourne le lien à jour, avec ses tags, ses destinations alternatives et sa programmation.
+func (s *LinkService) UpdateLink(shortCode string, update LinkUpdate) (*models.Link, error) {
+	err := s.linkRepo.Transaction(func(txRepo repository.LinkRepository) error {
+		tx := s.withRepo(txRepo)
+		// L'URL longue est vérifiée en premier : un doublon est l'erreur la plus probable.
+		if update.LongURL != "" {
+			if _, err := tx.UpdateLongURL(shortCode, update.LongURL, update.Actor); err != nil {
+				return err
+			}
+		}
+		if !update.Metadata.IsEmpty() {
+			if _, err := tx.UpdateLinkMetadata(shortCode, update.Metadata); err != nil {
+				return err
+			}
+		}
+		if !update.Redirect.IsEmpty() {
+			if _, err := tx.UpdateLinkRedirect(shortCode, update.Redirect); err != nil {
+				return err
+			}
+		}
+		for kind, urls := range update.Targets {
+			if _, err := tx.SetLinkTargets(shortCode, kind, urls); err != nil {
+				return err
+			}
+		}
+		if update.Variants != nil {
+			if _, err := tx.SetLinkVariants(shortCode, *update.Variants); err != nil {
+				return err
+			}
+		}
+		if !update.Schedule.IsEmpty() {
+			if _, err := tx.UpdateLinkSchedule(shortCode, update.Schedule); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetLinkInfo(shortCode)
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"sort"
+
+	"github.com/axellelanca/urlshortener/internal/models"
+)
+
+// maxVariantWeight est le poids maximum d'une variante A/B.
+const maxVariantWeight = 10000
+
+// Variant est une destination d'un test A/B : son identifiant (ex: "a", "nouvelle-page"),
+// son URL et son poids, c'est-à-dire sa part relative du trafic (0 = variante en pause).
+type Variant struct {
+	ID     string `json:"id"`
+	URL    string `json:"url"`
+	Weight int    `json:"weight"`
+}
+
+// normalizeVariants valide les variantes A/B d'un lien et retourne les LinkTarget correspondants triés par identifiant.
+// Une liste non vide doit comporter au moins une variante de poids positif.
+func normalizeVariants(variants []Variant) ([]models.LinkTarget, error) {
+	urls := make(map[string]string, len(variants))
+	weights := make(map[string]int, len(variants))
+	totalWeight := 0
+	for _, variant := range variants {
+		id, err := normalizeTargetKey(models.TargetKindVariant, variant.ID)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := urls[id]; ok {
+			return nil, fmt.Errorf("%w: variante '%s' définie plusieurs fois", ErrInvalidTarget, id)
+		}
+		if variant.Weight < 0 || variant.Weight > maxVariantWeight {
+			return nil, fmt.Errorf("%w: le poids de la variante '%s' doit être compris entre 0 et %d", ErrInvalidTarget, id, maxVariantWeight)
+		}
+		urls[id] = variant.URL
+		weights[id] = variant.Weight
+		totalWeight += variant.Weight
+	}
+	if len(variants) > 0 && totalWeight == 0 {
+		return nil, fmt.Errorf("%w: au moins une variante doit avoir un poids positif", ErrInvalidTarget)
+	}
+
+	targets, err := normalizeTargets(models.TargetKindVariant, urls)
+	if err != nil {
+		return nil, err
+	}
+	for i := range targets {
+		targets[i].Weight = weights[targets[i].Key]
+	}
+	return targets, nil
+}
+
+// ValidateVariants vérifie les variantes A/B d'un lien sans les enregistrer.
+func ValidateVariants(variants []Variant) error {
+	_, err := normalizeVariants(variants)
+	return err
+}
+
+// SetLinkVariants remplace les variantes A/B d'un lien. Une liste vide met fin au test A/B :
+// les clics déjà enregistrés conservent leur variante dans les statistiques.
+func (s *LinkService) SetLinkVariants(shortCode string, variants []Variant) (*models.Link, error) {
+	targets, err := normalizeVariants(variants)
+	if err != nil {
+		return nil, err
+	}
+	link, err := s.linkRepo.GetLinkByShortCode(shortCode)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.linkRepo.ReplaceLinkTargets(link.ID, models.TargetKindVariant, targets); err != nil {
+		return nil, fmt.Errorf("failed to update link variants: %w", err)
+	}
+	if link.Targets, err = s.linkRepo.GetLinkTargets(link.ID); err != nil {
+		return nil, err
+	}
+	return link, nil
+}
+
+// LinkVariants retourne les variantes A/B parmi les destinations alternatives d'un lien, triées par identifiant.
+func LinkVariants(targets []models.LinkTarget) []Variant {
+	variants := make([]Variant, 0)
+	for _, target := range targets {
+		if target.Kind == models.TargetKindVariant {
+			variants = append(variants, Variant{ID: target.Key, URL: target.URL, Weight: target.Weight})
+		}
+	}
+	sort.Slice(variants, func(i, j int) bool { return variants[i].ID < variants[j].ID })
+	return variants
+}
+
+// pickVariant choisit la variante A/B d'une requête. La variante assignée au visiteur (cookie d'un lien
+// à variante persistante) est conservée tant qu'elle existe et n'est pas en pause ; sinon une variante
+// est tirée au sort proportionnellement aux poids. Retourne nil si le lien n'a pas de variante active.
+func pickVariant(targets []models.LinkTarget, assigned string) *models.LinkTarget {
+	if assigned != "" {
+		if target := findTarget(targets, models.TargetKindVariant, assigned); target != nil && target.Weight > 0 {
+			return target
+		}
+	}
+
+	totalWeight := 0
+	for _, target := range targets {
+		if target.Kind == models.TargetKindVariant {
+			totalWeight += target.Weight
+		}
+	}
+	if totalWeight == 0 {
+		return nil
+	}
+	draw := rand.IntN(totalWeight)
+	for i := range targets {
+		if targets[i].Kind != models.TargetKindVariant {
+			continue
+		}
+		if draw < targets[i].Weight {
+			return &targets[i]
+		}
+		draw -= targets[i].Weight
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"math"
+	"testing"
+
+	"github.com/axellelanca/urlshortener/internal/models"
+)
+
+// variantTargets construit les destinations d'un test A/B à partir des poids des variantes, indexés par identifiant.
+func variantTargets(weights map[string]int) []models.LinkTarget {
+	variants := make([]Variant, 0, len(weights))
+	for id, weight := range weights {
+		variants = append(variants, Variant{ID: id, URL: "https://site.com/" + id, Weight: weight})
+	}
+	targets, err := normalizeVariants(variants)
+	if err != nil {
+		panic(err)
+	}
+	return targets
+}
+
+func TestPickVariant(t *testing.T) {
+	tests := []struct {
+		name     string
+		targets  []models.LinkTarget
+		assigned string
+		want     string // Variante retenue, "" si aucune
+	}{
+		{"sans variante", nil, "", ""},
+		{
+			"destinations d'un autre critère ignorées",
+			[]models.LinkTarget{{Kind: models.TargetKindLanguage, Key: "fr", URL: "https://site.com/fr", Weight: 5}},
+			"", "",
+		},
+		{"toutes les variantes en pause", []models.LinkTarget{{Kind: models.TargetKindVariant, Key: "a", Weight: 0}}, "", ""},
+		{"seule variante active", variantTargets(map[string]int{"a": 0, "b": 3}), "", "b"},
+		{"variante assignée conservée", variantTargets(map[string]int{"a": 1, "b": 10000}), "a", "a"},
+		{"variante assignée en pause", variantTargets(map[string]int{"a": 0, "b": 1}), "a", "b"},
+		{"variante assignée supprimée", variantTargets(map[string]int{"b": 1}), "a", "b"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Le tirage est répété : le résultat attendu ne doit pas dépendre du hasard.
+			for i := 0; i < 50; i++ {
+				got := ""
+				if target := pickVariant(tt.targets, tt.assigned); target != nil {
+					got = target.Key
+				}
+				if got != tt.want {
+					t.Fatalf("pickVariant(%q) = %q, attendu %q", tt.assigned, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestPickVariantWeights(t *testing.T) {
+	const draws = 20000
+	weights := map[string]int{"a": 70, "b": 30, "c": 0}
+	targets := variantTargets(weights)
+
+	counts := make(map[string]int)
+	for i := 0; i < draws; i++ {
+		counts[pickVariant(targets, "").Key]++
+	}
+	if counts["c"] != 0 {
+		t.Errorf("variante en pause tirée %d fois", counts["c"])
+	}
+	// Écart toléré de 3 points, soit plus de 9 écarts-types pour 20000 tirages.
+	for id, weight := range weights {
+		share := float64(counts[id]) / draws
+		if want := float64(weight) / 100; math.Abs(share-want) > 0.03 {
+			t.Errorf("variante %s tirée dans %.1f %% des cas, attendu %.0f %%", id, share*100, want*100)
+		}
+	}
+}
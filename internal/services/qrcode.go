@@ -0,0 +1,230 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"net/url"
+	"strconv"
+	"strings"
+
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+// Formats d'image des QR codes.
+const (
+	QRFormatPNG = "png"
+	QRFormatSVG = "svg"
+)
+
+// Limites et valeurs par défaut des options des QR codes.
+const (
+	defaultQRSize   = 256 // Côté de l'image, en pixels
+	minQRSize       = 64
+	maxQRSize       = 2048
+	defaultQRMargin = 4 // Marge (zone de silence), en modules
+	maxQRMargin     = 16
+	defaultQRLevel  = "M"
+)
+
+// qrLevels associe les niveaux de correction d'erreur (part du code pouvant être abîmée ou masquée) à ceux de go-qrcode.
+var qrLevels = map[string]qrcode.RecoveryLevel{
+	"L": qrcode.Low,     // 7 %
+	"M": qrcode.Medium,  // 15 %
+	"Q": qrcode.High,    // 25 %
+	"H": qrcode.Highest, // 30 %
+}
+
+// ClickSourceQR est la source des clics issus du scan d'un QR code : les URLs encodées portent le paramètre src=qr,
+// qui est enregistré avec le clic (colonne source) et n'est pas transmis à la destination.
+const ClickSourceQR = "qr"
+
+// clickSourceParam est le paramètre de query string qui indique la source d'un clic.
+const clickSourceParam = "src"
+
+// QROptions décrit l'image d'un QR code : format (png ou svg), côté en pixels, marge en modules,
+// niveau de correction d'erreur (L, M, Q ou H) et couleurs (#rrggbb ou #rgb). Les valeurs nulles prennent la valeur par défaut.
+type QROptions struct {
+	Format     string
+	Size       int
+	Margin     *int
+	Level      string
+	Foreground string
+	Background string
+}
+
+// qrImage est un QR code prêt à être dessiné : ses modules (true = module sombre), sans marge, et ses options validées.
+type qrImage struct {
+	modules    [][]bool
+	size       int
+	margin     int
+	foreground color.RGBA
+	background color.RGBA
+}
+
+// QRCodeURL retourne l'URL encodée dans le QR code d'un lien : son URL courte marquée src=qr.
+func QRCodeURL(baseURL, shortCode string) string {
+	return ShortURL(baseURL, shortCode) + "?" + clickSourceParam + "=" + ClickSourceQR
+}
+
+// QRContentType retourne le type MIME d'un format de QR code (PNG par défaut).
+func QRContentType(format string) string {
+	if strings.EqualFold(strings.TrimSpace(format), QRFormatSVG) {
+		return "image/svg+xml"
+	}
+	return "image/png"
+}
+
+// GenerateQRCode génère l'image du QR code encodant content. Retourne ErrInvalidQROptions si une option est invalide.
+func GenerateQRCode(content string, opts QROptions) ([]byte, error) {
+	format := strings.ToLower(strings.TrimSpace(opts.Format))
+	if format == "" {
+		format = QRFormatPNG
+	}
+	if format != QRFormatPNG && format != QRFormatSVG {
+		return nil, fmt.Errorf("%w: format '%s' inconnu (png ou svg)", ErrInvalidQROptions, opts.Format)
+	}
+	img, err := newQRImage(content, opts)
+	if err != nil {
+		return nil, err
+	}
+	if format == QRFormatSVG {
+		return img.svg(), nil
+	}
+	return img.png()
+}
+
+// newQRImage valide les options et encode content.
+func newQRImage(content string, opts QROptions) (*qrImage, error) {
+	img := &qrImage{size: opts.Size, margin: defaultQRMargin}
+	if img.size == 0 {
+		img.size = defaultQRSize
+	}
+	if img.size < minQRSize || img.size > maxQRSize {
+		return nil, fmt.Errorf("%w: la taille doit être comprise entre %d et %d pixels", ErrInvalidQROptions, minQRSize, maxQRSize)
+	}
+	if opts.Margin != nil {
+		img.margin = *opts.Margin
+	}
+	if img.margin < 0 || img.margin > maxQRMargin {
+		return nil, fmt.Errorf("%w: la marge doit être comprise entre 0 et %d modules", ErrInvalidQROptions, maxQRMargin)
+	}
+	levelName := strings.ToUpper(strings.TrimSpace(opts.Level))
+	if levelName == "" {
+		levelName = defaultQRLevel
+	}
+	level, ok := qrLevels[levelName]
+	if !ok {
+		return nil, fmt.Errorf("%w: niveau de correction '%s' inconnu (L, M, Q ou H)", ErrInvalidQROptions, opts.Level)
+	}
+	var err error
+	if img.foreground, err = parseQRColor(opts.Foreground, color.RGBA{A: 0xff}); err != nil {
+		return nil, err
+	}
+	if img.background, err = parseQRColor(opts.Background, color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}); err != nil {
+		return nil, err
+	}
+
+	code, err := qrcode.New(content, level)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidQROptions, err)
+	}
+	code.DisableBorder = true
+	img.modules = code.Bitmap()
+	if minSize := len(img.modules) + 2*img.margin; img.size < minSize {
+		return nil, fmt.Errorf("%w: taille trop petite pour ce code (%d pixels minimum)", ErrInvalidQROptions, minSize)
+	}
+	return img, nil
+}
+
+// parseQRColor décode une couleur hexadécimale (#rrggbb ou #rgb, '#' optionnel) ; vide = couleur par défaut.
+func parseQRColor(value string, defaultColor color.RGBA) (color.RGBA, error) {
+	hex := strings.TrimPrefix(strings.TrimSpace(value), "#")
+	if hex == "" {
+		return defaultColor, nil
+	}
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	rgb, err := strconv.ParseUint(hex, 16, 32)
+	if len(hex) != 6 || err != nil {
+		return color.RGBA{}, fmt.Errorf("%w: couleur '%s' invalide (#rrggbb ou #rgb)", ErrInvalidQROptions, value)
+	}
+	return color.RGBA{R: uint8(rgb >> 16), G: uint8(rgb >> 8), B: uint8(rgb), A: 0xff}, nil
+}
+
+// png dessine le QR code en PNG de size pixels de côté. Chaque module occupe un nombre entier de pixels,
+// pour un code net ; l'arrondi est absorbé par la marge, qui centre le code.
+func (q *qrImage) png() ([]byte, error) {
+	modules := len(q.modules)
+	scale := q.size / (modules + 2*q.margin)
+	offset := (q.size - scale*modules) / 2
+
+	img := image.NewPaletted(image.Rect(0, 0, q.size, q.size), color.Palette{q.background, q.foreground})
+	for y, row := range q.modules {
+		for x, dark := range row {
+			if !dark {
+				continue
+			}
+			for py := offset + y*scale; py < offset+(y+1)*scale; py++ {
+				for px := offset + x*scale; px < offset+(x+1)*scale; px++ {
+					img.SetColorIndex(px, py, 1)
+				}
+			}
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode QR code: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// svg dessine le QR code en SVG : une unité par module, affichée sur size pixels de côté.
+func (q *qrImage) svg() []byte {
+	total := len(q.modules) + 2*q.margin
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<?xml version="1.0" encoding="UTF-8"?>`+"\n")
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`+"\n", q.size, q.size, total, total)
+	fmt.Fprintf(&buf, `<rect width="%d" height="%d" fill="%s"/>`+"\n", total, total, hexColor(q.background))
+	fmt.Fprintf(&buf, `<path fill="%s" d="`, hexColor(q.foreground))
+	for y, row := range q.modules {
+		for x, dark := range row {
+			if dark {
+				fmt.Fprintf(&buf, "M%d %dh1v1h-1z", x+q.margin, y+q.margin)
+			}
+		}
+	}
+	buf.WriteString("\"/>\n</svg>\n")
+	return buf.Bytes()
+}
+
+// hexColor formate une couleur en #rrggbb.
+func hexColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// ClickSource retourne la source d'un clic d'après la query string de la requête : "qr" pour le scan d'un QR code
+// (src=qr), "" pour un clic direct.
+func ClickSource(rawQuery string) string {
+	// Les paramètres mal encodés sont ignorés : ParseQuery retourne tout de même les autres.
+	values, _ := url.ParseQuery(rawQuery)
+	if values.Get(clickSourceParam) == ClickSourceQR {
+		return ClickSourceQR
+	}
+	return ""
+}
+
+// withoutClickSource retire le marqueur src=qr d'une query string brute, pour ne pas le transmettre à la destination.
+func withoutClickSource(rawQuery string) string {
+	parts := strings.Split(rawQuery, "&")
+	kept := parts[:0]
+	for _, part := range parts {
+		if part != clickSourceParam+"="+ClickSourceQR {
+			kept = append(kept, part)
+		}
+	}
+	return strings.Join(kept, "&")
+}
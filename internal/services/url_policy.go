@@ -0,0 +1,121 @@
+package services
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// Politiques appliquées quand une URL longue (canonique) a déjà un lien, sélectionnées par
+// links.duplicate_policy dans la configuration.
+const (
+	DuplicatePolicyReject         = "reject"          // Refuser la création (409)
+	DuplicatePolicyReturnExisting = "return_existing" // Retourner le lien existant (200)
+	DuplicatePolicyAlwaysNew      = "always_new"      // Toujours créer un nouveau code
+)
+
+// defaultTrackingParams sont les paramètres de suivi retirés de l'URL canonique quand
+// StripTrackingParams est actif. Un nom terminé par '*' désigne un préfixe.
+var defaultTrackingParams = []string{
+	"utm_*", "fbclid", "gclid", "dclid", "gbraid", "wbraid", "msclkid",
+	"mc_cid", "mc_eid", "igshid", "yclid", "twclid", "_hsenc", "_hsmi",
+}
+
+// URLPolicy définit la détection des doublons : mise sous forme canonique des URLs longues
+// et conduite à tenir quand une URL a déjà un lien.
+type URLPolicy struct {
+	// DuplicatePolicy est l'une des constantes DuplicatePolicy*.
+	DuplicatePolicy string
+	// StripTrackingParams retire les paramètres de suivi (TrackingParams) de l'URL canonique.
+	StripTrackingParams bool
+	// TrackingParams remplace defaultTrackingParams s'il n'est pas vide.
+	TrackingParams []string
+}
+
+// NewURLPolicy crée et valide une politique d'URL. Une politique de doublons vide vaut DuplicatePolicyReject.
+func NewURLPolicy(duplicatePolicy string, stripTrackingParams bool, trackingParams []string) (URLPolicy, error) {
+	switch duplicatePolicy {
+	case "":
+		duplicatePolicy = DuplicatePolicyReject
+	case DuplicatePolicyReject, DuplicatePolicyReturnExisting, DuplicatePolicyAlwaysNew:
+	default:
+		return URLPolicy{}, fmt.Errorf("politique de doublons '%s' inconnue (%s, %s ou %s)",
+			duplicatePolicy, DuplicatePolicyReject, DuplicatePolicyReturnExisting, DuplicatePolicyAlwaysNew)
+	}
+	if len(trackingParams) == 0 {
+		trackingParams = defaultTrackingParams
+	}
+	return URLPolicy{
+		DuplicatePolicy:     duplicatePolicy,
+		StripTrackingParams: stripTrackingParams,
+		TrackingParams:      trackingParams,
+	}, nil
+}
+
+// Canonicalize retourne la forme canonique d'une URL, utilisée pour détecter les doublons :
+// schéma et hôte en minuscules, port par défaut retiré, slash final retiré, paramètres de requête triés
+// et, si StripTrackingParams est actif, paramètres de suivi retirés.
+// L'URL de destination enregistrée sur le lien n'est pas modifiée.
+func (p URLPolicy) Canonicalize(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidLongURL, err)
+	}
+	if !u.IsAbs() || u.Host == "" {
+		return "", fmt.Errorf("%w: une URL absolue est attendue", ErrInvalidLongURL)
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	host, port := strings.ToLower(u.Hostname()), u.Port()
+	if (u.Scheme == "http" && port == "80") || (u.Scheme == "https" && port == "443") {
+		port = ""
+	}
+	switch {
+	case port != "":
+		u.Host = net.JoinHostPort(host, port)
+	case strings.Contains(host, ":"): // IPv6
+		u.Host = "[" + host + "]"
+	default:
+		u.Host = host
+	}
+
+	if u.Path == "" {
+		u.Path = "/"
+	} else if len(u.Path) > 1 {
+		u.Path = strings.TrimRight(u.Path, "/")
+		if u.Path == "" {
+			u.Path = "/"
+		}
+	}
+	u.RawPath = ""
+
+	query := u.Query()
+	if p.StripTrackingParams {
+		for name := range query {
+			if p.isTrackingParam(name) {
+				query.Del(name)
+			}
+		}
+	}
+	u.RawQuery = query.Encode() // Encode trie les paramètres par nom
+	u.ForceQuery = false
+
+	return u.String(), nil
+}
+
+// isTrackingParam indique si un paramètre de requête est un paramètre de suivi.
+func (p URLPolicy) isTrackingParam(name string) bool {
+	name = strings.ToLower(name)
+	for _, param := range p.TrackingParams {
+		param = strings.ToLower(param)
+		if prefix, ok := strings.CutSuffix(param, "*"); ok {
+			if strings.HasPrefix(name, prefix) {
+				return true
+			}
+		} else if name == param {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/axellelanca/urlshortener/internal/repository"
+	"github.com/axellelanca/urlshortener/internal/testutil"
+)
+
+func TestNewURLPolicy(t *testing.T) {
+	tests := []struct {
+		policy  string
+		want    string
+		wantErr bool
+	}{
+		{"", DuplicatePolicyReject, false},
+		{DuplicatePolicyReject, DuplicatePolicyReject, false},
+		{DuplicatePolicyReturnExisting, DuplicatePolicyReturnExisting, false},
+		{DuplicatePolicyAlwaysNew, DuplicatePolicyAlwaysNew, false},
+		{"ignore", "", true},
+	}
+	for _, tt := range tests {
+		policy, err := NewURLPolicy(tt.policy, false, nil)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("NewURLPolicy(%q) = %v, erreur attendue: %t", tt.policy, err, tt.wantErr)
+			continue
+		}
+		if policy.DuplicatePolicy != tt.want {
+			t.Errorf("NewURLPolicy(%q).DuplicatePolicy = %q, attendu %q", tt.policy, policy.DuplicatePolicy, tt.want)
+		}
+	}
+}
+
+func TestCanonicalize(t *testing.T) {
+	keepTracking, err := NewURLPolicy("", false, nil)
+	if err != nil {
+		t.Fatalf("NewURLPolicy: %v", err)
+	}
+	stripTracking, err := NewURLPolicy("", true, nil)
+	if err != nil {
+		t.Fatalf("NewURLPolicy: %v", err)
+	}
+	customTracking, err := NewURLPolicy("", true, []string{"ref", "pk_*"})
+	if err != nil {
+		t.Fatalf("NewURLPolicy: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		policy URLPolicy
+		rawURL string
+		want   string
+	}{
+		{"schéma et hôte en minuscules", keepTracking, "HTTPS://Example.COM/Page", "https://example.com/Page"},
+		{"port par défaut retiré", keepTracking, "http://example.com:80/a", "http://example.com/a"},
+		{"port https par défaut retiré", keepTracking, "https://example.com:443/a", "https://example.com/a"},
+		{"autre port conservé", keepTracking, "https://example.com:8443/a", "https://example.com:8443/a"},
+		{"IPv6", keepTracking, "http://[2001:DB8::1]:80/", "http://[2001:db8::1]/"},
+		{"chemin vide", keepTracking, "https://example.com", "https://example.com/"},
+		{"slash final retiré", keepTracking, "https://example.com/docs//", "https://example.com/docs"},
+		{"paramètres triés", keepTracking, "https://example.com/?b=2&a=1", "https://example.com/?a=1&b=2"},
+		{"query vide retirée", keepTracking, "https://example.com/a?", "https://example.com/a"},
+		{"fragment conservé", keepTracking, "https://example.com/a#top", "https://example.com/a#top"},
+		{"suivi conservé sans strip", keepTracking, "https://example.com/?utm_source=x&id=1", "https://example.com/?id=1&utm_source=x"},
+		{"suivi retiré", stripTracking, "https://example.com/?utm_source=x&UTM_Medium=y&fbclid=z&id=1", "https://example.com/?id=1"},
+		{"paramètres de suivi personnalisés", customTracking, "https://example.com/?ref=a&pk_campaign=b&utm_source=c", "https://example.com/?utm_source=c"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.policy.Canonicalize(tt.rawURL)
+			if err != nil {
+				t.Fatalf("Canonicalize(%q): %v", tt.rawURL, err)
+			}
+			if got != tt.want {
+				t.Errorf("Canonicalize(%q) = %q, attendu %q", tt.rawURL, got, tt.want)
+			}
+		})
+	}
+
+	for _, rawURL := range []string{"/relatif", "example.com/page", "http://[::1"} {
+		if _, err := keepTracking.Canonicalize(rawURL); !errors.Is(err, ErrInvalidLongURL) {
+			t.Errorf("Canonicalize(%q) = %v, attendu ErrInvalidLongURL", rawURL, err)
+		}
+	}
+}
+
+func TestCreateLinkDuplicatePolicy(t *testing.T) {
+	tests := []struct {
+		policy      string
+		wantErr     error
+		wantCreated bool
+		wantSame    bool // Le lien existant est retourné
+	}{
+		{DuplicatePolicyReject, ErrURLAlreadyExists, false, false},
+		{DuplicatePolicyReturnExisting, nil, false, true},
+		{DuplicatePolicyAlwaysNew, nil, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			linkService := NewLinkService(repository.NewLinkRepository(testutil.NewDB(t)))
+			policy, err := NewURLPolicy(tt.policy, true, nil)
+			if err != nil {
+				t.Fatalf("NewURLPolicy: %v", err)
+			}
+			linkService.SetURLPolicy(policy)
+			first, _, err := linkService.CreateLink("https://example.com/page?utm_source=mail", CreateLinkOptions{})
+			if err != nil {
+				t.Fatalf("premier CreateLink: %v", err)
+			}
+
+			// Même URL sous forme canonique : hôte en majuscules, slash final, paramètre de suivi différent.
+			link, created, err := linkService.CreateLink("https://EXAMPLE.com/page/?utm_source=ads", CreateLinkOptions{})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("second CreateLink = %v, attendu %v", err, tt.wantErr)
+			}
+			if created != tt.wantCreated {
+				t.Errorf("created = %t, attendu %t", created, tt.wantCreated)
+			}
+			if link != nil && (link.ID == first.ID) != tt.wantSame {
+				t.Errorf("lien %d retourné (existant: %d), lien existant attendu: %t", link.ID, first.ID, tt.wantSame)
+			}
+		})
+	}
+}
@@ -0,0 +1,218 @@
+package services
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/axellelanca/urlshortener/internal/repository"
+)
+
+// Paramètres UTM gérés par le générateur, dans l'ordre où ils sont ajoutés à l'URL.
+const (
+	UTMSource   = "utm_source"
+	UTMMedium   = "utm_medium"
+	UTMCampaign = "utm_campaign"
+	UTMTerm     = "utm_term"
+	UTMContent  = "utm_content"
+)
+
+// maxUTMValueLength borne la longueur d'une valeur UTM.
+const maxUTMValueLength = 255
+
+// UTMParams regroupe les valeurs des paramètres UTM d'une URL. Une valeur vide n'est pas ajoutée.
+type UTMParams struct {
+	Source   string
+	Medium   string
+	Campaign string
+	Term     string
+	Content  string
+}
+
+// pairs retourne les paramètres UTM sous forme (nom, valeur), dans l'ordre des constantes UTM*.
+func (p UTMParams) pairs() [][2]string {
+	return [][2]string{
+		{UTMSource, p.Source},
+		{UTMMedium, p.Medium},
+		{UTMCampaign, p.Campaign},
+		{UTMTerm, p.Term},
+		{UTMContent, p.Content},
+	}
+}
+
+// IsEmpty indique si aucun paramètre UTM n'est renseigné.
+func (p UTMParams) IsEmpty() bool {
+	return p == UTMParams{}
+}
+
+// merge retourne p complété par les valeurs de base pour les paramètres que p ne renseigne pas.
+func (p UTMParams) merge(base UTMParams) UTMParams {
+	pick := func(value, fallback string) string {
+		if value = strings.TrimSpace(value); value != "" {
+			return value
+		}
+		return strings.TrimSpace(fallback)
+	}
+	return UTMParams{
+		Source:   pick(p.Source, base.Source),
+		Medium:   pick(p.Medium, base.Medium),
+		Campaign: pick(p.Campaign, base.Campaign),
+		Term:     pick(p.Term, base.Term),
+		Content:  pick(p.Content, base.Content),
+	}
+}
+
+// Get retourne la valeur du paramètre UTM nommé (UTMSource, UTMMedium...).
+func (p UTMParams) Get(name string) string {
+	for _, pair := range p.pairs() {
+		if pair[0] == name {
+			return pair[1]
+		}
+	}
+	return ""
+}
+
+// IsUTMParam indique si name est l'un des paramètres UTM gérés (utm_source, utm_medium...).
+func IsUTMParam(name string) bool {
+	for _, pair := range (UTMParams{}).pairs() {
+		if pair[0] == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ApplyUTM ajoute les paramètres UTM renseignés à la query string d'une URL.
+// La query existante est conservée telle quelle (ordre et encodage) : seuls les paramètres UTM fournis
+// remplacent une éventuelle valeur précédente du même nom. Le fragment (#...) reste en fin d'URL.
+func ApplyUTM(rawURL string, utm UTMParams) (string, error) {
+	if utm.IsEmpty() {
+		return rawURL, nil
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidLongURL, err)
+	}
+
+	replaced := make(map[string]bool)
+	var added []string
+	for _, pair := range utm.pairs() {
+		if pair[1] == "" {
+			continue
+		}
+		if utf8.RuneCountInString(pair[1]) > maxUTMValueLength {
+			return "", fmt.Errorf("%w: %s dépasse %d caractères", ErrInvalidUTM, pair[0], maxUTMValueLength)
+		}
+		replaced[pair[0]] = true
+		added = append(added, pair[0]+"="+url.QueryEscape(pair[1]))
+	}
+
+	var kept []string
+	if u.RawQuery != "" {
+		for _, part := range strings.Split(u.RawQuery, "&") {
+			name := part
+			if i := strings.IndexByte(part, '='); i >= 0 {
+				name = part[:i]
+			}
+			if unescaped, err := url.QueryUnescape(name); err == nil {
+				name = unescaped
+			}
+			if part != "" && !replaced[name] {
+				kept = append(kept, part)
+			}
+		}
+	}
+	u.RawQuery = strings.Join(append(kept, added...), "&")
+	return u.String(), nil
+}
+
+// ExtractUTM lit les paramètres UTM de la query string d'une URL. Une URL invalide donne des valeurs vides.
+func ExtractUTM(rawURL string) UTMParams {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return UTMParams{}
+	}
+	query := u.Query()
+	return UTMParams{
+		Source:   query.Get(UTMSource),
+		Medium:   query.Get(UTMMedium),
+		Campaign: query.Get(UTMCampaign),
+		Term:     query.Get(UTMTerm),
+		Content:  query.Get(UTMContent),
+	}
+}
+
+// resolveUTM combine le preset nommé (optionnel) et les valeurs explicites, qui ont priorité.
+func (s *LinkService) resolveUTM(presetName string, utm UTMParams) (UTMParams, error) {
+	if presetName == "" {
+		return utm.merge(UTMParams{}), nil
+	}
+	preset, ok := s.utmPresets[strings.ToLower(presetName)]
+	if !ok {
+		return UTMParams{}, fmt.Errorf("%w: preset '%s' inconnu", ErrInvalidUTM, presetName)
+	}
+	return utm.merge(preset), nil
+}
+
+// SetUTMPresets remplace les presets UTM utilisables à la création des liens (noms insensibles à la casse).
+func (s *LinkService) SetUTMPresets(presets map[string]UTMParams) {
+	s.utmPresets = make(map[string]UTMParams, len(presets))
+	for name, preset := range presets {
+		s.utmPresets[strings.ToLower(name)] = preset
+	}
+}
+
+// UTMPresetNames retourne les noms des presets UTM configurés, triés.
+func (s *LinkService) UTMPresetNames() []string {
+	names := make([]string, 0, len(s.utmPresets))
+	for name := range s.utmPresets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// UTMGroupStats est le nombre de liens et de clics partageant une même valeur d'un paramètre UTM.
+type UTMGroupStats struct {
+	Value       string // Valeur du paramètre, vide pour les liens qui ne l'ont pas
+	TotalLinks  int
+	TotalClicks int
+}
+
+// GetStatsByUTM regroupe les liens correspondant aux filtres de params par valeur du paramètre UTM utmParam
+// (utm_source, utm_medium...) de leur URL longue, et agrège leurs clics. Les groupes sont triés par clics décroissants.
+func (s *LinkService) GetStatsByUTM(params ListLinksParams, utmParam string) ([]UTMGroupStats, error) {
+	if !IsUTMParam(utmParam) {
+		return nil, fmt.Errorf("%w: regroupement '%s' inconnu (%s, %s, %s, %s ou %s)", ErrInvalidListParams,
+			utmParam, UTMSource, UTMMedium, UTMCampaign, UTMTerm, UTMContent)
+	}
+
+	groups := make(map[string]*UTMGroupStats)
+	err := s.eachListedLink(params, func(link repository.LinkWithClicks) {
+		value := ExtractUTM(link.LongURL).Get(utmParam)
+		group, ok := groups[value]
+		if !ok {
+			group = &UTMGroupStats{Value: value}
+			groups[value] = group
+		}
+		group.TotalLinks++
+		group.TotalClicks += link.TotalClicks
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]UTMGroupStats, 0, len(groups))
+	for _, group := range groups {
+		result = append(result, *group)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].TotalClicks != result[j].TotalClicks {
+			return result[i].TotalClicks > result[j].TotalClicks
+		}
+		return result[i].Value < result[j].Value
+	})
+	return result, nil
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestApplyUTM(t *testing.T) {
+	tests := []struct {
+		name   string
+		rawURL string
+		utm    UTMParams
+		want   string
+	}{
+		{"aucun paramètre", "https://example.com/?b=2&a=1", UTMParams{}, "https://example.com/?b=2&a=1"},
+		{"ajout dans l'ordre des paramètres", "https://example.com/page", UTMParams{Campaign: "soldes", Source: "mail"},
+			"https://example.com/page?utm_source=mail&utm_campaign=soldes"},
+		{"query existante conservée telle quelle", "https://example.com/?b=2&a=%2F", UTMParams{Source: "mail"},
+			"https://example.com/?b=2&a=%2F&utm_source=mail"},
+		{"valeur précédente remplacée", "https://example.com/?utm_source=old&id=1&utm_medium=web", UTMParams{Source: "mail"},
+			"https://example.com/?id=1&utm_medium=web&utm_source=mail"},
+		{"nom encodé remplacé", "https://example.com/?utm%5Fsource=old", UTMParams{Source: "mail"},
+			"https://example.com/?utm_source=mail"},
+		{"valeur encodée", "https://example.com/", UTMParams{Campaign: "été 2024 & co"},
+			"https://example.com/?utm_campaign=%C3%A9t%C3%A9+2024+%26+co"},
+		{"fragment en fin d'URL", "https://example.com/page#top", UTMParams{Medium: "qr"},
+			"https://example.com/page?utm_medium=qr#top"},
+		{"paramètres vides ignorés", "https://example.com/?&id=1&", UTMParams{Term: "chaussures"},
+			"https://example.com/?id=1&utm_term=chaussures"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ApplyUTM(tt.rawURL, tt.utm)
+			if err != nil {
+				t.Fatalf("ApplyUTM: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ApplyUTM(%q) = %q, attendu %q", tt.rawURL, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := ApplyUTM("https://example.com/", UTMParams{Source: strings.Repeat("é", maxUTMValueLength+1)}); !errors.Is(err, ErrInvalidUTM) {
+		t.Errorf("valeur trop longue: %v, attendu ErrInvalidUTM", err)
+	}
+}
+
+func TestResolveUTM(t *testing.T) {
+	linkService := NewLinkService(nil)
+	linkService.SetUTMPresets(map[string]UTMParams{
+		"Newsletter": {Source: "newsletter", Medium: "email", Campaign: "mensuelle"},
+	})
+
+	tests := []struct {
+		name    string
+		preset  string
+		utm     UTMParams
+		want    UTMParams
+		wantErr bool
+	}{
+		{"sans preset", "", UTMParams{Source: " mail "}, UTMParams{Source: "mail"}, false},
+		{"preset seul", "newsletter", UTMParams{}, UTMParams{Source: "newsletter", Medium: "email", Campaign: "mensuelle"}, false},
+		{"nom du preset insensible à la casse", "NEWSLETTER", UTMParams{}, UTMParams{Source: "newsletter", Medium: "email", Campaign: "mensuelle"}, false},
+		{
+			"valeurs explicites prioritaires", "newsletter", UTMParams{Campaign: "soldes", Content: "bouton"},
+			UTMParams{Source: "newsletter", Medium: "email", Campaign: "soldes", Content: "bouton"}, false,
+		},
+		{"valeur explicite blanche ignorée", "newsletter", UTMParams{Source: "  "}, UTMParams{Source: "newsletter", Medium: "email", Campaign: "mensuelle"}, false},
+		{"preset inconnu", "inconnu", UTMParams{}, UTMParams{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := linkService.resolveUTM(tt.preset, tt.utm)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveUTM = %v, erreur attendue: %t", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("resolveUTM = %+v, attendu %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractUTM(t *testing.T) {
+	got := ExtractUTM("https://example.com/?utm_source=mail&utm_campaign=%C3%A9t%C3%A9&id=1")
+	if want := (UTMParams{Source: "mail", Campaign: "été"}); got != want {
+		t.Errorf("ExtractUTM = %+v, attendu %+v", got, want)
+	}
+	if got := ExtractUTM("http://[::1"); !got.IsEmpty() {
+		t.Errorf("ExtractUTM(URL invalide) = %+v, attendu des valeurs vides", got)
+	}
+}
@@ -0,0 +1,33 @@
+// Package testutil regroupe les aides partagées par les tests des autres packages.
+package testutil
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/axellelanca/urlshortener/internal/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// NewDB ouvre une base SQLite fraîchement migrée dans un fichier temporaire, fermée à la fin du test.
+// Un fichier (et non une base en mémoire) est nécessaire pour que plusieurs connexions partagent les données ;
+// le busy_timeout fait attendre les écritures concurrentes au lieu d'échouer sur "database is locked".
+func NewDB(t testing.TB) *gorm.DB {
+	t.Helper()
+	dsn := "file:" + filepath.Join(t.TempDir(), "test.db") + "?_busy_timeout=5000"
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("ouverture de la base de test: %v", err)
+	}
+	if err := db.AutoMigrate(models.AllModels()...); err != nil {
+		t.Fatalf("migration de la base de test: %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return db
+}
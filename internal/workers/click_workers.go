@@ -28,6 +28,11 @@ func clickWorker(clickEventsChan <-chan models.ClickEvent, clickRepo repository.
 			Timestamp: event.Timestamp,
 			UserAgent: event.UserAgent,
 			IPAddress: event.IPAddress,
+			Device:    event.Device,
+			Country:   event.Country,
+			Variant:   event.Variant,
+			Language:  event.Language,
+			Source:    event.Source,
 		}
 
 		// DONE 2: Persister le clic en base de données via le 'clickRepo' (CreateClick).